@@ -0,0 +1,74 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const form = document.getElementById('otp-form');
+    const alertBox = document.getElementById('alert');
+    const currentInput = form.querySelector('[name="current"]');
+    const regenerateButton = document.getElementById('btn-regenerate');
+
+    function showError(msg) {
+        alertBox.textContent = msg;
+        alertBox.style.display = msg ? 'block' : 'none';
+    }
+
+    async function api(url, body) {
+        const res = await fetch(url, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(body),
+        });
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) throw new Error(data.error || ('요청 실패 (' + res.status + ')'));
+        return data;
+    }
+
+    // 새로 발급된 복구 코드 표시 (한 번만 노출됨)
+    function showCodes(codes) {
+        const box = document.getElementById('codes');
+        box.textContent = '';
+        codes.forEach(code => {
+            const div = document.createElement('div');
+            div.textContent = code;
+            box.appendChild(div);
+        });
+        document.getElementById('codes-box').style.display = 'block';
+    }
+
+    form.addEventListener('submit', async (e) => {
+        e.preventDefault();
+        try {
+            const data = await api('/api/account/otp', {
+                current: currentInput ? currentInput.value.trim() : '',
+                secret: form.querySelector('[name="secret"]').value,
+                token: form.querySelector('[name="token"]').value.trim(),
+            });
+            showError('');
+            form.style.display = 'none';
+            if (data.codes && data.codes.length) {
+                showCodes(data.codes);
+            } else {
+                alert('OTP가 재등록되었습니다. 이전 기기의 OTP는 더 이상 사용할 수 없습니다.');
+                location.href = '/';
+            }
+        } catch (err) {
+            showError(err.message);
+        }
+    });
+
+    if (regenerateButton) {
+        regenerateButton.addEventListener('click', async () => {
+            if (!currentInput.value.trim()) {
+                showError('현재 OTP 번호 또는 복구 코드를 입력하세요.');
+                currentInput.focus();
+                return;
+            }
+            if (!confirm('기존 복구 코드는 모두 폐기됩니다. 계속하시겠습니까?')) return;
+            try {
+                const data = await api('/api/account/recovery-codes', { current: currentInput.value.trim() });
+                showError('');
+                form.style.display = 'none';
+                showCodes(data.codes);
+            } catch (err) {
+                showError(err.message);
+            }
+        });
+    }
+});
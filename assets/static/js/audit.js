@@ -0,0 +1,130 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const alertBox = document.getElementById('alert');
+    const verifyBox = document.getElementById('verify-result');
+    const form = document.getElementById('filter-form');
+    const rows = document.getElementById('event-rows');
+    const moreBtn = document.getElementById('btn-more');
+    const problems = {
+        missing: '이벤트가 삭제되었거나 순서가 바뀌었습니다',
+        prev_hash: '이전 이벤트와의 연결이 끊어졌습니다',
+        hash: '이벤트 내용이 변경되었습니다',
+    };
+
+    let filter = null; // 현재 조회 조건
+    let lastID = 0;    // 다음 페이지 조회 기준
+
+    function showError(msg) {
+        alertBox.textContent = msg;
+        alertBox.style.display = msg ? 'block' : 'none';
+    }
+
+    async function api(url) {
+        const res = await fetch(url);
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) throw new Error(data.error || ('요청 실패 (' + res.status + ')'));
+        return data;
+    }
+
+    function cell(text) {
+        const td = document.createElement('td');
+        td.textContent = text;
+        return td;
+    }
+
+    // 조회 조건 생성 (날짜 입력은 브라우저 시간대 기준)
+    function readFilter() {
+        const params = new URLSearchParams();
+        ['user', 'ip', 'type'].forEach(name => {
+            const v = form.elements[name].value.trim();
+            if (v) params.set(name, v);
+        });
+        ['from', 'to'].forEach(name => {
+            const v = form.elements[name].value;
+            if (v) params.set(name, new Date(v).toISOString());
+        });
+        return params;
+    }
+
+    function formatDetails(details) {
+        const keys = Object.keys(details || {});
+        return keys.map(k => k + '=' + (typeof details[k] === 'string' ? details[k] : JSON.stringify(details[k]))).join(', ');
+    }
+
+    function render(events) {
+        events.forEach(e => {
+            const tr = document.createElement('tr');
+            const type = document.createElement('td');
+            const badge = document.createElement('span');
+            badge.className = 'badge' + (e.type === 'login.failure' ? ' fail' : '');
+            badge.textContent = e.type;
+            type.appendChild(badge);
+
+            const details = cell(formatDetails(e.details));
+            details.className = 'audit-details';
+            details.title = 'hash ' + e.hash;
+
+            tr.appendChild(cell(new Date(e.time).toLocaleString()));
+            tr.appendChild(type);
+            tr.appendChild(cell(e.username || '-'));
+            tr.appendChild(cell(e.clientIp || '-'));
+            tr.appendChild(cell(e.target || '-'));
+            tr.appendChild(details);
+            rows.appendChild(tr);
+            lastID = e.id;
+        });
+    }
+
+    async function load(append) {
+        const params = new URLSearchParams(filter);
+        params.set('limit', '100');
+        if (append) params.set('before', lastID);
+
+        try {
+            const data = await api('/api/audit/events?' + params.toString());
+            if (!append) rows.innerHTML = '';
+            render(data.events);
+            if (!append && data.events.length === 0) {
+                const tr = document.createElement('tr');
+                const td = cell('조건에 맞는 이벤트가 없습니다.');
+                td.className = 'empty';
+                td.colSpan = 6;
+                tr.appendChild(td);
+                rows.appendChild(tr);
+            }
+            moreBtn.style.display = data.more ? 'block' : 'none';
+            showError('');
+        } catch (err) {
+            showError(err.message);
+        }
+    }
+
+    form.addEventListener('submit', (e) => {
+        e.preventDefault();
+        filter = readFilter();
+        load(false);
+    });
+
+    moreBtn.addEventListener('click', () => load(true));
+
+    document.getElementById('btn-verify').addEventListener('click', async () => {
+        try {
+            const res = await api('/api/audit/verify');
+            if (res.ok) {
+                verifyBox.className = 'alert ok';
+                verifyBox.textContent = '이벤트 ' + res.count + '건의 해시 체인이 온전합니다.' +
+                    (res.lastHash ? ' 마지막 해시: ' + res.lastHash : '');
+            } else {
+                verifyBox.className = 'alert error';
+                verifyBox.textContent = '이벤트 #' + res.brokenId + '에서 해시 체인이 끊어졌습니다: ' +
+                    (problems[res.problem] || res.problem) + '. (앞선 ' + res.count + '건은 정상)';
+            }
+            verifyBox.style.display = 'block';
+            showError('');
+        } catch (err) {
+            showError(err.message);
+        }
+    });
+
+    filter = readFilter();
+    load(false);
+});
@@ -0,0 +1,47 @@
+// CSRF 토큰 자동 첨부 (다른 스크립트보다 먼저 로드)
+// - fetch: 같은 출처로 보내는 GET/HEAD 외 요청에 X-CSRF-Token 헤더 추가
+// - 폼 전송: POST 폼에 csrf_token 필드 추가
+// - 로그아웃 링크: csrf 쿼리 추가
+(function () {
+    function csrfToken() {
+        const match = document.cookie.match(/(?:^|;\s*)rootweb_csrf=([^;]*)/);
+        return match ? decodeURIComponent(match[1]) : '';
+    }
+    // 웹소켓 연결 주소에 토큰을 붙일 때 사용
+    window.csrfToken = csrfToken;
+
+    const nativeFetch = window.fetch.bind(window);
+    window.fetch = (input, init = {}) => {
+        const request = input instanceof Request ? input : null;
+        const method = (init.method || (request ? request.method : 'GET')).toUpperCase();
+        const url = new URL(request ? request.url : input, window.location.href);
+
+        if (method !== 'GET' && method !== 'HEAD' && url.origin === window.location.origin) {
+            const headers = new Headers(init.headers || (request ? request.headers : undefined));
+            headers.set('X-CSRF-Token', csrfToken());
+            init = Object.assign({}, init, { headers: headers });
+        }
+        return nativeFetch(input, init);
+    };
+
+    document.addEventListener('submit', (e) => {
+        const form = e.target;
+        if (form.method.toUpperCase() !== 'POST') return;
+
+        let field = form.querySelector('input[name="csrf_token"]');
+        if (!field) {
+            field = document.createElement('input');
+            field.type = 'hidden';
+            field.name = 'csrf_token';
+            form.appendChild(field);
+        }
+        field.value = csrfToken();
+    }, true);
+
+    document.addEventListener('click', (e) => {
+        const link = e.target.closest && e.target.closest('a[href^="/logout"]');
+        if (link) {
+            link.href = '/logout?csrf=' + encodeURIComponent(csrfToken());
+        }
+    }, true);
+})();
@@ -0,0 +1,330 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const card = document.getElementById('file-card');
+    const rows = document.getElementById('file-rows');
+    const alertBox = document.getElementById('alert');
+    const pathForm = document.getElementById('path-form');
+    const pathInput = pathForm.querySelector('[name="path"]');
+    const uploadInput = document.getElementById('upload-input');
+    const uploads = document.getElementById('uploads');
+    const chunkSize = parseInt(card.dataset.chunkSize, 10) || 8 * 1024 * 1024;
+
+    // 업로드 요청 실패 시 재시도 횟수
+    const maxRetries = 5;
+
+    let cwd = new URLSearchParams(location.search).get('path') || '';
+    let home = '/';
+
+    function showError(msg) {
+        alertBox.textContent = msg;
+        alertBox.style.display = msg ? 'block' : 'none';
+    }
+
+    function formatTime(value) {
+        return value ? new Date(value).toLocaleString() : '-';
+    }
+
+    function formatSize(bytes) {
+        const units = ['B', 'KB', 'MB', 'GB', 'TB'];
+        let i = 0;
+        while (bytes >= 1024 && i < units.length - 1) {
+            bytes /= 1024;
+            i++;
+        }
+        return (i === 0 ? bytes : bytes.toFixed(1)) + ' ' + units[i];
+    }
+
+    function joinPath(dir, name) {
+        return (dir.endsWith('/') ? dir : dir + '/') + name;
+    }
+
+    function parentPath(path) {
+        const idx = path.replace(/\/+$/, '').lastIndexOf('/');
+        return idx <= 0 ? '/' : path.substring(0, idx);
+    }
+
+    // 입력한 이름을 현재 디렉터리 기준 절대 경로로 변환
+    function resolvePath(name) {
+        return name.startsWith('/') ? name : joinPath(cwd, name);
+    }
+
+    function cell(content) {
+        const td = document.createElement('td');
+        if (content instanceof Node) td.appendChild(content);
+        else td.textContent = content;
+        return td;
+    }
+
+    function actionLink(text, handler) {
+        const a = document.createElement('a');
+        a.href = '#';
+        a.textContent = text;
+        a.style.marginRight = '12px';
+        a.addEventListener('click', (e) => {
+            e.preventDefault();
+            handler();
+        });
+        return a;
+    }
+
+    async function request(url, options) {
+        const res = await fetch(url, options);
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) {
+            throw new Error(data.error || ('요청 실패 (' + res.status + ')'));
+        }
+        return data;
+    }
+
+    function postJSON(url, body) {
+        return request(url, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(body),
+        });
+    }
+
+    async function run(action) {
+        try {
+            await action();
+            showError('');
+            load(cwd);
+        } catch (err) {
+            showError(err.message);
+        }
+    }
+
+    async function load(path) {
+        let data;
+        try {
+            data = await request('/api/files' + (path ? '?path=' + encodeURIComponent(path) : ''));
+        } catch (err) {
+            showError(err.message);
+            return;
+        }
+
+        cwd = data.path;
+        home = data.home;
+        pathInput.value = cwd;
+        document.getElementById('unix-user').textContent = data.unixUser;
+        // 재인증 후 같은 디렉터리로 돌아오도록 주소에 경로 유지
+        history.replaceState(null, '', '/files?path=' + encodeURIComponent(cwd));
+
+        rows.innerHTML = '';
+        if (data.entries.length === 0) {
+            const tr = document.createElement('tr');
+            const td = cell('비어 있는 디렉터리입니다.');
+            td.colSpan = 6;
+            td.className = 'empty';
+            tr.appendChild(td);
+            rows.appendChild(tr);
+            return;
+        }
+
+        data.entries.forEach(entry => rows.appendChild(entryRow(entry)));
+    }
+
+    function entryRow(entry) {
+        const path = joinPath(cwd, entry.name);
+        const tr = document.createElement('tr');
+
+        let name;
+        if (entry.isDir) {
+            name = actionLink(entry.name + '/', () => load(path));
+        } else {
+            name = document.createElement('span');
+            name.textContent = entry.name;
+        }
+        if (entry.type === 'symlink') {
+            name.textContent += ' → ' + entry.target;
+        }
+        const nameCell = cell(name);
+        nameCell.className = 'ellipsis';
+        nameCell.title = entry.name;
+        tr.appendChild(nameCell);
+
+        tr.appendChild(cell(entry.type === 'file' ? formatSize(entry.size) : '-'));
+        const mode = cell(entry.mode + ' (' + entry.perm + ')');
+        mode.style.fontFamily = 'ui-monospace, SFMono-Regular, Menlo, Consolas, monospace';
+        tr.appendChild(mode);
+        tr.appendChild(cell(entry.owner + ':' + entry.group));
+        tr.appendChild(cell(formatTime(entry.modTime)));
+
+        const actions = document.createElement('span');
+        if (entry.type === 'file') {
+            actions.appendChild(actionLink('다운로드', () => download(path)));
+        }
+        actions.appendChild(actionLink('이름 변경', () => {
+            const to = prompt('새 이름 또는 이동할 경로', entry.name);
+            if (!to || to === entry.name) return;
+            run(() => postJSON('/api/files/rename', { path: path, to: resolvePath(to) }));
+        }));
+        actions.appendChild(actionLink('권한', () => {
+            const mode = prompt('권한 (8진수, 예: 0644)', entry.perm);
+            if (!mode || mode === entry.perm) return;
+            run(() => postJSON('/api/files/chmod', { path: path, mode: mode.trim() }));
+        }));
+        actions.appendChild(actionLink('삭제', () => {
+            const recursive = entry.type === 'dir';
+            const msg = recursive
+                ? entry.name + ' 디렉터리와 그 안의 모든 항목을 삭제합니다.'
+                : entry.name + ' 항목을 삭제합니다.';
+            if (!confirm(msg)) return;
+            run(() => request('/api/files?path=' + encodeURIComponent(path) + (recursive ? '&recursive=1' : ''),
+                { method: 'DELETE' }));
+        }));
+        tr.appendChild(cell(actions));
+        return tr;
+    }
+
+    // 다운로드는 브라우저가 직접 받도록 링크로 이동 (중단 시 브라우저가 Range 요청으로 이어받기)
+    async function download(path) {
+        if (!(await RootWebStepUp.ensure())) return;
+        const a = document.createElement('a');
+        a.href = '/api/files/download?path=' + encodeURIComponent(path);
+        a.download = '';
+        document.body.appendChild(a);
+        a.click();
+        a.remove();
+    }
+
+    function uploadRow(name) {
+        const row = document.createElement('div');
+        row.className = 'upload-item';
+        const label = document.createElement('span');
+        label.textContent = name;
+        const bar = document.createElement('progress');
+        bar.max = 1;
+        bar.value = 0;
+        const status = document.createElement('span');
+        const cancel = document.createElement('a');
+        cancel.href = '#';
+        cancel.textContent = '취소';
+        row.append(label, bar, status, cancel);
+        uploads.appendChild(row);
+        return { row, bar, status, cancel };
+    }
+
+    // 파일을 chunkSize 단위로 나누어 전송 (중단된 업로드는 서버에 기록된 위치부터 이어서 전송)
+    async function upload(file) {
+        const dir = cwd;
+        const path = joinPath(dir, file.name);
+        const url = '/api/files/upload?path=' + encodeURIComponent(path);
+        const ui = uploadRow(file.name);
+        let canceled = false;
+        ui.cancel.addEventListener('click', (e) => {
+            e.preventDefault();
+            canceled = true;
+        });
+
+        const progress = (offset) => {
+            ui.bar.value = file.size ? offset / file.size : 1;
+            ui.status.textContent = formatSize(offset) + ' / ' + formatSize(file.size);
+        };
+
+        try {
+            const status = await request(url);
+            const overwrite = status.exists;
+            if (overwrite && !confirm(file.name + ' 파일이 이미 있습니다. 덮어쓰시겠습니까?')) {
+                ui.row.remove();
+                return;
+            }
+
+            let offset = 0;
+            if (status.offset > 0 && status.offset <= file.size &&
+                confirm(file.name + ' 파일의 중단된 업로드가 있습니다. (' + formatSize(status.offset) + ')\n이어서 전송하시겠습니까?')) {
+                offset = status.offset;
+            }
+            progress(offset);
+
+            let retries = 0;
+            do {
+                if (canceled) {
+                    await request(url, { method: 'DELETE' });
+                    ui.row.remove();
+                    return;
+                }
+
+                let res = null;
+                try {
+                    res = await fetch(url + '&offset=' + offset, {
+                        method: 'PUT',
+                        headers: { 'Content-Type': 'application/octet-stream' },
+                        body: file.slice(offset, offset + chunkSize),
+                    });
+                } catch (err) {
+                    // 네트워크 오류는 재시도
+                }
+
+                if (res && res.ok) {
+                    offset = (await res.json()).offset;
+                    retries = 0;
+                    progress(offset);
+                    continue;
+                }
+                if (res && res.status !== 409 && res.status < 500) {
+                    const data = await res.json().catch(() => ({}));
+                    throw new Error(data.error || ('요청 실패 (' + res.status + ')'));
+                }
+                if (++retries > maxRetries) {
+                    throw new Error('전송이 반복해서 실패했습니다. 같은 파일을 다시 선택하면 이어서 전송합니다.');
+                }
+
+                // 서버에 기록된 위치를 다시 확인한 뒤 재전송
+                await new Promise(resolve => setTimeout(resolve, 1000 * retries));
+                offset = (await request(url)).offset;
+            } while (offset < file.size);
+
+            await postJSON('/api/files/upload/complete', { path: path, overwrite: overwrite });
+            ui.row.remove();
+            if (dir === cwd) load(cwd);
+        } catch (err) {
+            ui.status.textContent = err.message;
+            ui.cancel.textContent = '닫기';
+            ui.cancel.onclick = (e) => {
+                e.preventDefault();
+                ui.row.remove();
+            };
+        }
+    }
+
+    async function uploadFiles(files) {
+        if (files.length === 0 || !(await RootWebStepUp.ensure())) return;
+        for (const file of files) {
+            await upload(file);
+        }
+    }
+
+    pathForm.addEventListener('submit', (e) => {
+        e.preventDefault();
+        load(pathInput.value.trim());
+    });
+
+    document.getElementById('btn-up').addEventListener('click', () => load(parentPath(cwd)));
+    document.getElementById('btn-home').addEventListener('click', () => load(home));
+    document.getElementById('btn-mkdir').addEventListener('click', () => {
+        const name = prompt('새 디렉터리 이름');
+        if (!name) return;
+        run(() => postJSON('/api/files/mkdir', { path: resolvePath(name.trim()) }));
+    });
+    document.getElementById('btn-upload').addEventListener('click', () => uploadInput.click());
+    uploadInput.addEventListener('change', () => {
+        const files = Array.from(uploadInput.files);
+        uploadInput.value = '';
+        uploadFiles(files);
+    });
+
+    card.addEventListener('dragover', (e) => {
+        e.preventDefault();
+        card.classList.add('drop-active');
+    });
+    card.addEventListener('dragleave', (e) => {
+        if (!card.contains(e.relatedTarget)) card.classList.remove('drop-active');
+    });
+    card.addEventListener('drop', (e) => {
+        e.preventDefault();
+        card.classList.remove('drop-active');
+        uploadFiles(Array.from(e.dataTransfer.files));
+    });
+
+    load(cwd);
+});
@@ -9,7 +9,9 @@ document.addEventListener('DOMContentLoaded', () => {
     function updateSubmit() {
         const usernameOk = usernameInput.value.trim().length > 0;
         const passwordOk = passwordInput.value.length > 0;
-        const otpOk = otpInput.value.trim().length === 6; // 6자리일 때만
+        const otp = otpInput.value.trim();
+        // 미입력(최초 로그인, 패스키), 6자리 OTP 또는 복구 코드(xxxx-xxxx-xxxx)
+        const otpOk = otp.length === 0 || /^[0-9]{6}$/.test(otp) || /^[a-z2-7]{4}-?[a-z2-7]{4}-?[a-z2-7]{4}$/i.test(otp);
         submitButton.disabled = !(usernameOk && passwordOk && otpOk);
     }
 
@@ -17,8 +19,13 @@ document.addEventListener('DOMContentLoaded', () => {
     passwordInput.addEventListener('input', updateSubmit);
 
     otpInput.addEventListener('input', () => {
-        // 숫자만 + 최대 6자리
-        otpInput.value = otpInput.value.replace(/[^0-9]/g, '').slice(0, 6);
+        // 숫자만 입력하면 OTP(최대 6자리), 영문이 포함되면 복구 코드로 취급
+        const value = otpInput.value;
+        if (/^[0-9]*$/.test(value)) {
+            otpInput.value = value.slice(0, 6);
+        } else {
+            otpInput.value = value.replace(/[^a-zA-Z0-9-]/g, '').slice(0, 14);
+        }
         updateSubmit();
     });
 
@@ -0,0 +1,19 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const alertBox = document.getElementById('alert');
+
+    document.querySelectorAll('[data-unlock]').forEach((a) => {
+        a.addEventListener('click', async (e) => {
+            e.preventDefault();
+            if (!confirm('로그인 제한을 해제하시겠습니까?')) return;
+
+            const res = await fetch('/api/login-throttles/' + a.dataset.unlock, { method: 'DELETE' });
+            if (!res.ok) {
+                const data = await res.json().catch(() => ({}));
+                alertBox.textContent = data.error || ('요청 실패 (' + res.status + ')');
+                alertBox.style.display = 'block';
+                return;
+            }
+            location.reload();
+        });
+    });
+});
@@ -0,0 +1,23 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const alertBox = document.getElementById('alert');
+
+    document.querySelectorAll('[data-revoke]').forEach((a) => {
+        a.addEventListener('click', async (e) => {
+            e.preventDefault();
+            const current = a.dataset.current === 'true';
+            const msg = current
+                ? '현재 사용 중인 세션입니다. 종료하면 로그아웃됩니다. 계속하시겠습니까?'
+                : '세션을 종료하시겠습니까? 해당 세션에서 열린 터미널 연결도 끊어집니다.';
+            if (!confirm(msg)) return;
+
+            const res = await fetch('/api/login-sessions/' + a.dataset.revoke, { method: 'DELETE' });
+            if (!res.ok) {
+                const data = await res.json().catch(() => ({}));
+                alertBox.textContent = data.error || ('요청 실패 (' + res.status + ')');
+                alertBox.style.display = 'block';
+                return;
+            }
+            location.href = current ? '/login' : location.href;
+        });
+    });
+});
@@ -0,0 +1,31 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const alertBox = document.getElementById('alert');
+    const buttons = document.querySelectorAll('[data-passkey]');
+
+    function showError(msg) {
+        alertBox.textContent = msg;
+        alertBox.style.display = msg ? 'block' : 'none';
+    }
+
+    if (!RootWebAuthn.supported) {
+        showError('이 브라우저는 패스키를 지원하지 않습니다. OTP로 로그인하세요.');
+        buttons.forEach(b => { b.disabled = true; });
+        return;
+    }
+
+    buttons.forEach(button => {
+        button.addEventListener('click', async () => {
+            const id = button.dataset.passkey;
+            const query = id ? '?id=' + encodeURIComponent(id) : '';
+            buttons.forEach(b => { b.disabled = true; });
+            showError('');
+            try {
+                const data = await RootWebAuthn.authenticate('/login/passkey/begin' + query, '/login/passkey/finish');
+                location.href = data.redirect || '/';
+            } catch (err) {
+                showError(err.name === 'NotAllowedError' ? '패스키 인증이 취소되었습니다.' : err.message);
+                buttons.forEach(b => { b.disabled = false; });
+            }
+        });
+    });
+});
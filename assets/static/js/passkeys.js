@@ -0,0 +1,87 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const rows = document.getElementById('passkey-rows');
+    const alertBox = document.getElementById('alert');
+    const form = document.getElementById('register-form');
+
+    function showError(msg) {
+        alertBox.textContent = msg;
+        alertBox.style.display = msg ? 'block' : 'none';
+    }
+
+    function formatTime(value) {
+        return value ? new Date(value).toLocaleString() : '-';
+    }
+
+    function cell(content) {
+        const td = document.createElement('td');
+        if (content instanceof Node) td.appendChild(content);
+        else td.textContent = content;
+        return td;
+    }
+
+    async function load() {
+        const res = await fetch('/api/passkeys');
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) {
+            showError(data.error || '패스키 목록 조회 실패');
+            return;
+        }
+
+        rows.innerHTML = '';
+        if (data.passkeys.length === 0) {
+            const tr = document.createElement('tr');
+            const td = cell('등록된 패스키가 없습니다.');
+            td.colSpan = 4;
+            td.className = 'empty';
+            tr.appendChild(td);
+            rows.appendChild(tr);
+            return;
+        }
+
+        data.passkeys.forEach(pk => {
+            const tr = document.createElement('tr');
+            tr.appendChild(cell(pk.name));
+            tr.appendChild(cell(formatTime(pk.createdAt)));
+            tr.appendChild(cell(formatTime(pk.lastUsedAt)));
+
+            const del = document.createElement('a');
+            del.href = '#';
+            del.textContent = '삭제';
+            del.addEventListener('click', async (e) => {
+                e.preventDefault();
+                if (!confirm(pk.name + ' 패스키를 삭제합니다.')) return;
+                const res = await fetch('/api/passkeys/' + pk.id, { method: 'DELETE' });
+                if (!res.ok) {
+                    const data = await res.json().catch(() => ({}));
+                    showError(data.error || ('요청 실패 (' + res.status + ')'));
+                    return;
+                }
+                showError('');
+                load();
+            });
+            tr.appendChild(cell(del));
+            rows.appendChild(tr);
+        });
+    }
+
+    form.addEventListener('submit', async (e) => {
+        e.preventDefault();
+        if (!RootWebAuthn.supported) {
+            showError('이 브라우저는 패스키를 지원하지 않습니다.');
+            return;
+        }
+
+        const name = form.querySelector('[name="name"]').value.trim();
+        try {
+            await RootWebAuthn.register('/api/passkeys/register/begin',
+                '/api/passkeys/register/finish?name=' + encodeURIComponent(name));
+            form.reset();
+            showError('');
+        } catch (err) {
+            showError(err.name === 'NotAllowedError' ? '패스키 등록이 취소되었습니다.' : err.message);
+        }
+        load();
+    });
+
+    load();
+});
@@ -0,0 +1,19 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const alertBox = document.getElementById('alert');
+
+    document.querySelectorAll('[data-terminate]').forEach((a) => {
+        a.addEventListener('click', async (e) => {
+            e.preventDefault();
+            if (!confirm('세션을 종료하시겠습니까? 실행 중인 프로그램도 함께 종료됩니다.')) return;
+
+            const res = await fetch('/api/terminal/sessions/' + a.dataset.terminate, { method: 'DELETE' });
+            if (!res.ok) {
+                const data = await res.json().catch(() => ({}));
+                alertBox.textContent = data.error || ('요청 실패 (' + res.status + ')');
+                alertBox.style.display = 'block';
+                return;
+            }
+            location.reload();
+        });
+    });
+});
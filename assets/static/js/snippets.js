@@ -0,0 +1,354 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const alertBox = document.getElementById('alert');
+    const form = document.getElementById('snippet-form');
+    const snippetRows = document.getElementById('snippet-rows');
+    const runRows = document.getElementById('run-rows');
+    const runOutput = document.getElementById('run-output');
+    const usePanel = document.getElementById('use-panel');
+    const useParams = document.getElementById('use-params');
+    const useSession = document.getElementById('use-session');
+    const useSubmit = document.getElementById('btn-use-submit');
+    const statusLabels = {
+        running: '실행 중',
+        succeeded: '성공',
+        failed: '실패',
+        timeout: '시간 초과',
+        canceled: '취소됨',
+        interrupted: '중단됨',
+    };
+
+    let editing = null; // 수정 중인 명령
+    let using = null;   // 실행 또는 전송할 명령과 방식
+    let shownRun = null;
+    let pollTimer = null;
+
+    function showError(msg) {
+        alertBox.textContent = msg;
+        alertBox.style.display = msg ? 'block' : 'none';
+    }
+
+    async function api(method, url, body) {
+        const res = await fetch(url, {
+            method: method,
+            headers: { 'Content-Type': 'application/json' },
+            body: body ? JSON.stringify(body) : undefined,
+        });
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) {
+            const err = new Error(data.error || ('요청 실패 (' + res.status + ')'));
+            err.status = res.status;
+            throw err;
+        }
+        return data;
+    }
+
+    function cell(content) {
+        const td = document.createElement('td');
+        if (content instanceof Node) td.appendChild(content);
+        else td.textContent = content;
+        return td;
+    }
+
+    function link(label, onClick) {
+        const a = document.createElement('a');
+        a.href = '#';
+        a.textContent = label;
+        a.style.marginRight = '12px';
+        a.addEventListener('click', (e) => {
+            e.preventDefault();
+            onClick();
+        });
+        return a;
+    }
+
+    function emptyRow(tbody, text, colSpan) {
+        const tr = document.createElement('tr');
+        const td = cell(text);
+        td.className = 'empty';
+        td.colSpan = colSpan;
+        tr.appendChild(td);
+        tbody.appendChild(tr);
+    }
+
+    // --- 명령 목록 ---
+    function renderSnippets(snippets) {
+        snippetRows.innerHTML = '';
+        if (snippets.length === 0) {
+            emptyRow(snippetRows, '저장된 명령이 없습니다.', 4);
+            return;
+        }
+
+        snippets.forEach(s => {
+            const tr = document.createElement('tr');
+
+            const name = document.createElement('div');
+            name.textContent = s.name;
+            if (s.description) {
+                const desc = document.createElement('div');
+                desc.className = 'field-hint';
+                desc.style.margin = '4px 0 0';
+                desc.textContent = s.description;
+                name.appendChild(desc);
+            }
+
+            const code = document.createElement('code');
+            code.textContent = s.command;
+            const cmdCell = cell(code);
+            cmdCell.className = 'ellipsis';
+            cmdCell.style.maxWidth = '360px';
+            cmdCell.title = s.command;
+
+            const owner = document.createElement('span');
+            owner.textContent = s.owner || '-';
+            if (s.shared) {
+                const badge = document.createElement('span');
+                badge.className = 'badge ok';
+                badge.style.marginLeft = '6px';
+                badge.textContent = '공유';
+                owner.appendChild(badge);
+            }
+
+            const actions = document.createElement('span');
+            actions.appendChild(link('실행', () => openUse(s, 'run')));
+            actions.appendChild(link('터미널로 보내기', () => openUse(s, 'send')));
+            if (s.editable) {
+                actions.appendChild(link('수정', () => startEdit(s)));
+                actions.appendChild(link('삭제', async () => {
+                    if (!confirm(s.name + ' 명령을 삭제합니다.')) return;
+                    try {
+                        await api('DELETE', '/api/snippets/' + s.id);
+                        showError('');
+                    } catch (err) {
+                        showError(err.message);
+                    }
+                    loadSnippets();
+                }));
+            }
+
+            tr.appendChild(cell(name));
+            tr.appendChild(cmdCell);
+            tr.appendChild(cell(owner));
+            tr.appendChild(cell(actions));
+            snippetRows.appendChild(tr);
+        });
+    }
+
+    async function loadSnippets() {
+        try {
+            const data = await api('GET', '/api/snippets');
+            renderSnippets(data.snippets);
+            return data.snippets;
+        } catch (err) {
+            showError(err.message);
+            return null;
+        }
+    }
+
+    // --- 저장 및 수정 ---
+    function startEdit(s) {
+        editing = s;
+        form.elements.name.value = s.name;
+        form.elements.description.value = s.description || '';
+        form.elements.command.value = s.command;
+        form.elements.shared.checked = s.shared;
+        document.getElementById('btn-save').textContent = '수정 저장';
+        document.getElementById('btn-cancel-edit').style.display = '';
+        form.elements.name.focus();
+    }
+
+    function stopEdit() {
+        editing = null;
+        form.reset();
+        document.getElementById('btn-save').textContent = '명령 저장';
+        document.getElementById('btn-cancel-edit').style.display = 'none';
+    }
+
+    form.addEventListener('submit', async (e) => {
+        e.preventDefault();
+        const body = {
+            name: form.elements.name.value,
+            description: form.elements.description.value,
+            command: form.elements.command.value,
+            shared: form.elements.shared.checked,
+        };
+        try {
+            if (editing) await api('PATCH', '/api/snippets/' + editing.id, body);
+            else await api('POST', '/api/snippets', body);
+            stopEdit();
+            showError('');
+        } catch (err) {
+            showError(err.message);
+        }
+        loadSnippets();
+    });
+
+    document.getElementById('btn-cancel-edit').addEventListener('click', stopEdit);
+
+    // --- 실행 및 터미널 전송 ---
+    async function openUse(s, mode) {
+        using = { snippet: s, mode: mode };
+        document.getElementById('use-title').textContent = s.name;
+        useSubmit.textContent = mode === 'run' ? '실행' : '보내기';
+
+        useParams.innerHTML = '';
+        s.params.forEach(p => {
+            const row = document.createElement('div');
+            row.className = 'panel-row';
+            const label = document.createElement('label');
+            label.textContent = p;
+            const input = document.createElement('input');
+            input.type = 'text';
+            input.dataset.param = p;
+            input.spellcheck = false;
+            row.append(label, input);
+            useParams.appendChild(row);
+        });
+
+        document.getElementById('use-send').style.display = mode === 'send' ? '' : 'none';
+        if (mode === 'send') {
+            useSession.innerHTML = '';
+            try {
+                const sessions = (await api('GET', '/api/terminal/sessions')).filter(t => t.own && !t.locked);
+                sessions.forEach(t => {
+                    const opt = document.createElement('option');
+                    opt.value = t.id;
+                    opt.textContent = t.unixUser + ' · ' + new Date(t.startedAt).toLocaleString() +
+                        (t.attached ? '' : ' (분리됨)');
+                    useSession.appendChild(opt);
+                });
+                if (sessions.length === 0) {
+                    const opt = document.createElement('option');
+                    opt.value = '';
+                    opt.textContent = '열려 있는 터미널이 없습니다.';
+                    useSession.appendChild(opt);
+                }
+            } catch (err) {
+                showError(err.message);
+            }
+        }
+
+        usePanel.style.display = 'block';
+        const first = useParams.querySelector('input');
+        if (first) first.focus();
+    }
+
+    function closeUse() {
+        using = null;
+        usePanel.style.display = 'none';
+    }
+
+    document.getElementById('btn-use-cancel').addEventListener('click', closeUse);
+
+    useSubmit.addEventListener('click', async () => {
+        if (!using) return;
+        const params = {};
+        useParams.querySelectorAll('input').forEach(i => { params[i.dataset.param] = i.value; });
+        const s = using.snippet;
+
+        try {
+            if (using.mode === 'run') {
+                const data = await api('POST', '/api/snippets/' + s.id + '/run', { command: s.command, params: params });
+                shownRun = data.run.id;
+                runOutput.textContent = '';
+                runOutput.style.display = 'none';
+                loadRuns();
+            } else {
+                if (!useSession.value) throw new Error('명령을 보낼 터미널 세션을 선택하세요.');
+                await api('POST', '/api/snippets/' + s.id + '/send', {
+                    command: s.command,
+                    params: params,
+                    session: useSession.value,
+                    execute: document.getElementById('use-execute').checked,
+                });
+            }
+            closeUse();
+            showError('');
+        } catch (err) {
+            showError(err.message);
+            // 확인한 뒤 명령이 바뀌었으면 실행하지 않고 바뀐 명령을 목록에 다시 표시
+            if (err.status === 409) {
+                const list = await loadSnippets();
+                const current = list && list.find(x => x.id === s.id);
+                if (!current || current.command !== s.command) closeUse();
+            }
+        }
+    });
+
+    // --- 실행 기록 ---
+    function statusBadge(run) {
+        const badge = document.createElement('span');
+        badge.className = 'badge' + (run.status === 'succeeded' ? ' ok' : (run.status === 'running' ? '' : ' fail'));
+        let text = statusLabels[run.status] || run.status;
+        if (run.signal) text += ' (' + run.signal + ')';
+        else if (run.exitCode !== undefined && run.status !== 'succeeded') text += ' (코드 ' + run.exitCode + ')';
+        badge.textContent = text;
+        return badge;
+    }
+
+    function renderRuns(runs) {
+        runRows.innerHTML = '';
+        const colSpan = isAdmin ? 6 : 5;
+        if (runs.length === 0) {
+            emptyRow(runRows, '실행 기록이 없습니다.', colSpan);
+            return;
+        }
+
+        runs.forEach(run => {
+            const tr = document.createElement('tr');
+            const actions = document.createElement('span');
+            actions.appendChild(link('출력 보기', () => showRun(run.id)));
+            if (run.status === 'running') {
+                actions.appendChild(link('취소', async () => {
+                    try {
+                        await api('POST', '/api/snippet-runs/' + run.id + '/cancel');
+                        showError('');
+                    } catch (err) {
+                        showError(err.message);
+                    }
+                    loadRuns();
+                }));
+            }
+
+            const name = cell(run.snippetName);
+            name.title = run.command;
+            tr.appendChild(cell(new Date(run.startedAt).toLocaleString()));
+            tr.appendChild(name);
+            if (isAdmin) tr.appendChild(cell(run.username));
+            tr.appendChild(cell(run.unixUser));
+            tr.appendChild(cell(statusBadge(run)));
+            tr.appendChild(cell(actions));
+            runRows.appendChild(tr);
+        });
+    }
+
+    async function showRun(id) {
+        try {
+            const run = (await api('GET', '/api/snippet-runs/' + id)).run;
+            shownRun = id;
+            let text = '$ ' + run.command + '\n' + (run.output || '');
+            if (run.truncated) text += '\n[출력이 최대 크기를 넘어 나머지는 저장되지 않았습니다.]';
+            if (run.status === 'running') text += '\n[실행 중...]';
+            runOutput.textContent = text;
+            runOutput.style.display = 'block';
+        } catch (err) {
+            showError(err.message);
+        }
+    }
+
+    // 실행 중인 명령이 있으면 완료될 때까지 주기적으로 갱신
+    async function loadRuns() {
+        if (pollTimer) clearTimeout(pollTimer);
+        try {
+            const runs = (await api('GET', '/api/snippet-runs')).runs;
+            renderRuns(runs);
+            const shown = runs.find(r => r.id === shownRun);
+            if (shown && (shown.status !== 'running' || runOutput.style.display === 'block')) showRun(shownRun);
+            if (runs.some(r => r.status === 'running')) pollTimer = setTimeout(loadRuns, 2000);
+        } catch (err) {
+            showError(err.message);
+        }
+    }
+
+    loadSnippets();
+    loadRuns();
+});
@@ -0,0 +1,37 @@
+// OTP 재인증(step-up) 처리 (csrf.js 다음, 다른 스크립트보다 먼저 로드)
+// - 서버가 X-Step-Up: required 응답으로 거부하면 재인증 페이지로 이동 (인증 후 현재 페이지로 복귀)
+// - 웹소켓은 응답 헤더를 확인할 수 없으므로 연결 전에 ensure()로 미리 확인
+const RootWebStepUp = (function () {
+    const verifyUrl = '/account/verify';
+
+    function redirect() {
+        location.href = verifyUrl + '?next=' + encodeURIComponent(location.pathname + location.search);
+    }
+
+    const wrappedFetch = window.fetch;
+    window.fetch = async (input, init) => {
+        const res = await wrappedFetch(input, init);
+        if (res.status === 403 && res.headers.get('X-Step-Up') === 'required') {
+            redirect();
+        }
+        return res;
+    };
+
+    // 재인증이 필요하면 재인증 페이지로 이동하고 false 반환
+    async function ensure() {
+        try {
+            const res = await wrappedFetch('/api/account/step-up');
+            const data = await res.json();
+            if (data.required) {
+                redirect();
+                return false;
+            }
+        } catch (err) {
+            // 확인에 실패하면 서버의 거부 응답에 맡김
+            console.error('Step-up check failed:', err);
+        }
+        return true;
+    }
+
+    return { ensure: ensure };
+})();
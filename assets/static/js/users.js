@@ -0,0 +1,170 @@
+document.addEventListener('DOMContentLoaded', () => {
+    const rows = document.getElementById('user-rows');
+    const alertBox = document.getElementById('alert');
+    const createForm = document.getElementById('create-form');
+    const roles = ['viewer', 'operator', 'admin'];
+
+    function showError(msg) {
+        alertBox.textContent = msg;
+        alertBox.style.display = msg ? 'block' : 'none';
+    }
+
+    async function api(method, url, body) {
+        const res = await fetch(url, {
+            method: method,
+            headers: { 'Content-Type': 'application/json' },
+            body: body ? JSON.stringify(body) : undefined,
+        });
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) throw new Error(data.error || ('요청 실패 (' + res.status + ')'));
+        return data;
+    }
+
+    function cell(content) {
+        const td = document.createElement('td');
+        if (content instanceof Node) td.appendChild(content);
+        else td.textContent = content;
+        return td;
+    }
+
+    function link(label, onClick) {
+        const a = document.createElement('a');
+        a.href = '#';
+        a.textContent = label;
+        a.style.marginRight = '12px';
+        a.addEventListener('click', (e) => {
+            e.preventDefault();
+            onClick();
+        });
+        return a;
+    }
+
+    async function update(user, changes, confirmMsg) {
+        if (confirmMsg && !confirm(confirmMsg)) return;
+        try {
+            await api('PATCH', '/api/users/' + user.id, changes);
+            showError('');
+        } catch (err) {
+            showError(err.message);
+        }
+        load();
+    }
+
+    function render(users) {
+        rows.innerHTML = '';
+        if (users.length === 0) {
+            const tr = document.createElement('tr');
+            const td = cell('사용자가 없습니다.');
+            td.className = 'empty';
+            td.colSpan = 9;
+            tr.appendChild(td);
+            rows.appendChild(tr);
+            return;
+        }
+
+        users.forEach(user => {
+            const tr = document.createElement('tr');
+            const isMe = user.id === currentUserId;
+
+            // 권한 등급 선택
+            const roleSelect = document.createElement('select');
+            roles.forEach(r => {
+                const opt = document.createElement('option');
+                opt.value = r;
+                opt.textContent = r;
+                opt.selected = r === user.role;
+                roleSelect.appendChild(opt);
+            });
+            roleSelect.disabled = isMe;
+            roleSelect.addEventListener('change', () => update(user, { role: roleSelect.value }));
+
+            const status = document.createElement('span');
+            status.className = 'badge ' + (user.disabled ? 'fail' : 'ok');
+            status.textContent = user.disabled ? '비활성' : '활성';
+
+            // 리눅스 계정 매핑 (포커스 해제 시 저장)
+            const unixInput = document.createElement('input');
+            unixInput.type = 'text';
+            unixInput.value = user.unixUser || '';
+            unixInput.placeholder = '미지정';
+            unixInput.className = 'cell-input';
+            unixInput.addEventListener('change', () => update(user, { unixUser: unixInput.value.trim() }));
+
+            const rootCheck = document.createElement('input');
+            rootCheck.type = 'checkbox';
+            rootCheck.checked = user.allowRoot;
+            rootCheck.className = 'cell-check';
+            rootCheck.addEventListener('change', () => update(user, { allowRoot: rootCheck.checked },
+                rootCheck.checked ? user.username + ' 계정에 루트 쉘 사용을 허용합니다.' : null));
+
+            const actions = document.createElement('span');
+            if (!isMe) {
+                actions.appendChild(link(user.disabled ? '활성화' : '비활성화',
+                    () => update(user, { disabled: !user.disabled })));
+            }
+            actions.appendChild(link('비밀번호 초기화', () => {
+                const pw = prompt(user.username + ' 계정의 새 비밀번호 (8자 이상)');
+                if (pw) update(user, { password: pw });
+            }));
+            if (user.otpEnrolled || user.passkeys > 0) {
+                actions.appendChild(link('2단계 인증 초기화', () => update(user, { resetOtp: true },
+                    user.username + ' 계정의 OTP, 복구 코드, 패스키를 모두 삭제합니다. 다음 로그인 시 OTP를 다시 등록해야 합니다.')));
+            }
+            if (!isMe) {
+                actions.appendChild(link('삭제', async () => {
+                    if (!confirm(user.username + ' 계정을 삭제합니다.')) return;
+                    try {
+                        await api('DELETE', '/api/users/' + user.id);
+                        showError('');
+                    } catch (err) {
+                        showError(err.message);
+                    }
+                    load();
+                }));
+            }
+
+            tr.appendChild(cell(String(user.id)));
+            tr.appendChild(cell(user.username + (isMe ? ' (나)' : '')));
+            tr.appendChild(cell(roleSelect));
+            tr.appendChild(cell(status));
+            const factors = [];
+            if (user.otpEnrolled) factors.push('OTP');
+            if (user.passkeys > 0) factors.push('패스키 ' + user.passkeys);
+            tr.appendChild(cell(factors.length ? factors.join(' · ') : '미등록'));
+            tr.appendChild(cell(unixInput));
+            tr.appendChild(cell(rootCheck));
+            tr.appendChild(cell(new Date(user.createdAt).toLocaleString()));
+            tr.appendChild(cell(actions));
+            rows.appendChild(tr);
+        });
+    }
+
+    async function load() {
+        try {
+            const data = await api('GET', '/api/users');
+            render(data.users);
+        } catch (err) {
+            showError(err.message);
+        }
+    }
+
+    createForm.addEventListener('submit', async (e) => {
+        e.preventDefault();
+        const form = new FormData(createForm);
+        try {
+            await api('POST', '/api/users', {
+                username: form.get('username'),
+                password: form.get('password'),
+                role: form.get('role'),
+                unixUser: form.get('unixUser'),
+            });
+            createForm.reset();
+            showError('');
+        } catch (err) {
+            showError(err.message);
+        }
+        load();
+    });
+
+    load();
+});
@@ -0,0 +1,81 @@
+// WebAuthn 요청/응답의 바이너리 필드를 서버 형식(base64url)과 상호 변환하는 공용 함수
+const RootWebAuthn = (() => {
+    function toBuffer(value) {
+        const b64 = value.replace(/-/g, '+').replace(/_/g, '/');
+        const pad = b64.length % 4 ? '='.repeat(4 - (b64.length % 4)) : '';
+        return Uint8Array.from(atob(b64 + pad), c => c.charCodeAt(0)).buffer;
+    }
+
+    function toBase64url(buffer) {
+        const bytes = new Uint8Array(buffer);
+        let str = '';
+        bytes.forEach(b => { str += String.fromCharCode(b); });
+        return btoa(str).replace(/\+/g, '-').replace(/\//g, '_').replace(/=+$/, '');
+    }
+
+    async function request(url) {
+        const res = await fetch(url, { method: 'POST' });
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) throw new Error(data.error || ('요청 실패 (' + res.status + ')'));
+        return data;
+    }
+
+    async function submit(url, body) {
+        const res = await fetch(url, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(body),
+        });
+        const data = await res.json().catch(() => ({}));
+        if (!res.ok) throw new Error(data.error || ('요청 실패 (' + res.status + ')'));
+        return data;
+    }
+
+    // 패스키 등록 (beginUrl에서 옵션을 받아 인증 장치에 키 생성 후 finishUrl로 전송)
+    async function register(beginUrl, finishUrl) {
+        const options = await request(beginUrl);
+        const pk = options.publicKey;
+        pk.challenge = toBuffer(pk.challenge);
+        pk.user.id = toBuffer(pk.user.id);
+        (pk.excludeCredentials || []).forEach(c => { c.id = toBuffer(c.id); });
+
+        const cred = await navigator.credentials.create({ publicKey: pk });
+        return submit(finishUrl, {
+            id: cred.id,
+            rawId: toBase64url(cred.rawId),
+            type: cred.type,
+            response: {
+                attestationObject: toBase64url(cred.response.attestationObject),
+                clientDataJSON: toBase64url(cred.response.clientDataJSON),
+                transports: cred.response.getTransports ? cred.response.getTransports() : [],
+            },
+        });
+    }
+
+    // 패스키 인증 (beginUrl에서 옵션을 받아 인증 장치로 서명 후 finishUrl로 전송)
+    async function authenticate(beginUrl, finishUrl) {
+        const options = await request(beginUrl);
+        const pk = options.publicKey;
+        pk.challenge = toBuffer(pk.challenge);
+        (pk.allowCredentials || []).forEach(c => { c.id = toBuffer(c.id); });
+
+        const cred = await navigator.credentials.get({ publicKey: pk });
+        return submit(finishUrl, {
+            id: cred.id,
+            rawId: toBase64url(cred.rawId),
+            type: cred.type,
+            response: {
+                authenticatorData: toBase64url(cred.response.authenticatorData),
+                clientDataJSON: toBase64url(cred.response.clientDataJSON),
+                signature: toBase64url(cred.response.signature),
+                userHandle: cred.response.userHandle ? toBase64url(cred.response.userHandle) : null,
+            },
+        });
+    }
+
+    return {
+        supported: !!window.PublicKeyCredential,
+        register: register,
+        authenticate: authenticate,
+    };
+})();
@@ -28,9 +28,12 @@ import (
 	"time"
 
 	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/filemgr"
 	"github.com/hoon-x/rootweb/internal/ipc"
 	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/metrics"
 	"github.com/hoon-x/rootweb/internal/server"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
 	"github.com/hoon-x/rootweb/pkg/proc"
 	"github.com/hoon-x/rootweb/pkg/task"
 	"github.com/spf13/cobra"
@@ -75,10 +78,33 @@ var stopCmd = &cobra.Command{
 	RunE:  wrapCmdFuncForCobra(shutdown),
 }
 
+var logLevelCmd = &cobra.Command{
+	Use:   "toggle-debug-log",
+	Short: "Toggle debug logging of the running " + config.ModuleName + " (reverts to the configured level on restart)",
+	RunE:  wrapCmdFuncForCobra(toggleDebugLog),
+}
+
+var rotateKeyCmd = &cobra.Command{
+	Use:   "rotate-session-key",
+	Short: "Generate a new session cookie key (applied on restart, previous key remains valid)",
+	RunE:  wrapCmdFuncForCobra(rotateSessionKey),
+}
+
+// fsHelperCmd 파일 관리자가 사용자의 리눅스 계정 권한으로 실행하는 파일 작업 도우미 (내부용)
+var fsHelperCmd = &cobra.Command{
+	Use:                filemgr.HelperCommand,
+	Short:              "Run a file manager operation as the current user (internal use)",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(filemgr.RunHelper(args, os.Stdin, os.Stdout, os.Stderr))
+	},
+}
+
 var taskManager *task.TaskManager
 
 func init() {
-	rootCmd.AddCommand(startCmd, debugCmd, stopCmd)
+	rootCmd.AddCommand(startCmd, debugCmd, stopCmd, logLevelCmd, rotateKeyCmd, fsHelperCmd)
 }
 
 // Execute 프로그램 진입점 역할을 수행하며, 설정된 모든 명령어 실행
@@ -168,15 +194,20 @@ func run(cmd *cobra.Command) error {
 
 	// 모듈 초기화
 	initialize()
-	logger.LogInfo("Run %s (pid:%d)", config.ModuleName, config.RunConf.Pid)
+	logger.LogInfow("Run "+config.ModuleName, "pid", config.RunConf.Pid)
 
 	// 등록된 모든 작업 가동
 	taskManager.RunAll()
 
 	// 종료 시그널 대기 (SIGINT, SIGTERM)
+	// SIGUSR1 수신 시 디버그 로그 기록 여부 전환
 	sig := <-sigChan
+	for sig == syscall.SIGUSR1 {
+		logger.LogWarnw("Log level changed by signal", "signal", sig.String(), "level", logger.ToggleDebugLevel())
+		sig = <-sigChan
+	}
 
-	logger.LogInfo("Received %s (signum:%d)", sig.String(), sig)
+	logger.LogInfow("Signal received", "signal", sig.String(), "signum", int(sig.(syscall.Signal)))
 
 	// 모듈 자원 정리
 	finalize()
@@ -209,6 +240,60 @@ func shutdown(cmd *cobra.Command) error {
 	return nil
 }
 
+// toggleDebugLog 실행 중인 모듈의 디버그 로그 기록 여부 전환
+func toggleDebugLog(cmd *cobra.Command) error {
+	// 작업 경로를 실행 파일이 위치한 경로로 변경
+	if err := chdirToExecutableDir(); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to change working path: %v\n", err)
+		return err
+	}
+
+	// 프로세스가 동작 중인지 확인
+	var pid int
+	if !isRun(&pid, config.PidFilePath) {
+		fmt.Fprintf(os.Stderr, "[ERROR] %s is not running\n", config.ModuleName)
+		return fmt.Errorf("%s is not running", config.ModuleName)
+	}
+
+	// 프로세스에 로그 레벨 전환 시그널 전송
+	if err := proc.SendSignal(pid, syscall.SIGUSR1); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to send signal (pid:%d): %v\n", pid, err)
+		return err
+	}
+	return nil
+}
+
+// rotateSessionKey 세션 쿠키 키 교체 (이전 키는 기존 세션 검증용으로 보관)
+func rotateSessionKey(cmd *cobra.Command) error {
+	// 작업 경로를 실행 파일이 위치한 경로로 변경
+	if err := chdirToExecutableDir(); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to change working path: %v\n", err)
+		return err
+	}
+
+	// 설정 파일 로드
+	if err := config.Conf.LoadConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to load config: %v\n", err)
+		return err
+	}
+
+	logger.InitializeLogger(config.LogFilePath,
+		config.Conf.Log.MaxSize, config.Conf.Log.MaxBackups,
+		config.Conf.Log.MaxAge, config.Conf.Log.Compress, false,
+		config.Conf.Log.Format, config.Conf.Log.Level)
+	defer logger.FinalizeLogger()
+
+	if err := sessionstore.RotateKeys(); err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] Failed to rotate session key: %v\n", err)
+		logger.LogErrorw("Failed to rotate session key", "err", err)
+		return err
+	}
+
+	logger.LogInfow("Session key rotated")
+	fmt.Fprintf(os.Stdout, "[INFO] Session key rotated. Restart %s to apply.\n", config.ModuleName)
+	return nil
+}
+
 // chdirToExecutableDir 프로세스 작업 경로를 실행 파일이 위치한 경로로 변경
 func chdirToExecutableDir() error {
 	exePath, err := os.Executable()
@@ -326,8 +411,8 @@ func redirectToDevNull(debug bool) error {
 // setSignal 시그널 설정
 func setSignal() chan os.Signal {
 	sigChan := make(chan os.Signal, 1)
-	// 수신할 시그널 설정 (SIGINT, SIGTERM)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	// 수신할 시그널 설정 (SIGINT, SIGTERM, SIGUSR1)
+	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGUSR1)
 	// 무시할 시그널 설정
 	signal.Ignore(syscall.SIGHUP, syscall.SIGPIPE, syscall.SIGTTIN, syscall.SIGTTOU,
 		syscall.SIGTSTP, syscall.SIGQUIT, syscall.SIGWINCH, syscall.SIGURG)
@@ -341,10 +426,41 @@ func initialize() {
 	logger.InitializeLogger(config.LogFilePath,
 		config.Conf.Log.MaxSize, config.Conf.Log.MaxBackups,
 		config.Conf.Log.MaxAge, config.Conf.Log.Compress,
-		config.RunConf.Debug)
+		config.RunConf.Debug, config.Conf.Log.Format, config.Conf.Log.Level)
+	if c := config.Conf.Log.Syslog; c.Enabled {
+		err := logger.AddSyslogSink(logger.SyslogOptions{
+			Network:  c.Network,
+			Address:  c.Address,
+			Facility: c.Facility,
+			Tag:      config.ModuleName,
+			Level:    c.Level,
+			Format:   config.Conf.Log.Format,
+		})
+		if err != nil {
+			logger.LogErrorw("Failed to add syslog output", "network", c.Network, "address", c.Address, "err", err)
+		}
+	}
+	if c := config.Conf.Log.Journald; c.Enabled {
+		err := logger.AddJournaldSink(logger.JournaldOptions{
+			Tag:    config.ModuleName,
+			Level:  c.Level,
+			Format: config.Conf.Log.Format,
+		})
+		if err != nil {
+			logger.LogErrorw("Failed to add journald output", "err", err)
+		}
+	}
+	if config.Conf.Log.Access.Enabled {
+		logger.InitializeAccessLogger(config.AccessLogFilePath,
+			config.Conf.Log.MaxSize, config.Conf.Log.MaxBackups,
+			config.Conf.Log.MaxAge, config.Conf.Log.Compress)
+	}
 
 	// 작업 관리자 생성
 	taskManager = task.NewTaskManager(panicHandler)
+	if config.Conf.Metrics.Enabled {
+		metrics.WatchTasks(taskManager)
+	}
 
 	// 서버 작업 등록
 	taskManager.AddTask("server", server.Run)
@@ -355,9 +471,9 @@ func initialize() {
 func finalize() {
 	// 가동중인 모든 작업 종료 지시
 	if err := taskManager.ShutdownAll(10 * time.Second); err != nil {
-		logger.LogWarn("All tasks have not been completed: %v", err)
+		logger.LogWarnw("All tasks have not been completed", "err", err)
 	}
-	logger.LogInfo("Shutdown %s (pid:%d)", config.ModuleName, config.RunConf.Pid)
+	logger.LogInfow("Shutdown "+config.ModuleName, "pid", config.RunConf.Pid)
 
 	// 로거 자원 해제
 	logger.FinalizeLogger()
@@ -365,5 +481,5 @@ func finalize() {
 
 // panicHandler 고루틴 패닉 핸들러
 func panicHandler(err interface{}) {
-	logger.LogError("panic occurred: %v", err)
+	logger.LogErrorw("panic occurred", "err", err)
 }
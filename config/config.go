@@ -31,9 +31,10 @@ var (
 )
 
 var (
-	LogFilePath  = "log/" + ModuleName + ".log"
-	ConfFilePath = "config/" + ModuleName + ".yaml"
-	PidFilePath  = "var/." + ModuleName + ".pid"
+	LogFilePath       = "log/" + ModuleName + ".log"
+	AccessLogFilePath = "log/" + ModuleName + "_access.log"
+	ConfFilePath      = "config/" + ModuleName + ".yaml"
+	PidFilePath       = "var/." + ModuleName + ".pid"
 )
 
 type Config struct {
@@ -46,6 +47,10 @@ type Config struct {
 		// TLS 인증서 파일 경로
 		TlsCertPath string `yaml:"tlsCertPath"`
 		TlsKeyPath  string `yaml:"tlsKeyPath"`
+		// 클라이언트 IP 헤더(X-Forwarded-For 등)를 신뢰할 리버스 프록시 주소 목록 (미설정 시 헤더 무시)
+		TrustedProxies []string `yaml:"trustedProxies"`
+		// 웹소켓 연결 및 상태 변경 요청을 허용할 Origin 목록 (미설정 시 https://접속 호스트만 허용)
+		AllowedOrigins []string `yaml:"allowedOrigins"`
 	} `yaml:"server"`
 
 	// DB 설정
@@ -53,6 +58,124 @@ type Config struct {
 		DBPath string `yaml:"dbPath"`
 	} `yaml:"db"`
 
+	// 로그인 유지 시간 설정
+	Auth struct {
+		// 마지막 요청 후 로그인 세션 유지 시간 (단위:초)
+		IdleTimeout int `yaml:"idleTimeout"`
+		// 세션 유지 기간 갱신 간격 (단위:초, 요청마다 세션을 저장하지 않도록 함)
+		RefreshInterval int `yaml:"refreshInterval"`
+		// 활동과 관계없이 로그인 후 세션을 유지하는 최대 시간 (단위:초)
+		MaxLifetime int `yaml:"maxLifetime"`
+	} `yaml:"auth"`
+
+	// 로그인 세션 설정
+	Session struct {
+		// 세션 쿠키 서명/암호화 키 파일 경로 (없으면 최초 실행 시 생성)
+		KeyFile string `yaml:"keyFile"`
+		// 키 파일 대신 사용할 키 목록 (첫 번째 항목이 현재 키, 나머지는 기존 세션 검증용 이전 키)
+		Keys []SessionKey `yaml:"keys"`
+	} `yaml:"session"`
+
+	// 웹 터미널 설정
+	Terminal struct {
+		// 쉘 프로세스 환경 변수 (데몬의 환경 변수는 상속하지 않음)
+		// HOME, USER, LOGNAME, SHELL은 매핑된 리눅스 계정 정보로 설정됨
+		Env map[string]string `yaml:"env"`
+		// 웹소켓 연결이 끊긴 세션의 유지 시간 (단위:초, 0이면 즉시 종료)
+		DetachTimeout int `yaml:"detachTimeout"`
+		// 재접속 시 재전송할 스크롤백 버퍼 크기 (단위:KB)
+		ScrollbackSize int `yaml:"scrollbackSize"`
+		// 사용자당 최대 세션 개수 (0이면 무제한)
+		MaxSessionsPerUser int `yaml:"maxSessionsPerUser"`
+		// 키 입력이 없을 때 세션을 잠그거나 종료하기까지의 시간 (단위:초, 0이면 제한 없음)
+		IdleTimeout int `yaml:"idleTimeout"`
+		// 입력 없음 시간 초과 시 처리 방식 (lock: 연결 해제 후 OTP 재인증 시 재접속 허용, disconnect: 세션 종료)
+		IdleAction string `yaml:"idleAction"`
+		// 입력 여부와 관계없이 세션을 유지하는 최대 시간 (단위:초, 0이면 제한 없음)
+		MaxDuration int `yaml:"maxDuration"`
+		// 잠금 또는 종료 전 경고 메시지를 보내는 시점 (단위:초)
+		WarnBefore int `yaml:"warnBefore"`
+		// 터미널 안에서 iTerm2 파일 전송 이스케이프(OSC 1337 File, RequestUpload)로 파일 주고받기 허용 여부
+		FileTransfer bool `yaml:"fileTransfer"`
+		// 터미널 파일 전송 최대 크기 (단위:MB)
+		TransferMaxSize int `yaml:"transferMaxSize"`
+	} `yaml:"terminal"`
+
+	// 파일 관리자 설정
+	Files struct {
+		// 파일 관리자 활성화 플래그
+		Enabled bool `yaml:"enabled"`
+		// 업로드 요청 1회당 최대 크기 (단위:MB, 큰 파일은 나누어 전송하며 중단 시 이어서 전송)
+		ChunkSize int `yaml:"chunkSize"`
+	} `yaml:"files"`
+
+	// 저장된 명령(스니펫) 설정
+	Snippets struct {
+		// 비대화형 실행 최대 시간 (단위:초, 초과 시 프로세스 그룹 종료)
+		RunTimeout int `yaml:"runTimeout"`
+		// 실행 기록에 저장할 최대 출력 크기 (단위:KB, 초과분은 버림)
+		MaxOutput int `yaml:"maxOutput"`
+		// 실행 기록 보관 기간 (단위:일)
+		RunRetention int `yaml:"runRetention"`
+	} `yaml:"snippets"`
+
+	// 터미널 세션 녹화 설정
+	Recording struct {
+		// 녹화 활성화 플래그
+		Enabled bool `yaml:"enabled"`
+		// 녹화 파일(asciicast v2) 저장 경로
+		Dir string `yaml:"dir"`
+		// 키 입력 녹화 여부 (비밀번호 등 민감 정보가 기록될 수 있음)
+		RecordInput bool `yaml:"recordInput"`
+	} `yaml:"recording"`
+
+	// WebAuthn(패스키) 설정
+	WebAuthn struct {
+		// Relying Party ID (미설정 시 접속 호스트명 사용)
+		RPID string `yaml:"rpId"`
+		// 허용할 Origin 목록 (미설정 시 https://접속 호스트 사용)
+		RPOrigins []string `yaml:"rpOrigins"`
+	} `yaml:"webauthn"`
+
+	// 로그인 시도 제한 설정 (/login, /setup)
+	LoginThrottle struct {
+		// 실패 횟수 집계 기간 (단위:초, 마지막 실패 후 이 시간이 지나면 초기화)
+		Window int `yaml:"window"`
+		// 실패 시 다음 시도까지의 대기 시간 (단위:초, 실패할 때마다 2배씩 증가)
+		BaseDelay int `yaml:"baseDelay"`
+		// 대기 시간 최대값 (단위:초)
+		MaxDelay int `yaml:"maxDelay"`
+		// 계정 잠금까지 허용하는 아이디별 연속 실패 횟수
+		UserMaxFailures int `yaml:"userMaxFailures"`
+		// 차단까지 허용하는 IP별 연속 실패 횟수
+		IPMaxFailures int `yaml:"ipMaxFailures"`
+		// 잠금 유지 시간 (단위:초)
+		LockoutDuration int `yaml:"lockoutDuration"`
+		// 실패 기록 보관 기간 (단위:일)
+		Retention int `yaml:"retention"`
+	} `yaml:"loginThrottle"`
+
+	// 민감한 작업(터미널 열기, 파일 관리자, 사용자 및 패스키 관리) 전 OTP 재인증 설정
+	StepUp struct {
+		// 재인증 활성화 플래그
+		Enabled bool `yaml:"enabled"`
+		// 마지막 OTP 인증 후 재인증 없이 허용하는 시간 (단위:초)
+		MaxAge int `yaml:"maxAge"`
+	} `yaml:"stepUp"`
+
+	// Prometheus 메트릭 설정
+	Metrics struct {
+		// /metrics 제공 여부
+		Enabled bool `yaml:"enabled"`
+		// 별도 리스닝 포트 (0이면 웹 서버 포트에서 제공, 별도 포트도 웹 서버의 TLS 인증서 사용)
+		Port int `yaml:"port"`
+		// 조회 시 Authorization: Bearer 헤더로 보내야 하는 토큰
+		BearerToken string `yaml:"bearerToken"`
+		// 조회를 허용할 IP 또는 CIDR 목록 (토큰과 함께 지정하면 둘 다 만족해야 함)
+		// 토큰과 허용 목록이 모두 비어 있으면 로컬 접속만 허용
+		AllowIPs []string `yaml:"allowIPs"`
+	} `yaml:"metrics"`
+
 	// 로그 설정
 	Log struct {
 		// 최대 로그 파일 사이즈 (단위:MB)
@@ -63,9 +186,49 @@ type Config struct {
 		MaxAge int `yaml:"maxAge"`
 		// 로그 파일 백업 시 압축 여부
 		Compress bool `yaml:"compress"`
+		// 로그 파일 형식 (console: 한 줄 텍스트, json: JSON 한 줄)
+		Format string `yaml:"format"`
+		// 파일에 기록할 최소 로그 레벨 (debug, info, warn, error)
+		// 실행 중 관리자 API 또는 SIGUSR1 시그널로 변경 가능하며 재시작하면 이 값으로 돌아감
+		Level string `yaml:"level"`
+		// 웹 요청 접근 로그 설정 (로그 파일 크기 및 백업 설정은 위 설정을 따름)
+		Access struct {
+			// 접근 로그 기록 여부
+			Enabled bool `yaml:"enabled"`
+			// 값을 가려서 기록할 쿼리 및 폼 필드 이름 (기본 목록에 추가)
+			RedactFields []string `yaml:"redactFields"`
+		} `yaml:"access"`
+		// 로그 파일과 함께 syslog 서버로 로그 전송 (RFC 5424)
+		Syslog struct {
+			// syslog 전송 여부
+			Enabled bool `yaml:"enabled"`
+			// 연결 방식 (unix, udp, tcp)
+			Network string `yaml:"network"`
+			// unix: 소켓 경로 (비어 있으면 /dev/log), udp/tcp: 호스트:포트
+			Address string `yaml:"address"`
+			// facility (daemon, auth, authpriv, local0~local7 등, 비어 있으면 daemon)
+			Facility string `yaml:"facility"`
+			// 전송할 최소 로그 레벨 (debug, info, warn, error)
+			Level string `yaml:"level"`
+		} `yaml:"syslog"`
+		// 로그 파일과 함께 journald로 로그 전송 (네이티브 프로토콜)
+		Journald struct {
+			// journald 전송 여부
+			Enabled bool `yaml:"enabled"`
+			// 전송할 최소 로그 레벨 (debug, info, warn, error)
+			Level string `yaml:"level"`
+		} `yaml:"journald"`
 	} `yaml:"log"`
 }
 
+// SessionKey 세션 쿠키 키 쌍 (base64)
+type SessionKey struct {
+	// 서명 키 (32바이트 이상)
+	AuthKey string `yaml:"authKey"`
+	// 암호화 키 (16, 24, 32바이트 중 하나, 비워두면 암호화하지 않음)
+	EncryptionKey string `yaml:"encryptionKey"`
+}
+
 type RunConfig struct {
 	Debug bool
 	Pid   int
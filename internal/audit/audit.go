@@ -0,0 +1,245 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"gorm.io/gorm"
+)
+
+// 이벤트 종류
+const (
+	TypeSetup         = "setup"               // 최초 관리자 계정 등록
+	TypeLoginSuccess  = "login.success"       // 로그인 성공
+	TypeLoginFailure  = "login.failure"       // 로그인 실패 (초기 설정 OTP 불일치 및 시도 제한 포함)
+	TypeLogout        = "logout"              // 로그아웃
+	TypeSessionExpire = "session.expire"      // 로그인 세션 만료
+	TypeSessionRevoke = "session.revoke"      // 관리자가 로그인 세션 강제 종료
+	TypeStepUp        = "stepup"              // 민감한 작업 전 OTP 재인증
+	TypeOTPEnroll     = "otp.enroll"          // 최초 로그인 OTP 등록
+	TypeOTPRotate     = "otp.rotate"          // OTP 재등록
+	TypeRecoveryCodes = "recovery.regenerate" // 복구 코드 재발급
+	TypePasskeyAdd    = "passkey.register"    // 패스키 등록
+	TypePasskeyDelete = "passkey.delete"      // 패스키 삭제
+
+	TypeTerminalOpen      = "terminal.open"      // 새 쉘 실행
+	TypeTerminalAttach    = "terminal.attach"    // 기존 세션에 재접속
+	TypeTerminalJoin      = "terminal.join"      // 초대 링크로 참여
+	TypeTerminalResize    = "terminal.resize"    // 터미널 크기 변경 (연속 변경은 마지막 크기만 기록)
+	TypeTerminalLock      = "terminal.lock"      // 입력 없음으로 세션 잠금
+	TypeTerminalUnlock    = "terminal.unlock"    // 잠긴 세션을 OTP 재인증 후 잠금 해제
+	TypeTerminalLimit     = "terminal.limit"     // 입력 없음 또는 최대 사용 시간 초과로 세션 종료
+	TypeTerminalClose     = "terminal.close"     // 세션 종료 (쉘 종료, 강제 종료, 사용 제한 등)
+	TypeTerminalTerminate = "terminal.terminate" // 소유자 또는 관리자가 세션 강제 종료
+
+	TypeSnippetSend = "snippet.send" // 저장된 명령을 터미널에 전송
+	TypeSnippetRun  = "snippet.run"  // 저장된 명령을 비대화형으로 실행
+
+	TypeFileList         = "file.list"          // 디렉터리 항목 조회
+	TypeFileUploadChunk  = "file.upload_chunk"  // 업로드 데이터 일부 기록
+	TypeFileUploadCancel = "file.upload_cancel" // 중단된 업로드 취소 (임시 파일 삭제)
+	TypeFileUpload       = "file.upload"        // 파일 업로드 완료
+	TypeFileDownload     = "file.download"      // 파일 다운로드 (중단된 경우 전송된 크기까지 기록)
+	TypeFileMkdir        = "file.mkdir"         // 디렉터리 생성
+	TypeFileRename       = "file.rename"        // 이름 변경 및 이동
+	TypeFileChmod        = "file.chmod"         // 권한 변경
+	TypeFileDelete       = "file.delete"        // 파일 또는 디렉터리 삭제
+
+	TypeUserCreate    = "user.create"    // 관리자가 사용자 생성
+	TypeUserUpdate    = "user.update"    // 관리자가 사용자 정보 변경
+	TypeUserDelete    = "user.delete"    // 관리자가 사용자 삭제
+	TypeThrottleClear = "throttle.clear" // 관리자가 로그인 제한 해제
+	TypeLogLevel      = "log.level"      // 관리자가 로그 레벨 변경
+)
+
+// Types 조회 화면에 표시할 이벤트 종류 목록
+var Types = []string{
+	TypeSetup, TypeLoginSuccess, TypeLoginFailure, TypeLogout, TypeSessionExpire, TypeSessionRevoke,
+	TypeStepUp, TypeOTPEnroll, TypeOTPRotate, TypeRecoveryCodes, TypePasskeyAdd, TypePasskeyDelete,
+	TypeTerminalOpen, TypeTerminalAttach, TypeTerminalJoin, TypeTerminalResize, TypeTerminalLock,
+	TypeTerminalUnlock, TypeTerminalLimit, TypeTerminalClose, TypeTerminalTerminate,
+	TypeSnippetSend, TypeSnippetRun, TypeFileList, TypeFileUploadChunk, TypeFileUploadCancel,
+	TypeFileUpload, TypeFileDownload, TypeFileMkdir, TypeFileRename, TypeFileChmod, TypeFileDelete,
+	TypeUserCreate, TypeUserUpdate, TypeUserDelete, TypeThrottleClear, TypeLogLevel,
+}
+
+// 검증 중 체인이 끊어진 이벤트를 찾으면 순회를 멈추기 위한 오류
+var errBroken = errors.New("audit chain broken")
+
+// 첫 이벤트의 이전 해시
+var genesisHash = strings.Repeat("0", sha256.Size*2)
+
+// Event 기록할 감사 이벤트
+type Event struct {
+	Type     string
+	UserID   uint   // 작업을 수행한 사용자 (Username이 비어 있으면 ID로 조회)
+	Username string // 로그인 실패처럼 계정이 없을 수 있으면 입력된 아이디
+	ClientIP string
+	Target   string
+	Details  map[string]interface{}
+}
+
+// chain 마지막으로 기록된 이벤트 (해시 체인을 이어가기 위해 기록은 하나씩 순서대로 처리)
+type chain struct {
+	mu       sync.Mutex
+	loaded   bool
+	lastID   uint
+	lastHash string
+}
+
+var tail chain
+
+// Record 감사 이벤트를 해시 체인에 추가
+// 기록 실패는 요청 처리를 막지 않도록 서버 로그에만 남김
+func Record(e Event) {
+	if e.Username == "" && e.UserID != 0 {
+		var user db.User
+		if err := db.SqliteDB.Unscoped().Select("username").First(&user, e.UserID).Error; err == nil {
+			e.Username = user.Username
+		}
+	}
+	details := "{}"
+	if len(e.Details) > 0 {
+		b, err := json.Marshal(e.Details)
+		if err != nil {
+			logger.Server.Errorw("Failed to encode audit event details", "type", e.Type, "err", err)
+		} else {
+			details = string(b)
+		}
+	}
+
+	tail.mu.Lock()
+	defer tail.mu.Unlock()
+
+	if !tail.loaded {
+		var last db.AuditEvent
+		if err := db.SqliteDB.Order("id DESC").Limit(1).Find(&last).Error; err != nil {
+			logger.Server.Errorw("Failed to load last audit event", "type", e.Type, "user", e.Username, "err", err)
+			return
+		}
+		tail.lastID, tail.lastHash = last.ID, last.Hash
+		if last.ID == 0 {
+			tail.lastHash = genesisHash
+		}
+		tail.loaded = true
+	}
+
+	ev := db.AuditEvent{
+		ID:       tail.lastID + 1,
+		Time:     time.Now().UTC(),
+		Type:     e.Type,
+		UserID:   e.UserID,
+		Username: e.Username,
+		ClientIP: e.ClientIP,
+		Target:   e.Target,
+		Details:  details,
+		PrevHash: tail.lastHash,
+	}
+	ev.Hash = Hash(&ev)
+	if err := db.SqliteDB.Create(&ev).Error; err != nil {
+		// 다른 프로세스가 추가했을 수 있으므로 다음 기록 시 마지막 이벤트를 다시 읽음
+		tail.loaded = false
+		logger.Server.Errorw("Failed to record audit event", "type", e.Type, "user", e.Username, "ip", e.ClientIP, "err", err)
+		return
+	}
+	tail.lastID, tail.lastHash = ev.ID, ev.Hash
+
+	// 서버 로그에도 해시를 남겨 마지막 이벤트들이 통째로 지워진 경우도 확인할 수 있도록 함
+	logger.Server.Infow("Audit event recorded",
+		"id", ev.ID, "type", ev.Type, "user", ev.Username, "ip", ev.ClientIP, "target", ev.Target, "hash", ev.Hash)
+}
+
+// Hash 이전 해시를 포함한 이벤트 내용의 SHA-256 해시
+func Hash(ev *db.AuditEvent) string {
+	b, _ := json.Marshal(struct {
+		ID       uint   `json:"id"`
+		Time     string `json:"time"`
+		Type     string `json:"type"`
+		UserID   uint   `json:"userId"`
+		Username string `json:"username"`
+		ClientIP string `json:"clientIp"`
+		Target   string `json:"target"`
+		Details  string `json:"details"`
+		PrevHash string `json:"prevHash"`
+	}{
+		ID:       ev.ID,
+		Time:     ev.Time.UTC().Format(time.RFC3339Nano),
+		Type:     ev.Type,
+		UserID:   ev.UserID,
+		Username: ev.Username,
+		ClientIP: ev.ClientIP,
+		Target:   ev.Target,
+		Details:  ev.Details,
+		PrevHash: ev.PrevHash,
+	})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyResult 해시 체인 검증 결과
+type VerifyResult struct {
+	Count    int64  `json:"count"`              // 검증한 이벤트 개수
+	OK       bool   `json:"ok"`                 // 변조 흔적 없음
+	BrokenID uint   `json:"brokenId,omitempty"` // 처음으로 체인이 끊어진 이벤트 ID
+	Problem  string `json:"problem,omitempty"`  // 끊어진 이유 (missing, prev_hash, hash)
+	LastHash string `json:"lastHash,omitempty"` // 마지막 이벤트 해시 (서버 로그의 해시와 비교)
+}
+
+// Verify 처음부터 해시 체인을 다시 계산하여 누락, 순서 변경, 내용 변조 여부 확인
+func Verify() (VerifyResult, error) {
+	res := VerifyResult{OK: true}
+	prevID, prevHash := uint(0), genesisHash
+
+	var batch []db.AuditEvent
+	err := db.SqliteDB.Order("id").FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for i := range batch {
+			ev := &batch[i]
+			switch {
+			case ev.ID != prevID+1:
+				res.Problem = "missing"
+			case ev.PrevHash != prevHash:
+				res.Problem = "prev_hash"
+			case Hash(ev) != ev.Hash:
+				res.Problem = "hash"
+			}
+			if res.Problem != "" {
+				res.OK = false
+				res.BrokenID = ev.ID
+				return errBroken
+			}
+			prevID, prevHash = ev.ID, ev.Hash
+			res.Count++
+		}
+		return nil
+	}).Error
+	if err != nil && !errors.Is(err, errBroken) {
+		return res, err
+	}
+	if res.Count > 0 {
+		res.LastHash = prevHash
+	}
+	return res, nil
+}
@@ -17,16 +17,181 @@
 package db
 
 import (
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
 
+// 사용자 권한 등급
+const (
+	RoleAdmin    = "admin"    // 사용자 관리를 포함한 모든 기능
+	RoleOperator = "operator" // 웹 터미널 사용
+	RoleViewer   = "viewer"   // 조회 전용
+)
+
+// 권한 등급별 순위 (높을수록 상위 권한)
+var roleRanks = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
 type User struct {
 	gorm.Model
 	Username  string `gorm:"uniqueIndex;not null"`
 	Password  string `gorm:"not null"`
 	OTPSecret string `gorm:"default:null"`
-	IsAdmin   bool   `gorm:"index;default:false;not null"`
+	Role      string `gorm:"index;default:viewer;not null"`
+	Disabled  bool   `gorm:"default:false;not null"`
+	// 터미널 쉘을 실행할 리눅스 계정 (미설정 시 터미널 사용 불가)
+	UnixUser string `gorm:"default:null"`
+	// 루트(uid 0) 계정으로 쉘 실행 허용 여부
+	AllowRoot bool `gorm:"default:false;not null"`
+	// 마지막으로 사용된 OTP 시간 단계 (같은 시간 구간의 OTP 번호 재사용 방지)
+	OTPLastStep int64 `gorm:"default:0;not null"`
+}
+
+// 사용자 아이디 형식 (리눅스 계정 이름 규칙과 같이 소문자, 숫자, 밑줄, 점, 하이픈으로 32자 이하)
+var usernamePattern = regexp.MustCompile(`^[a-z_][a-z0-9_.-]{0,31}$`)
+
+// IsValidUsername 사용자 아이디 형식 확인 (아이디는 로그와 파일 경로 등에 그대로 쓰이므로 "/", "..", 제어 문자 불허)
+func IsValidUsername(name string) bool {
+	return usernamePattern.MatchString(name) && !strings.Contains(name, "..")
+}
+
+// IsValidRole 정의된 권한 등급인지 확인
+func IsValidRole(role string) bool {
+	_, ok := roleRanks[role]
+	return ok
+}
+
+// HasRole 사용자가 주어진 권한 등급 이상인지 확인
+func (u *User) HasRole(role string) bool {
+	return roleRanks[u.Role] >= roleRanks[role] && roleRanks[role] > 0
+}
+
+// IsAdmin 관리자 여부
+func (u *User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// WebAuthnCredential 사용자가 등록한 WebAuthn(패스키) 인증 장치
+type WebAuthnCredential struct {
+	gorm.Model
+	UserID       uint   `gorm:"index;not null"`
+	Name         string `gorm:"not null"`
+	CredentialID []byte `gorm:"uniqueIndex;not null"`
+	// 공개키, 서명 카운터 등 인증 장치 정보 (webauthn.Credential JSON)
+	Data       []byte     `gorm:"not null"`
+	LastUsedAt *time.Time `gorm:"default:null"`
+}
+
+// RecoveryCode OTP 분실 시 사용하는 1회용 복구 코드 (SHA-256 해시로 저장)
+type RecoveryCode struct {
+	gorm.Model
+	UserID   uint       `gorm:"index;not null"`
+	CodeHash string     `gorm:"index;not null"`
+	UsedAt   *time.Time `gorm:"default:null"`
+}
+
+// LoginAttempt 실패한 로그인 시도 기록 (보관 기간이 지나면 삭제)
+type LoginAttempt struct {
+	gorm.Model
+	Username string `gorm:"index;not null"`
+	ClientIP string `gorm:"index;not null"`
+	// 실패 사유 (password, otp, passkey, setup)
+	Reason string `gorm:"not null"`
+}
+
+// LoginThrottle 아이디 또는 접속 IP별 연속 로그인 실패 횟수와 대기(잠금) 상태
+type LoginThrottle struct {
+	gorm.Model
+	// 제한 대상 (user:아이디 또는 ip:주소)
+	Subject       string    `gorm:"uniqueIndex;not null"`
+	Failures      int       `gorm:"not null"`
+	LastFailureAt time.Time `gorm:"not null"`
+	BlockedUntil  time.Time `gorm:"index;not null"`
+	// 최대 실패 횟수 초과로 잠긴 상태 여부
+	Locked bool `gorm:"default:false;not null"`
+}
+
+// WebSession 서버 측에 저장하는 로그인 세션 (만료 시각이 지나면 삭제)
+type WebSession struct {
+	// 세션 ID (쿠키에는 서명된 값으로 저장)
+	ID        string `gorm:"primaryKey"`
+	UserID    uint   `gorm:"index;default:0;not null"` // 로그인 전이면 0
+	ClientIP  string `gorm:"not null"`
+	UserAgent string `gorm:"not null"`
+	// 세션 값 (gob 인코딩)
+	Data       []byte    `gorm:"not null"`
+	CreatedAt  time.Time `gorm:"not null"`
+	LastSeenAt time.Time `gorm:"not null"`
+	ExpiresAt  time.Time `gorm:"index;not null"`
+}
+
+// Recording 터미널 세션 녹화 정보
+type Recording struct {
+	gorm.Model
+	UserID     uint       `gorm:"index;not null"`
+	Username   string     `gorm:"not null"`
+	ClientIP   string     `gorm:"not null"`
+	FilePath   string     `gorm:"not null"`
+	StartedAt  time.Time  `gorm:"index;not null"`
+	EndedAt    *time.Time `gorm:"default:null"`
+	ExitStatus *int       `gorm:"default:null"`
+}
+
+// Snippet 자주 사용하는 명령 (본문에 {{이름}} 형식의 매개변수 포함 가능)
+type Snippet struct {
+	gorm.Model
+	UserID      uint   `gorm:"index;not null"` // 작성자
+	Name        string `gorm:"not null"`
+	Description string `gorm:"default:null"`
+	Command     string `gorm:"not null"`
+	// 공유 여부 (공유된 명령은 운영자 이상 모든 사용자가 사용 가능, 수정은 작성자와 관리자만 가능)
+	Shared bool `gorm:"index;default:false;not null"`
+}
+
+// SnippetRun 저장된 명령의 비대화형 실행 기록
+type SnippetRun struct {
+	gorm.Model
+	SnippetID   uint   `gorm:"index;not null"`
+	SnippetName string `gorm:"not null"`
+	UserID      uint   `gorm:"index;not null"`
+	Username    string `gorm:"not null"`
+	UnixUser    string `gorm:"not null"`
+	ClientIP    string `gorm:"not null"`
+	// 매개변수를 적용한 실제 실행 명령
+	Command string `gorm:"not null"`
+	// 실행 상태 (running, succeeded, failed, timeout, canceled, interrupted)
+	Status    string     `gorm:"index;not null"`
+	ExitCode  *int       `gorm:"default:null"`
+	Signal    string     `gorm:"default:null"`
+	Output    string     `gorm:"not null"`
+	Truncated bool       `gorm:"default:false;not null"` // 최대 크기를 넘은 출력을 버렸는지 여부
+	StartedAt time.Time  `gorm:"index;not null"`
+	EndedAt   *time.Time `gorm:"default:null"`
+}
+
+// AuditEvent 감사 로그 이벤트
+// 추가만 가능하며 (수정 및 삭제는 트리거로 차단), 각 이벤트는 이전 이벤트의 해시를 포함하는 해시 체인으로 연결됨
+type AuditEvent struct {
+	ID       uint      `gorm:"primaryKey;autoIncrement:false"`
+	Time     time.Time `gorm:"index;not null"`
+	Type     string    `gorm:"index;not null"`
+	UserID   uint      `gorm:"index;default:0;not null"` // 작업을 수행한 사용자 (알 수 없으면 0)
+	Username string    `gorm:"index;not null"`
+	ClientIP string    `gorm:"index;not null"`
+	// 작업 대상 (사용자 이름, 터미널 세션 ID 등)
+	Target string `gorm:"not null"`
+	// 이벤트 상세 정보 (JSON 객체)
+	Details  string `gorm:"not null"`
+	PrevHash string `gorm:"not null"`
+	Hash     string `gorm:"uniqueIndex;not null"`
 }
 
 var SqliteDB *gorm.DB
@@ -38,10 +203,76 @@ func InitSqliteDB(dbPath string) error {
 	if err != nil {
 		return err
 	}
-	SqliteDB.AutoMigrate(&User{})
+	// 리눅스 계정 매핑 도입 이전의 DB인지 확인
+	mapLegacy := SqliteDB.Migrator().HasTable(&User{}) && !SqliteDB.Migrator().HasColumn(&User{}, "unix_user")
+
+	SqliteDB.AutoMigrate(&User{}, &Recording{}, &WebAuthnCredential{}, &RecoveryCode{},
+		&LoginAttempt{}, &LoginThrottle{}, &WebSession{}, &Snippet{}, &SnippetRun{},
+		&AuditEvent{})
+
+	// 감사 로그는 추가만 가능하도록 수정 및 삭제 차단
+	for _, stmt := range []string{
+		"CREATE TRIGGER IF NOT EXISTS audit_events_no_update BEFORE UPDATE ON audit_events " +
+			"BEGIN SELECT RAISE(ABORT, 'audit events are append-only'); END",
+		"CREATE TRIGGER IF NOT EXISTS audit_events_no_delete BEFORE DELETE ON audit_events " +
+			"BEGIN SELECT RAISE(ABORT, 'audit events are append-only'); END",
+	} {
+		if err := SqliteDB.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+
+	// 단일 관리자(is_admin) 구조에서 권한 등급(role) 구조로 이전
+	if err := migrateLegacyAdmin(); err != nil {
+		return err
+	}
+
+	// 기존 관리자는 이전과 동일하게 데몬 실행 계정으로 쉘을 사용하도록 매핑
+	if mapLegacy {
+		return mapLegacyAdmins()
+	}
 	return nil
 }
 
+// mapLegacyAdmins 리눅스 계정이 매핑되지 않은 관리자를 데몬 실행 계정으로 매핑
+func mapLegacyAdmins() error {
+	current, err := user.Current()
+	if err != nil {
+		return err
+	}
+
+	return SqliteDB.Model(&User{}).
+		Where("role = ? AND (unix_user IS NULL OR unix_user = '')", RoleAdmin).
+		Updates(map[string]interface{}{
+			"unix_user":  current.Username,
+			"allow_root": current.Uid == "0",
+		}).Error
+}
+
+// migrateLegacyAdmin 기존 is_admin 컬럼을 role 컬럼으로 이전한 뒤 제거
+func migrateLegacyAdmin() error {
+	migrator := SqliteDB.Migrator()
+	if !migrator.HasColumn(&User{}, "is_admin") {
+		return nil
+	}
+
+	return SqliteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("UPDATE users SET role = ? WHERE is_admin = ?", RoleAdmin, true).Error; err != nil {
+			return err
+		}
+		if migrator.HasIndex(&User{}, "idx_users_is_admin") {
+			if err := tx.Migrator().DropIndex(&User{}, "idx_users_is_admin"); err != nil {
+				return err
+			}
+		}
+		if err := tx.Migrator().DropColumn(&User{}, "is_admin"); err != nil {
+			return err
+		}
+		// SQLite는 컬럼 삭제 시 테이블을 재생성하므로 인덱스 재생성
+		return tx.AutoMigrate(&User{})
+	})
+}
+
 // CloseSqliteDB SQLite DB 연결 해제
 func CloseSqliteDB() {
 	if SqliteDB != nil {
@@ -0,0 +1,336 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemgr
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/terminal"
+	"github.com/hoon-x/rootweb/pkg/passwd"
+)
+
+var (
+	ErrNotFound   = errors.New("file or directory not found")
+	ErrPermission = errors.New("permission denied")
+	ErrExist      = errors.New("file already exists")
+	ErrNotEmpty   = errors.New("directory is not empty")
+	ErrOffset     = errors.New("upload offset does not match")
+	ErrInvalid    = errors.New("invalid file operation")
+)
+
+// 파일 항목 종류
+const (
+	TypeDir     = "dir"
+	TypeFile    = "file"
+	TypeSymlink = "symlink"
+	TypeOther   = "other"
+)
+
+// 업로드 중인 파일의 임시 파일명 접미사 (대상 파일과 같은 디렉터리에 숨김 파일로 생성)
+const partialSuffix = ".rootweb-upload"
+
+// Entry 디렉터리 목록 항목 및 파일 정보
+type Entry struct {
+	Name    string    `json:"name"`
+	Type    string    `json:"type"`
+	Size    int64     `json:"size"`
+	Mode    string    `json:"mode"` // ls 형식 (drwxr-xr-x)
+	Perm    string    `json:"perm"` // 8진수 권한 (0755)
+	ModTime time.Time `json:"modTime"`
+	Owner   string    `json:"owner"`
+	Group   string    `json:"group"`
+	Target  string    `json:"target,omitempty"` // 심볼릭 링크 대상 경로
+	IsDir   bool      `json:"isDir"`            // 디렉터리 또는 디렉터리를 가리키는 심볼릭 링크
+}
+
+// FS 사용자에 매핑된 리눅스 계정 권한으로 파일 작업을 수행하는 파일 시스템
+// 모든 작업은 해당 계정의 uid/gid/보조 그룹으로 실행한 도우미 프로세스에서 수행되므로
+// 터미널에서와 동일한 파일 접근 권한이 적용됨
+type FS struct {
+	Account *passwd.Account
+	cred    *syscall.Credential
+}
+
+// 도우미 프로세스로 실행할 현재 실행 파일
+// 실행 파일 경로의 상위 디렉터리에 접근 권한이 없는 계정도 실행할 수 있도록 열어둔 파일을 /proc/self/fd로 실행
+var (
+	exeOnce sync.Once
+	exeFile *os.File
+	exeErr  error
+)
+
+// helperExecFd 도우미 프로세스에서 실행 파일이 위치하는 파일 디스크립터 (ExtraFiles 첫 번째 항목)
+const helperExecFd = 3
+
+// Open 사용자에 매핑된 리눅스 계정 조회 및 접근 정책 확인 (터미널과 동일한 정책 적용)
+func Open(user *db.User) (*FS, error) {
+	acc, err := terminal.LookupUnixAccount(user)
+	if err != nil {
+		return nil, err
+	}
+	return &FS{Account: acc, cred: terminal.UnixCredential(acc)}, nil
+}
+
+// PartialPath 업로드 중인 파일의 임시 파일 경로
+func PartialPath(path string) string {
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+partialSuffix)
+}
+
+// CleanPath 요청 경로를 절대 경로로 정리 (상대 경로는 거부)
+func CleanPath(path string) (string, error) {
+	if !filepath.IsAbs(path) || strings.ContainsRune(path, 0) {
+		return "", fmt.Errorf("%w: path must be absolute", ErrInvalid)
+	}
+	return filepath.Clean(path), nil
+}
+
+// List 디렉터리 항목 조회
+func (f *FS) List(ctx context.Context, dir string) ([]Entry, error) {
+	var entries []Entry
+	if err := f.runJSON(ctx, nil, &entries, "list", dir); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Mkdir 디렉터리 생성
+func (f *FS) Mkdir(ctx context.Context, path string) error {
+	return f.run(ctx, nil, io.Discard, "mkdir", path)
+}
+
+// Rename 이름 변경 및 이동 (대상 경로에 이미 항목이 있으면 ErrExist)
+func (f *FS) Rename(ctx context.Context, from, to string) error {
+	return f.run(ctx, nil, io.Discard, "rename", from, to)
+}
+
+// Remove 파일 또는 디렉터리 삭제 (recursive가 false이면 비어 있는 디렉터리만 삭제)
+func (f *FS) Remove(ctx context.Context, path string, recursive bool) error {
+	return f.run(ctx, nil, io.Discard, "remove", path, flag(recursive))
+}
+
+// Chmod 권한 변경 (mode: 8진수 문자열)
+func (f *FS) Chmod(ctx context.Context, path, mode string) error {
+	return f.run(ctx, nil, io.Discard, "chmod", path, mode)
+}
+
+// Exists 경로에 항목이 있는지 확인
+func (f *FS) Exists(ctx context.Context, path string) (bool, error) {
+	var size int64
+	err := f.runJSON(ctx, nil, &size, "size", path)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// UploadOffset 중단된 업로드의 임시 파일 크기 (이어서 전송할 위치, 임시 파일이 없으면 0)
+func (f *FS) UploadOffset(ctx context.Context, path string) (int64, error) {
+	var size int64
+	err := f.runJSON(ctx, nil, &size, "size", PartialPath(path))
+	if errors.Is(err, ErrNotFound) {
+		return 0, nil
+	}
+	return size, err
+}
+
+// WriteChunk 업로드 임시 파일의 offset 위치부터 데이터 기록 후 기록된 전체 크기 반환
+// offset이 0이면 새로 시작하고, 그 외에는 이전까지 기록된 크기와 같아야 함 (다르면 ErrOffset)
+func (f *FS) WriteChunk(ctx context.Context, path string, offset int64, r io.Reader) (int64, error) {
+	var size int64
+	err := f.runJSON(ctx, r, &size, "write", PartialPath(path), strconv.FormatInt(offset, 10))
+	return size, err
+}
+
+// CompleteUpload 업로드가 끝난 임시 파일을 대상 경로로 이동
+func (f *FS) CompleteUpload(ctx context.Context, path string, overwrite bool) error {
+	return f.run(ctx, nil, io.Discard, "commit", PartialPath(path), path, flag(overwrite))
+}
+
+// CancelUpload 중단된 업로드의 임시 파일 삭제
+func (f *FS) CancelUpload(ctx context.Context, path string) error {
+	return f.run(ctx, nil, io.Discard, "remove", PartialPath(path), flag(false))
+}
+
+// Download 파일 내용 스트림
+type Download struct {
+	Info   Entry
+	r      *bufio.Reader
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+// Download 파일의 offset 위치부터 length 바이트를 읽는 스트림 생성 (length < 0이면 끝까지)
+// offset이 파일 크기를 넘으면 Info만 채워지고 내용은 비어 있음
+func (f *FS) Download(ctx context.Context, path string, offset, length int64) (*Download, error) {
+	cmd, err := f.command(ctx, "read", path, strconv.FormatInt(offset, 10), strconv.FormatInt(length, 10))
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = &limitedBuffer{buf: stderr}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	d := &Download{r: bufio.NewReader(stdout), cmd: cmd, stderr: stderr}
+	// 첫 줄의 파일 정보를 읽지 못하면 파일을 열지 못한 것이므로 도우미 프로세스의 오류 반환
+	line, err := d.r.ReadBytes('\n')
+	if err == nil {
+		err = json.Unmarshal(line, &d.Info)
+	}
+	if err != nil {
+		if werr := helperError(cmd.Wait(), stderr); werr != nil {
+			return nil, werr
+		}
+		return nil, err
+	}
+	return d, nil
+}
+
+// Read 파일 내용 읽기
+func (d *Download) Read(p []byte) (int, error) {
+	return d.r.Read(p)
+}
+
+// Close 스트림 종료 (끝까지 읽지 않았으면 도우미 프로세스를 종료하고 결과는 무시)
+func (d *Download) Close() error {
+	if _, err := d.r.Peek(1); err != io.EOF {
+		d.cmd.Process.Kill()
+		d.cmd.Wait()
+		return nil
+	}
+	return helperError(d.cmd.Wait(), d.stderr)
+}
+
+// runJSON 도우미 프로세스 실행 후 출력된 JSON 결과 해석
+func (f *FS) runJSON(ctx context.Context, stdin io.Reader, v any, op string, args ...string) error {
+	var out bytes.Buffer
+	if err := f.run(ctx, stdin, &out, op, args...); err != nil {
+		return err
+	}
+	return json.Unmarshal(out.Bytes(), v)
+}
+
+// run 도우미 프로세스를 실행하고 종료 코드를 오류로 변환
+func (f *FS) run(ctx context.Context, stdin io.Reader, stdout io.Writer, op string, args ...string) error {
+	cmd, err := f.command(ctx, op, args...)
+	if err != nil {
+		return err
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = &limitedBuffer{buf: stderr}
+	return helperError(cmd.Run(), stderr)
+}
+
+// command 리눅스 계정 권한으로 실행할 도우미 프로세스 명령 생성
+func (f *FS) command(ctx context.Context, op string, args ...string) (*exec.Cmd, error) {
+	exeOnce.Do(func() {
+		exeFile, exeErr = os.Open("/proc/self/exe")
+	})
+	if exeErr != nil {
+		return nil, exeErr
+	}
+
+	cmd := exec.CommandContext(ctx, "/proc/self/fd/"+strconv.Itoa(helperExecFd),
+		append([]string{HelperCommand, op}, args...)...)
+	cmd.Args[0] = config.ModuleName
+	cmd.ExtraFiles = []*os.File{exeFile}
+	cmd.Env = []string{
+		"HOME=" + f.Account.Home,
+		"USER=" + f.Account.Name,
+		"LOGNAME=" + f.Account.Name,
+		"PATH=/usr/bin:/bin",
+	}
+	cmd.Dir = "/"
+	if f.cred != nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Credential: f.cred}
+	}
+	return cmd, nil
+}
+
+// helperError 도우미 프로세스 종료 코드를 오류로 변환 (stderr 메시지 포함)
+func helperError(err error, stderr *bytes.Buffer) error {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return err
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	code := exitErr.ExitCode()
+	if code > 0 && code < len(exitErrors) && exitErrors[code] != nil {
+		if msg == "" {
+			return exitErrors[code]
+		}
+		// 도우미 프로세스가 출력한 메시지에는 이미 오류 종류가 포함되어 있음
+		return &helperErr{kind: exitErrors[code], msg: msg}
+	}
+	if msg == "" {
+		return err
+	}
+	return errors.New(msg)
+}
+
+// helperErr 오류 종류(errors.Is 비교용)와 도우미 프로세스 메시지
+type helperErr struct {
+	kind error
+	msg  string
+}
+
+func (e *helperErr) Error() string { return e.msg }
+func (e *helperErr) Unwrap() error { return e.kind }
+
+// limitedBuffer 오류 메시지 수집용 크기 제한 버퍼
+type limitedBuffer struct {
+	buf *bytes.Buffer
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remain := 4096 - b.buf.Len(); remain > 0 {
+		b.buf.Write(p[:min(len(p), remain)])
+	}
+	return len(p), nil
+}
+
+// flag 도우미 프로세스 인자용 불리언 값
+func flag(v bool) string {
+	if v {
+		return "1"
+	}
+	return "0"
+}
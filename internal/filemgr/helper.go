@@ -0,0 +1,395 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package filemgr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"syscall"
+)
+
+// HelperCommand 파일 작업 도우미 프로세스 실행에 사용하는 숨김 하위 명령어
+const HelperCommand = "fs-helper"
+
+// 도우미 프로세스 종료 코드별 오류 (인덱스가 종료 코드, 1은 그 외 오류)
+var exitErrors = []error{nil, nil, ErrNotFound, ErrPermission, ErrExist, ErrNotEmpty, ErrOffset, ErrInvalid}
+
+// RunHelper 도우미 프로세스 진입점 (사용자의 리눅스 계정 권한으로 실행됨)
+// 작업 결과는 stdout, 오류 메시지는 stderr로 출력하고 종료 코드 반환
+func RunHelper(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	// 데몬의 umask(0)를 상속하므로 일반적인 로그인 환경과 같은 권한으로 생성
+	syscall.Umask(0o022)
+
+	err := runHelperOp(args, stdin, stdout)
+	if err == nil {
+		return 0
+	}
+	fmt.Fprintln(stderr, err)
+	for code, e := range exitErrors {
+		if e != nil && errors.Is(err, e) {
+			return code
+		}
+	}
+	return 1
+}
+
+// runHelperOp 작업 종류에 따라 파일 작업 수행
+func runHelperOp(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) < 2 {
+		return ErrInvalid
+	}
+	op, path := args[0], args[1]
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: path must be absolute", ErrInvalid)
+	}
+
+	arg := func(i int) string {
+		if i < len(args) {
+			return args[i]
+		}
+		return ""
+	}
+	num := func(i int) (int64, error) {
+		n, err := strconv.ParseInt(arg(i), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		return n, nil
+	}
+
+	switch op {
+	case "list":
+		entries, err := listDir(path)
+		if err != nil {
+			return convertError(err)
+		}
+		return json.NewEncoder(stdout).Encode(entries)
+
+	case "read":
+		offset, err := num(2)
+		if err != nil {
+			return err
+		}
+		length, err := num(3)
+		if err != nil {
+			return err
+		}
+		return convertError(readFile(path, offset, length, stdout))
+
+	case "write":
+		offset, err := num(2)
+		if err != nil {
+			return err
+		}
+		size, err := writeFile(path, offset, stdin)
+		if err != nil {
+			return convertError(err)
+		}
+		return json.NewEncoder(stdout).Encode(size)
+
+	case "size":
+		info, err := os.Stat(path)
+		if err != nil {
+			return convertError(err)
+		}
+		return json.NewEncoder(stdout).Encode(info.Size())
+
+	case "commit":
+		return convertError(commitFile(path, arg(2), arg(3) == "1"))
+
+	case "mkdir":
+		return convertError(os.Mkdir(path, 0o777))
+
+	case "rename":
+		return convertError(renameFile(path, arg(2)))
+
+	case "remove":
+		if arg(2) == "1" {
+			if _, err := os.Lstat(path); err != nil {
+				return convertError(err)
+			}
+			return convertError(os.RemoveAll(path))
+		}
+		return convertError(os.Remove(path))
+
+	case "chmod":
+		mode, err := strconv.ParseUint(arg(2), 8, 32)
+		if err != nil || mode > 0o7777 {
+			return fmt.Errorf("%w: invalid mode %q", ErrInvalid, arg(2))
+		}
+		return convertError(os.Chmod(path, fs.FileMode(mode)&fs.ModePerm|modeBits(uint32(mode))))
+	}
+	return fmt.Errorf("%w: unknown operation %q", ErrInvalid, op)
+}
+
+// listDir 디렉터리 항목을 이름순(디렉터리 먼저)으로 조회
+func listDir(dir string) ([]Entry, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := newOwnerNames()
+	entries := make([]Entry, 0, len(dirEntries))
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil {
+			// 조회 도중 삭제된 항목
+			continue
+		}
+		entries = append(entries, newEntry(filepath.Join(dir, de.Name()), info, names))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].IsDir != entries[j].IsDir {
+			return entries[i].IsDir
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries, nil
+}
+
+// readFile 파일 정보를 한 줄의 JSON으로 출력한 뒤 offset부터 length 바이트 출력 (length < 0이면 끝까지)
+func readFile(path string, offset, length int64, w io.Writer) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%w: not a regular file", ErrInvalid)
+	}
+	if err := json.NewEncoder(w).Encode(newEntry(path, info, newOwnerNames())); err != nil {
+		return err
+	}
+
+	// 범위를 벗어난 요청은 정보만 출력 (요청 측에서 범위 오류 처리)
+	if offset < 0 || offset > info.Size() {
+		return nil
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+	if length < 0 {
+		_, err = io.Copy(w, file)
+	} else {
+		_, err = io.CopyN(w, file, length)
+		if errors.Is(err, io.EOF) {
+			err = nil
+		}
+	}
+	return err
+}
+
+// writeFile 업로드 임시 파일의 offset 위치부터 입력 데이터 기록 후 파일 크기 반환
+// offset이 0이면 새로 작성하고, 그 외에는 현재 파일 크기와 같아야 이어서 기록
+func writeFile(path string, offset int64, r io.Reader) (int64, error) {
+	flags := os.O_WRONLY | os.O_CREATE
+	if offset == 0 {
+		flags |= os.O_TRUNC
+	}
+	// 업로드가 끝나기 전에는 다른 사용자가 읽지 못하도록 소유자 전용 권한으로 생성
+	file, err := os.OpenFile(path, flags, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("%w: not a regular file", ErrInvalid)
+	}
+	if info.Size() != offset {
+		return info.Size(), fmt.Errorf("%w: expected %d, got %d", ErrOffset, info.Size(), offset)
+	}
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(file, r)
+	if err != nil {
+		return offset + n, err
+	}
+	if err := file.Sync(); err != nil {
+		return offset + n, err
+	}
+	return offset + n, nil
+}
+
+// commitFile 업로드가 끝난 임시 파일을 일반 파일 권한으로 변경 후 대상 경로로 이동
+func commitFile(partial, target string, overwrite bool) error {
+	if !filepath.IsAbs(target) {
+		return fmt.Errorf("%w: path must be absolute", ErrInvalid)
+	}
+	if !overwrite {
+		if _, err := os.Lstat(target); err == nil {
+			return fs.ErrExist
+		}
+	}
+	if err := os.Chmod(partial, 0o666&^0o022); err != nil {
+		return err
+	}
+	return os.Rename(partial, target)
+}
+
+// renameFile 이름 변경 (대상 경로에 이미 항목이 있으면 덮어쓰지 않음)
+func renameFile(from, to string) error {
+	if !filepath.IsAbs(to) {
+		return fmt.Errorf("%w: path must be absolute", ErrInvalid)
+	}
+	if _, err := os.Lstat(to); err == nil {
+		return fs.ErrExist
+	}
+	return os.Rename(from, to)
+}
+
+// modeBits 8진수 권한 값의 setuid, setgid, sticky 비트를 FileMode로 변환
+func modeBits(mode uint32) fs.FileMode {
+	var m fs.FileMode
+	if mode&syscall.S_ISUID != 0 {
+		m |= fs.ModeSetuid
+	}
+	if mode&syscall.S_ISGID != 0 {
+		m |= fs.ModeSetgid
+	}
+	if mode&syscall.S_ISVTX != 0 {
+		m |= fs.ModeSticky
+	}
+	return m
+}
+
+// newEntry 파일 정보를 목록 항목으로 변환 (심볼릭 링크는 대상이 디렉터리인지 함께 확인)
+func newEntry(path string, info fs.FileInfo, names *ownerNames) Entry {
+	e := Entry{
+		Name:    info.Name(),
+		Size:    info.Size(),
+		Mode:    info.Mode().String(),
+		Perm:    fmt.Sprintf("%04o", uint32(info.Mode().Perm())|permBits(info.Mode())),
+		ModTime: info.ModTime(),
+	}
+
+	switch {
+	case info.IsDir():
+		e.Type = TypeDir
+		e.IsDir = true
+	case info.Mode()&fs.ModeSymlink != 0:
+		e.Type = TypeSymlink
+		e.Target, _ = os.Readlink(path)
+		if target, err := os.Stat(path); err == nil && target.IsDir() {
+			e.IsDir = true
+		}
+	case info.Mode().IsRegular():
+		e.Type = TypeFile
+	default:
+		e.Type = TypeOther
+	}
+
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		e.Owner = names.user(st.Uid)
+		e.Group = names.group(st.Gid)
+	}
+	return e
+}
+
+// permBits FileMode의 setuid, setgid, sticky 비트를 8진수 권한 값으로 변환
+func permBits(mode fs.FileMode) uint32 {
+	var m uint32
+	if mode&fs.ModeSetuid != 0 {
+		m |= syscall.S_ISUID
+	}
+	if mode&fs.ModeSetgid != 0 {
+		m |= syscall.S_ISGID
+	}
+	if mode&fs.ModeSticky != 0 {
+		m |= syscall.S_ISVTX
+	}
+	return m
+}
+
+// ownerNames uid/gid 이름 조회 캐시
+type ownerNames struct {
+	users  map[uint32]string
+	groups map[uint32]string
+}
+
+func newOwnerNames() *ownerNames {
+	return &ownerNames{users: map[uint32]string{}, groups: map[uint32]string{}}
+}
+
+// user uid에 해당하는 계정 이름 (계정이 없으면 uid 숫자)
+func (n *ownerNames) user(uid uint32) string {
+	if name, ok := n.users[uid]; ok {
+		return name
+	}
+	id := strconv.FormatUint(uint64(uid), 10)
+	name := id
+	if u, err := user.LookupId(id); err == nil {
+		name = u.Username
+	}
+	n.users[uid] = name
+	return name
+}
+
+// group gid에 해당하는 그룹 이름 (그룹이 없으면 gid 숫자)
+func (n *ownerNames) group(gid uint32) string {
+	if name, ok := n.groups[gid]; ok {
+		return name
+	}
+	id := strconv.FormatUint(uint64(gid), 10)
+	name := id
+	if g, err := user.LookupGroupId(id); err == nil {
+		name = g.Name
+	}
+	n.groups[gid] = name
+	return name
+}
+
+// convertError 시스템 오류를 종료 코드로 구분할 수 있는 오류로 변환
+func convertError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, syscall.ENOTEMPTY):
+		return fmt.Errorf("%w: %v", ErrNotEmpty, err)
+	case errors.Is(err, syscall.EISDIR), errors.Is(err, syscall.ENOTDIR), errors.Is(err, syscall.EXDEV),
+		errors.Is(err, syscall.EINVAL):
+		return fmt.Errorf("%w: %v", ErrInvalid, err)
+	case errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	case errors.Is(err, fs.ErrPermission):
+		return fmt.Errorf("%w: %v", ErrPermission, err)
+	case errors.Is(err, fs.ErrExist):
+		return fmt.Errorf("%w: %v", ErrExist, err)
+	}
+	return err
+}
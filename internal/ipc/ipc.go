@@ -58,7 +58,7 @@ func Run(ctx context.Context) {
 		for evt := range IpcMgr.ipcChan {
 			switch evt {
 			case Shutdown:
-				logger.LogInfo("Shutdown event received")
+				logger.IPC.Infow("Shutdown event received")
 				// 현재 프로세스에 종료 시그널 전송
 				proc.SendSignal(config.RunConf.Pid, syscall.SIGTERM)
 			}
@@ -0,0 +1,101 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// AccessEntry 웹 요청 접근 로그 항목
+type AccessEntry struct {
+	RequestID string
+	Method    string
+	Path      string
+	Query     string            // 민감한 값을 가린 쿼리 문자열
+	Form      map[string]string // 민감한 값을 가린 폼 필드 (핸들러가 폼을 읽은 경우만)
+	Status    int
+	Latency   time.Duration
+	Bytes     int
+	ClientIP  string
+	UserID    uint // 로그인 전 요청이면 0
+	Error     string
+}
+
+var accessLogger syncLogger
+
+// InitializeAccessLogger 접근 로그 전용 로거 초기화 (서버 로그와 별도 파일에 JSON 한 줄씩 기록)
+func InitializeAccessLogger(logPath string, maxSize, maxBackups, maxAge int, compress bool) {
+	accessLogger.fileLogger = &lumberjack.Logger{
+		Filename:   logPath,
+		MaxSize:    maxSize,
+		MaxBackups: maxBackups,
+		MaxAge:     maxAge,
+		Compress:   compress,
+	}
+
+	encoder := zapcore.NewJSONEncoder(zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.MillisDurationEncoder,
+	})
+	core := zapcore.NewCore(encoder, zapcore.AddSync(accessLogger.fileLogger), zapcore.InfoLevel)
+	accessLogger.zapLogger = zap.New(core)
+}
+
+// LogAccess 접근 로그 기록 (접근 로거가 초기화되지 않았으면 무시)
+func LogAccess(e *AccessEntry) {
+	if accessLogger.zapLogger == nil {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("requestId", e.RequestID),
+		zap.String("method", e.Method),
+		zap.String("path", e.Path),
+	}
+	if e.Query != "" {
+		fields = append(fields, zap.String("query", e.Query))
+	}
+	if len(e.Form) > 0 {
+		fields = append(fields, zap.Any("form", e.Form))
+	}
+	fields = append(fields,
+		zap.Int("status", e.Status),
+		zap.Duration("latency", e.Latency),
+		zap.Int("bytes", e.Bytes),
+		zap.String("clientIp", e.ClientIP),
+		zap.Uint("userId", e.UserID),
+	)
+	if e.Error != "" {
+		fields = append(fields, zap.String("error", e.Error))
+	}
+	accessLogger.zapLogger.Info("", fields...)
+}
+
+// finalizeAccessLogger 접근 로거 자원 정리
+func finalizeAccessLogger() {
+	if accessLogger.zapLogger == nil {
+		return
+	}
+	accessLogger.zapLogger.Sync()
+	accessLogger.fileLogger.Close()
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// journald 네이티브 프로토콜 소켓 경로
+var journalSocket = "/run/systemd/journal/socket"
+
+// JournaldOptions journald 출력처 설정
+type JournaldOptions struct {
+	Tag    string // SYSLOG_IDENTIFIER 필드에 기록할 프로그램 이름
+	Level  string // 보낼 최소 로그 레벨 (비어 있으면 info)
+	Format string // MESSAGE 필드 형식 (FormatConsole, FormatJSON)
+}
+
+// AddJournaldSink journald 네이티브 프로토콜로 로그를 보내는 출력처 추가
+// 구조화 필드는 MESSAGE에 포함하는 것과 별도로 대문자 이름의 journald 필드로도 기록하여 journalctl로 검색할 수 있음
+func AddJournaldSink(opts JournaldOptions) error {
+	lvl, err := parseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+
+	w := &journalWriter{}
+	if err := w.connect(); err != nil {
+		return err
+	}
+	enc := messageEncoder(opts.Format)
+
+	addSink(&sinkCore{
+		LevelEnabler: lvl,
+		write: func(ent zapcore.Entry, fields []zapcore.Field) error {
+			buf, err := enc.EncodeEntry(ent, fields)
+			if err != nil {
+				return err
+			}
+			defer buf.Free()
+
+			var msg bytes.Buffer
+			appendJournalField(&msg, "MESSAGE", strings.TrimSuffix(buf.String(), "\n"))
+			appendJournalField(&msg, "PRIORITY", strconv.Itoa(syslogSeverity(ent.Level)))
+			appendJournalField(&msg, "SYSLOG_IDENTIFIER", opts.Tag)
+			if ent.LoggerName != "" {
+				appendJournalField(&msg, "MODULE", ent.LoggerName)
+			}
+			if ent.Caller.Defined {
+				appendJournalField(&msg, "CODE_FILE", ent.Caller.File)
+				appendJournalField(&msg, "CODE_LINE", strconv.Itoa(ent.Caller.Line))
+				appendJournalField(&msg, "CODE_FUNC", ent.Caller.Function)
+			}
+
+			values := zapcore.NewMapObjectEncoder()
+			for _, f := range fields {
+				f.AddTo(values)
+			}
+			for key, v := range values.Fields {
+				appendJournalField(&msg, journalFieldName(key), journalFieldValue(v))
+			}
+			return w.write(msg.Bytes())
+		},
+	}, w)
+	return nil
+}
+
+// appendJournalField 필드를 journald 네이티브 프로토콜 형식으로 추가
+// 줄바꿈이 포함된 값은 이름 다음에 값의 길이(64비트 리틀 엔디언)를 붙이는 형식을 사용
+func appendJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if strings.ContainsRune(value, '\n') {
+		buf.WriteByte('\n')
+		binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	} else {
+		buf.WriteByte('=')
+	}
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName 구조화 필드 키를 journald 필드 이름 규칙(대문자, 숫자, 밑줄, 문자로 시작)에 맞게 변환
+func journalFieldName(key string) string {
+	b := make([]byte, 0, len(key)+2)
+	for i := 0; i < len(key) && len(b) < 64; i++ {
+		switch ch := key[i]; {
+		case ch >= 'a' && ch <= 'z':
+			b = append(b, ch-'a'+'A')
+		case ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+			b = append(b, ch)
+		default:
+			b = append(b, '_')
+		}
+	}
+	// 밑줄로 시작하는 이름은 journald가 예약하므로 접두어를 붙임
+	if len(b) == 0 || b[0] < 'A' || b[0] > 'Z' {
+		return "F_" + string(b)
+	}
+	return string(b)
+}
+
+// journalFieldValue 구조화 필드 값을 문자열로 변환 (맵이나 배열은 JSON)
+func journalFieldValue(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// journalWriter journald 소켓 연결
+type journalWriter struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// connect journald 소켓 연결
+func (w *journalWriter) connect() error {
+	conn, err := net.Dial("unixgram", journalSocket)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
+
+// write 항목 전송 (실패하면 한 번 다시 연결하여 재전송)
+func (w *journalWriter) write(msg []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if err = w.connect(); err != nil {
+				continue
+			}
+		}
+		if _, err = w.conn.Write(msg); err == nil {
+			return nil
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	return err
+}
+
+// Close journald 소켓 연결 종료
+func (w *journalWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
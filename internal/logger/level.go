@@ -0,0 +1,71 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels 실행 중 지정할 수 있는 로그 레벨 목록
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// parseLevel 로그 레벨 이름 해석 (비어 있으면 info, 해석할 수 없으면 info와 오류 반환)
+func parseLevel(name string) (zapcore.Level, error) {
+	if name == "" {
+		return zapcore.InfoLevel, nil
+	}
+	for _, l := range Levels {
+		if name == l {
+			var parsed zapcore.Level
+			err := parsed.UnmarshalText([]byte(name))
+			return parsed, err
+		}
+	}
+	return zapcore.InfoLevel, fmt.Errorf("unknown log level: %s", name)
+}
+
+// Level 현재 파일에 기록하는 최소 로그 레벨
+func Level() string {
+	return level.Level().String()
+}
+
+// BaseLevel 설정 파일에 지정된 기본 로그 레벨
+func BaseLevel() string {
+	return baseLevel.String()
+}
+
+// SetLevel 재시작 없이 파일에 기록할 최소 로그 레벨 변경 (재시작하면 설정 파일의 레벨로 돌아감)
+func SetLevel(name string) error {
+	parsed, err := parseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.SetLevel(parsed)
+	return nil
+}
+
+// ToggleDebugLevel 디버그 레벨과 기본 레벨 사이를 전환하고 변경된 레벨 반환
+func ToggleDebugLevel() string {
+	if level.Level() == zapcore.DebugLevel && baseLevel != zapcore.DebugLevel {
+		level.SetLevel(baseLevel)
+	} else {
+		level.SetLevel(zapcore.DebugLevel)
+	}
+	return Level()
+}
@@ -18,7 +18,9 @@ package logger
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sync"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -28,12 +30,28 @@ import (
 type syncLogger struct {
 	fileLogger *lumberjack.Logger
 	zapLogger  *zap.Logger
+	sugar      *zap.SugaredLogger
+	modules    sync.Map    // 하위 시스템 이름별 *zap.SugaredLogger
+	sinks      []io.Closer // 추가된 출력처(syslog, journald) 연결
 }
 
 var logger syncLogger
 
+// 로그 출력 형식
+const (
+	FormatConsole = "console" // 사람이 읽기 쉬운 한 줄 형식 (기본값)
+	FormatJSON    = "json"    // 로그 수집기가 읽기 쉬운 JSON 한 줄 형식
+)
+
+// 파일에 기록할 최소 로그 레벨 (실행 중 변경 가능)
+var level = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+// 설정 파일에 지정된 기본 로그 레벨 (디버그 로그 전환 후 되돌아갈 레벨)
+var baseLevel = zapcore.InfoLevel
+
 // InitializeLogger 로거를 초기화하고 파일 및 콘솔 출력 설정 구성
-func InitializeLogger(logPath string, maxSize, maxBackups, maxAge int, compress, debug bool) {
+// format: 파일 로그 형식 (FormatConsole, FormatJSON), levelName: 파일에 기록할 최소 레벨 (비어 있으면 info)
+func InitializeLogger(logPath string, maxSize, maxBackups, maxAge int, compress, debug bool, format, levelName string) {
 	var cores []zapcore.Core
 
 	// Lumberjack 설정: 로그 파일의 로테이션(용량 제한, 보관 기간 등)을 관리
@@ -50,20 +68,35 @@ func InitializeLogger(logPath string, maxSize, maxBackups, maxAge int, compress,
 		MessageKey:       "msg",
 		LevelKey:         "level",
 		TimeKey:          "time",
+		NameKey:          "module",
 		StacktraceKey:    "stacktrace",
 		LineEnding:       zapcore.DefaultLineEnding,
 		EncodeLevel:      capitalLevelEncoder,
 		EncodeTime:       zapcore.TimeEncoderOfLayout("[2006-01-02 15:04:05]"),
 		EncodeDuration:   zapcore.SecondsDurationEncoder,
+		EncodeName:       bracketNameEncoder,
 		ConsoleSeparator: " ",
 	}
 
 	// 파일 출력 코어(Core) 설정
 	// 파일에는 Caller(호출 위치) 정보를 남기지 않기 위해 기본 설정을 그대로 사용
-	fileEncoder := zapcore.NewConsoleEncoder(baseEncoderConfig)
+	var fileEncoder zapcore.Encoder
+	if format == FormatJSON {
+		jsonEncoderConfig := baseEncoderConfig
+		jsonEncoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+		jsonEncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+		jsonEncoderConfig.EncodeDuration = zapcore.MillisDurationEncoder
+		jsonEncoderConfig.EncodeName = zapcore.FullNameEncoder
+		fileEncoder = zapcore.NewJSONEncoder(jsonEncoderConfig)
+	} else {
+		fileEncoder = zapcore.NewConsoleEncoder(baseEncoderConfig)
+	}
 	fileWriter := zapcore.AddSync(logger.fileLogger)
-	// Info 레벨 이상의 로그(Info, Warn, Error 등)만 파일에 저장
-	cores = append(cores, zapcore.NewCore(fileEncoder, fileWriter, zapcore.InfoLevel))
+	// 설정된 레벨 이상의 로그만 파일에 저장 (기본값 Info, 실행 중 SetLevel로 변경 가능)
+	parsed, levelErr := parseLevel(levelName)
+	baseLevel = parsed
+	level.SetLevel(parsed)
+	cores = append(cores, zapcore.NewCore(fileEncoder, fileWriter, level))
 
 	// 디버그 모드 전용 콘솔 출력 설정
 	if debug {
@@ -91,11 +124,19 @@ func InitializeLogger(logPath string, maxSize, maxBackups, maxAge int, compress,
 		zap.AddCaller(),
 		zap.AddCallerSkip(1),
 		zap.AddStacktrace(zapcore.PanicLevel))
+	logger.sugar = logger.zapLogger.Sugar()
+	logger.modules.Clear()
+
+	if levelErr != nil {
+		LogWarn("Invalid log level %q, using %s", levelName, parsed)
+	}
 }
 
-// FinalizeLogger 로거 자원 정리
+// FinalizeLogger 로거 자원 정리 (접근 로거 및 추가된 출력처 포함)
 func FinalizeLogger() {
+	finalizeAccessLogger()
 	logger.zapLogger.Sync()
+	closeSinks()
 	logger.fileLogger.Close()
 }
 
@@ -104,12 +145,17 @@ func capitalLevelEncoder(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString("[" + l.CapitalString() + "]")
 }
 
+// bracketNameEncoder 콘솔 형식에서 하위 시스템 이름을 대괄호로 감싸서 출력
+func bracketNameEncoder(name string, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendString("[" + name + "]")
+}
+
 // shortCallerEncoder zapcore의 ShortCallerEncoder() 메서드 커스터마이징
 func shortCallerEncoder(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString("[" + caller.TrimmedPath() + "]")
 }
 
-// LogDebug DEBUG 레벨 로깅 (콘솔 출력만)
+// LogDebug DEBUG 레벨 로깅 (콘솔 출력 및 디버그 레벨 설정 시 파일)
 func LogDebug(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	logger.zapLogger.Debug(message)
@@ -146,3 +192,23 @@ func LogFatal(format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	logger.zapLogger.Fatal(message)
 }
+
+// LogDebugw DEBUG 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func LogDebugw(msg string, keysAndValues ...interface{}) {
+	logger.sugar.Debugw(msg, keysAndValues...)
+}
+
+// LogInfow INFO 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func LogInfow(msg string, keysAndValues ...interface{}) {
+	logger.sugar.Infow(msg, keysAndValues...)
+}
+
+// LogWarnw WARNING 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func LogWarnw(msg string, keysAndValues ...interface{}) {
+	logger.sugar.Warnw(msg, keysAndValues...)
+}
+
+// LogErrorw ERROR 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func LogErrorw(msg string, keysAndValues ...interface{}) {
+	logger.sugar.Errorw(msg, keysAndValues...)
+}
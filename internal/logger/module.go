@@ -0,0 +1,88 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"go.uber.org/zap"
+)
+
+// Module 하위 시스템 이름이 붙은 로거 (로그에 module 필드로 기록)
+type Module struct {
+	name string
+}
+
+// 하위 시스템별 로거
+var (
+	Server = Named("server") // 웹 서버 및 인증서
+	IPC    = Named("ipc")    // 프로세스 내부 이벤트
+	PTY    = Named("pty")    // 터미널 세션 및 쉘 프로세스
+	Auth   = Named("auth")   // 로그인, OTP, 패스키, 세션 만료
+)
+
+// Named 이름이 붙은 하위 시스템 로거 생성
+func Named(name string) *Module {
+	return &Module{name: name}
+}
+
+// sugar 하위 시스템 로거 (로거 초기화 이후 처음 사용할 때 생성)
+func (m *Module) sugar() *zap.SugaredLogger {
+	if s, ok := logger.modules.Load(m.name); ok {
+		return s.(*zap.SugaredLogger)
+	}
+	s, _ := logger.modules.LoadOrStore(m.name, logger.zapLogger.Named(m.name).Sugar())
+	return s.(*zap.SugaredLogger)
+}
+
+// Debug DEBUG 레벨 로깅 (콘솔 출력 및 디버그 레벨 설정 시 파일)
+func (m *Module) Debug(format string, args ...interface{}) {
+	m.sugar().Debugf(format, args...)
+}
+
+// Info INFO 레벨 로깅
+func (m *Module) Info(format string, args ...interface{}) {
+	m.sugar().Infof(format, args...)
+}
+
+// Warn WARNING 레벨 로깅
+func (m *Module) Warn(format string, args ...interface{}) {
+	m.sugar().Warnf(format, args...)
+}
+
+// Error ERROR 레벨 로깅
+func (m *Module) Error(format string, args ...interface{}) {
+	m.sugar().Errorf(format, args...)
+}
+
+// Debugw DEBUG 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func (m *Module) Debugw(msg string, keysAndValues ...interface{}) {
+	m.sugar().Debugw(msg, keysAndValues...)
+}
+
+// Infow INFO 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func (m *Module) Infow(msg string, keysAndValues ...interface{}) {
+	m.sugar().Infow(msg, keysAndValues...)
+}
+
+// Warnw WARNING 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func (m *Module) Warnw(msg string, keysAndValues ...interface{}) {
+	m.sugar().Warnw(msg, keysAndValues...)
+}
+
+// Errorw ERROR 레벨 구조화 로깅 (keysAndValues: 키, 값 순서로 나열)
+func (m *Module) Errorw(msg string, keysAndValues ...interface{}) {
+	m.sugar().Errorw(msg, keysAndValues...)
+}
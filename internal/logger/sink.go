@@ -0,0 +1,78 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"io"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sinkCore 로그 파일 외의 출력처(syslog, journald)로 보내는 zap 코어
+// 출력처마다 최소 레벨을 따로 지정하며, 서버 로그 레벨 변경의 영향을 받지 않음
+type sinkCore struct {
+	zapcore.LevelEnabler
+	fields []zapcore.Field
+	write  func(ent zapcore.Entry, fields []zapcore.Field) error
+	sync   func() error
+}
+
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append(make([]zapcore.Field, 0, len(c.fields)+len(fields)), c.fields...), fields...)
+	return &clone
+}
+
+func (c *sinkCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *sinkCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if len(c.fields) > 0 {
+		fields = append(append(make([]zapcore.Field, 0, len(c.fields)+len(fields)), c.fields...), fields...)
+	}
+	return c.write(ent, fields)
+}
+
+func (c *sinkCore) Sync() error {
+	if c.sync == nil {
+		return nil
+	}
+	return c.sync()
+}
+
+// addSink 초기화된 로거에 출력처를 추가 (closer: 로거 정리 시 닫을 연결)
+func addSink(core zapcore.Core, closer io.Closer) {
+	logger.zapLogger = logger.zapLogger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, core)
+	}))
+	logger.sugar = logger.zapLogger.Sugar()
+	logger.modules.Clear()
+	logger.sinks = append(logger.sinks, closer)
+}
+
+// closeSinks 추가된 출력처 연결 종료
+func closeSinks() {
+	for _, s := range logger.sinks {
+		s.Close()
+	}
+	logger.sinks = nil
+}
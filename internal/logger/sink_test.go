@@ -0,0 +1,307 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// initTestLogger 임시 디렉터리에 기록하는 로거 초기화 (테스트 종료 시 정리)
+func initTestLogger(t *testing.T, format string) {
+	t.Helper()
+
+	InitializeLogger(filepath.Join(t.TempDir(), "test.log"), 1, 1, 1, false, false, format, "")
+	t.Cleanup(FinalizeLogger)
+}
+
+// readPacket 데이터그램 하나를 읽어 문자열로 반환
+func readPacket(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+
+	buf := make([]byte, 64*1024)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("read packet: %v", err)
+	}
+	return string(buf[:n])
+}
+
+// expectNoPacket 짧은 시간 동안 데이터그램이 오지 않는지 확인
+func expectNoPacket(t *testing.T, conn net.PacketConn) {
+	t.Helper()
+
+	buf := make([]byte, 64*1024)
+	conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	if n, _, err := conn.ReadFrom(buf); err == nil {
+		t.Fatalf("unexpected packet: %q", buf[:n])
+	}
+}
+
+func TestSyslogUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	initTestLogger(t, FormatConsole)
+	err = AddSyslogSink(SyslogOptions{Network: "udp", Address: conn.LocalAddr().String(), Facility: "local3", Tag: "rootweb"})
+	if err != nil {
+		t.Fatalf("AddSyslogSink: %v", err)
+	}
+
+	// local3(19) * 8 + informational(6) = 158, local3 * 8 + warning(4) = 156
+	Server.Infow("listening", "port", 8443)
+	if got, want := readPacket(t, conn), `^<158>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}\S+ \S+ rootweb \d+ server - listening \{"port": 8443\}$`; !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("info message = %q, want match %s", got, want)
+	}
+	LogWarn("disk %s", "low")
+	if got, want := readPacket(t, conn), `^<156>1 \S+ \S+ rootweb \d+ - - disk low$`; !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("warn message = %q, want match %s", got, want)
+	}
+
+	// 기본 레벨(info) 미만은 보내지 않음
+	Auth.Debug("hidden")
+	expectNoPacket(t, conn)
+
+	// 로그 파일 레벨을 바꿔도 syslog 레벨은 그대로 유지
+	SetLevel("error")
+	defer SetLevel("info")
+	PTY.Info("still sent")
+	if got := readPacket(t, conn); !strings.HasSuffix(got, " pty - still sent") {
+		t.Errorf("message after SetLevel = %q", got)
+	}
+}
+
+func TestSyslogTCPFraming(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer ln.Close()
+
+	initTestLogger(t, FormatJSON)
+	err = AddSyslogSink(SyslogOptions{Network: "tcp", Address: ln.Addr().String(), Tag: "rootweb", Level: "debug", Format: FormatJSON})
+	if err != nil {
+		t.Fatalf("AddSyslogSink: %v", err)
+	}
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("accept: %v", err)
+	}
+	defer conn.Close()
+
+	Auth.Debugw("login", "user", "alice")
+	Auth.Errorw("multi\nline")
+
+	// RFC 6587 octet counting: "길이 SP 메시지"
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var got []string
+	for range 2 {
+		size, err := r.ReadString(' ')
+		if err != nil {
+			t.Fatalf("read length: %v", err)
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(size, " "))
+		if err != nil {
+			t.Fatalf("invalid length %q", size)
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(r, msg); err != nil {
+			t.Fatalf("read message: %v", err)
+		}
+		got = append(got, string(msg))
+	}
+
+	// daemon(3) * 8 + debug(7) = 31, daemon * 8 + error(3) = 27
+	if want := `^<31>1 \S+ \S+ rootweb \d+ auth - \{"msg":"login","user":"alice"\}$`; !regexp.MustCompile(want).MatchString(got[0]) {
+		t.Errorf("debug message = %q, want match %s", got[0], want)
+	}
+	if want := `^<27>1 \S+ \S+ rootweb \d+ auth - \{"msg":"multi\\nline"\}$`; !regexp.MustCompile(want).MatchString(got[1]) {
+		t.Errorf("error message = %q, want match %s", got[1], want)
+	}
+}
+
+func TestSyslogUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	initTestLogger(t, FormatConsole)
+	err = AddSyslogSink(SyslogOptions{Network: "unix", Address: path, Facility: "authpriv", Tag: "root web"})
+	if err != nil {
+		t.Fatalf("AddSyslogSink: %v", err)
+	}
+
+	// authpriv(10) * 8 + informational(6) = 86, 헤더 필드의 공백은 제거
+	IPC.Info("shutdown")
+	if got, want := readPacket(t, conn), `^<86>1 \S+ \S+ rootweb \d+ ipc - shutdown$`; !regexp.MustCompile(want).MatchString(got) {
+		t.Errorf("message = %q, want match %s", got, want)
+	}
+}
+
+func TestSyslogOptionErrors(t *testing.T) {
+	initTestLogger(t, FormatConsole)
+
+	for _, opts := range []SyslogOptions{
+		{Network: "udp"},
+		{Network: "sctp", Address: "127.0.0.1:514"},
+		{Network: "udp", Address: "127.0.0.1:514", Facility: "local9"},
+		{Network: "udp", Address: "127.0.0.1:514", Level: "trace"},
+	} {
+		if err := AddSyslogSink(opts); err == nil {
+			t.Errorf("AddSyslogSink(%+v) succeeded, want error", opts)
+		}
+	}
+}
+
+func TestSyslogDropsWhenUnreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	// 연결할 수 없는 서버로 보낸 메시지는 쓰기를 지연시키지 않고 버려짐
+	before := SyslogDropped()
+	w := newSyslogWriter("tcp", addr, nil, false)
+	start := time.Now()
+	for range 10 {
+		w.write("message")
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("write took %v, want no blocking", elapsed)
+	}
+	w.Close()
+	if got := SyslogDropped() - before; got != 10 {
+		t.Errorf("dropped = %d, want 10", got)
+	}
+	// 첫 연결 실패 후에는 대기 시간 동안 다시 연결하지 않음
+	if w.retryDelay != syslogRetryMin || !w.retryAt.After(time.Now()) {
+		t.Errorf("retryDelay = %v, retryAt = %v, want backoff after failed dial", w.retryDelay, w.retryAt)
+	}
+	w.backoff()
+	if w.retryDelay != 2*syslogRetryMin {
+		t.Errorf("retryDelay after second failure = %v, want %v", w.retryDelay, 2*syslogRetryMin)
+	}
+}
+
+func TestSyslogQueueFull(t *testing.T) {
+	// 전송 고루틴 없이 큐만 있는 연결: 큐가 가득 차면 버림
+	w := &syslogWriter{queue: make(chan string, 1), done: make(chan struct{})}
+	before := SyslogDropped()
+	w.write("first")
+	w.write("second")
+	if got := SyslogDropped() - before; got != 1 {
+		t.Errorf("dropped = %d, want 1", got)
+	}
+	if got := <-w.queue; got != "first" {
+		t.Errorf("queued = %q, want first", got)
+	}
+}
+
+// parseJournalEntry journald 네이티브 프로토콜 항목 해석
+func parseJournalEntry(t *testing.T, b []byte) map[string]string {
+	t.Helper()
+
+	fields := make(map[string]string)
+	for len(b) > 0 {
+		nl := bytes.IndexByte(b, '\n')
+		if nl < 0 {
+			t.Fatalf("unterminated field: %q", b)
+		}
+		line := b[:nl]
+		if eq := bytes.IndexByte(line, '='); eq >= 0 {
+			fields[string(line[:eq])] = string(line[eq+1:])
+			b = b[nl+1:]
+			continue
+		}
+
+		// 이름\n + 64비트 리틀 엔디언 길이 + 값 + \n
+		b = b[nl+1:]
+		if len(b) < 8 {
+			t.Fatalf("truncated binary field %q", line)
+		}
+		n := binary.LittleEndian.Uint64(b)
+		b = b[8:]
+		if uint64(len(b)) < n+1 || b[n] != '\n' {
+			t.Fatalf("invalid binary field %q", line)
+		}
+		fields[string(line)] = string(b[:n])
+		b = b[n+1:]
+	}
+	return fields
+}
+
+func TestJournald(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	prev := journalSocket
+	journalSocket = path
+	defer func() { journalSocket = prev }()
+
+	initTestLogger(t, FormatConsole)
+	if err := AddJournaldSink(JournaldOptions{Tag: "rootweb", Level: "warn"}); err != nil {
+		t.Fatalf("AddJournaldSink: %v", err)
+	}
+
+	PTY.Info("hidden")
+	PTY.Errorw("shell exited", "exitCode", 1, "output", "line1\nline2", "_pid", 42)
+	fields := parseJournalEntry(t, []byte(readPacket(t, conn)))
+
+	want := map[string]string{
+		"PRIORITY":          "3",
+		"SYSLOG_IDENTIFIER": "rootweb",
+		"MODULE":            "pty",
+		"EXITCODE":          "1",
+		"OUTPUT":            "line1\nline2",
+		"F__PID":            "42",
+	}
+	for k, v := range want {
+		if fields[k] != v {
+			t.Errorf("%s = %q, want %q", k, fields[k], v)
+		}
+	}
+	if !strings.HasPrefix(fields["MESSAGE"], "shell exited ") {
+		t.Errorf("MESSAGE = %q", fields["MESSAGE"])
+	}
+	if !strings.HasSuffix(fields["CODE_FILE"], "_test.go") || fields["CODE_LINE"] == "" {
+		t.Errorf("caller = %s:%s, want this test file", fields["CODE_FILE"], fields["CODE_LINE"])
+	}
+	expectNoPacket(t, conn)
+}
@@ -0,0 +1,315 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package logger
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// syslog 연결 및 전송 제한 시간 (syslog 서버 장애로 전송 고루틴이 멈추지 않도록 함)
+const syslogTimeout = 3 * time.Second
+
+// 전송 대기 중인 메시지 최대 개수 (초과하면 버림)
+const syslogQueueSize = 1024
+
+// syslog 서버 재연결 대기 시간 범위
+const (
+	syslogRetryMin = time.Second
+	syslogRetryMax = time.Minute
+)
+
+// 버린 syslog 메시지 수 (모든 syslog 출력처 합계)
+var syslogDropped atomic.Uint64
+
+// 유닉스 소켓 주소를 지정하지 않았을 때 사용하는 로컬 syslog 소켓
+const defaultSyslogSocket = "/dev/log"
+
+// syslog facility 코드 (RFC 5424)
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// SyslogOptions syslog 출력처 설정
+type SyslogOptions struct {
+	Network  string // unix, udp, tcp
+	Address  string // unix: 소켓 경로 (비어 있으면 /dev/log), udp/tcp: 호스트:포트
+	Facility string // kern, user, daemon, auth, authpriv, local0~local7 등 (비어 있으면 daemon)
+	Tag      string // APP-NAME 필드에 기록할 프로그램 이름
+	Level    string // 보낼 최소 로그 레벨 (비어 있으면 info)
+	Format   string // 메시지 본문 형식 (FormatConsole, FormatJSON)
+}
+
+// AddSyslogSink RFC 5424 형식으로 syslog 서버에 로그를 보내는 출력처 추가
+// 처음 연결에 실패하면 오류를 반환하고, 이후 연결이 끊어지면 대기 시간을 늘려 가며 다시 연결함
+func AddSyslogSink(opts SyslogOptions) error {
+	lvl, err := parseLevel(opts.Level)
+	if err != nil {
+		return err
+	}
+	if opts.Facility == "" {
+		opts.Facility = "daemon"
+	}
+	facility, ok := syslogFacilities[opts.Facility]
+	if !ok {
+		return fmt.Errorf("unknown syslog facility: %s", opts.Facility)
+	}
+	switch opts.Network {
+	case "unix":
+		if opts.Address == "" {
+			opts.Address = defaultSyslogSocket
+		}
+	case "udp", "tcp":
+		if opts.Address == "" {
+			return fmt.Errorf("syslog address is required for %s", opts.Network)
+		}
+	default:
+		return fmt.Errorf("unknown syslog network: %s", opts.Network)
+	}
+
+	conn, stream, err := dialSyslog(opts.Network, opts.Address)
+	if err != nil {
+		return err
+	}
+	w := newSyslogWriter(opts.Network, opts.Address, conn, stream)
+
+	hostname, _ := os.Hostname()
+	header := " " + syslogHeaderField(hostname, 255) + " " + syslogHeaderField(opts.Tag, 48) +
+		" " + strconv.Itoa(os.Getpid()) + " "
+	enc := messageEncoder(opts.Format)
+
+	addSink(&sinkCore{
+		LevelEnabler: lvl,
+		write: func(ent zapcore.Entry, fields []zapcore.Field) error {
+			buf, err := enc.EncodeEntry(ent, fields)
+			if err != nil {
+				return err
+			}
+			defer buf.Free()
+
+			// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+			msg := "<" + strconv.Itoa(facility*8+syslogSeverity(ent.Level)) + ">1 " +
+				ent.Time.Format("2006-01-02T15:04:05.000000Z07:00") + header +
+				syslogHeaderField(ent.LoggerName, 32) + " - " + strings.TrimSuffix(buf.String(), "\n")
+			return w.write(msg)
+		},
+	}, w)
+	return nil
+}
+
+// messageEncoder syslog 메시지 본문 및 journald MESSAGE 필드 인코더
+// 시각, 레벨, 하위 시스템 이름은 각 형식의 별도 필드로 보내므로 메시지와 구조화 필드만 인코딩
+func messageEncoder(format string) zapcore.Encoder {
+	cfg := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeDuration: zapcore.MillisDurationEncoder,
+	}
+	if format == FormatJSON {
+		return zapcore.NewJSONEncoder(cfg)
+	}
+	cfg.ConsoleSeparator = " "
+	return zapcore.NewConsoleEncoder(cfg)
+}
+
+// syslogSeverity zap 로그 레벨에 해당하는 syslog severity
+func syslogSeverity(l zapcore.Level) int {
+	switch l {
+	case zapcore.DebugLevel:
+		return 7 // debug
+	case zapcore.InfoLevel:
+		return 6 // informational
+	case zapcore.WarnLevel:
+		return 4 // warning
+	case zapcore.ErrorLevel:
+		return 3 // error
+	default:
+		return 2 // critical (DPanic, Panic, Fatal)
+	}
+}
+
+// syslogHeaderField RFC 5424 헤더 필드 값 (공백 및 제어 문자 제외한 ASCII, 비어 있으면 "-")
+func syslogHeaderField(s string, maxLen int) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < maxLen; i++ {
+		if s[i] > ' ' && s[i] < 0x7f {
+			b = append(b, s[i])
+		}
+	}
+	if len(b) == 0 {
+		return "-"
+	}
+	return string(b)
+}
+
+// syslogWriter syslog 서버 연결
+// 로그는 큐에 넣고 백그라운드 고루틴이 순서대로 전송하므로 syslog 서버 장애가 로깅 호출을 지연시키지 않음
+// 큐가 가득 찼거나 서버에 연결할 수 없으면 메시지를 버리고 개수를 기록함
+type syslogWriter struct {
+	network   string
+	address   string
+	queue     chan string
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+
+	// 아래 필드는 전송 고루틴만 사용
+	conn       net.Conn
+	stream     bool          // 스트림 연결 여부 (메시지 구분자 필요)
+	retryDelay time.Duration // 다음 재연결까지 대기 시간 (연결에 실패할 때마다 두 배로 늘어남)
+	retryAt    time.Time     // 이 시각 전에는 재연결하지 않고 메시지를 버림
+}
+
+// newSyslogWriter 전송 고루틴을 시작한 syslog 연결 생성 (conn: 미리 연결된 연결, 없으면 nil)
+func newSyslogWriter(network, address string, conn net.Conn, stream bool) *syslogWriter {
+	w := &syslogWriter{
+		network: network,
+		address: address,
+		queue:   make(chan string, syslogQueueSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		conn:    conn,
+		stream:  stream,
+	}
+	go w.run()
+	return w
+}
+
+// dial syslog 서버 연결 (유닉스 소켓은 데이터그램을 먼저 시도하고 안 되면 스트림으로 연결)
+func dialSyslog(network, address string) (conn net.Conn, stream bool, err error) {
+	networks := []string{network}
+	if network == "unix" {
+		networks = []string{"unixgram", "unix"}
+	}
+
+	for _, n := range networks {
+		if conn, err = net.DialTimeout(n, address, syslogTimeout); err == nil {
+			return conn, n == "tcp" || n == "unix", nil
+		}
+	}
+	return nil, false, err
+}
+
+// write 메시지를 전송 큐에 넣음 (큐가 가득 찼거나 연결이 종료되었으면 버림)
+func (w *syslogWriter) write(msg string) error {
+	select {
+	case <-w.done:
+		syslogDropped.Add(1)
+		return nil
+	default:
+	}
+
+	select {
+	case w.queue <- msg:
+	default:
+		syslogDropped.Add(1)
+	}
+	return nil
+}
+
+// run 큐의 메시지를 순서대로 전송 (종료 시 큐에 남은 메시지까지 전송 후 연결 종료)
+func (w *syslogWriter) run() {
+	defer close(w.stopped)
+
+	for {
+		select {
+		case msg := <-w.queue:
+			w.deliver(msg)
+		case <-w.done:
+			for {
+				select {
+				case msg := <-w.queue:
+					w.deliver(msg)
+				default:
+					if w.conn != nil {
+						w.conn.Close()
+						w.conn = nil
+					}
+					return
+				}
+			}
+		}
+	}
+}
+
+// deliver 메시지 전송 (전송에 실패하면 한 번 다시 연결하여 재전송하고, 그래도 실패하면 버림)
+// 연결에 실패하면 대기 시간이 지날 때까지 연결을 시도하지 않음
+func (w *syslogWriter) deliver(msg string) {
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			if time.Now().Before(w.retryAt) {
+				break
+			}
+			conn, stream, err := dialSyslog(w.network, w.address)
+			if err != nil {
+				w.backoff()
+				break
+			}
+			w.conn, w.stream, w.retryDelay = conn, stream, 0
+		}
+		if err := w.send(msg); err == nil {
+			return
+		}
+		w.conn.Close()
+		w.conn = nil
+	}
+	syslogDropped.Add(1)
+}
+
+// backoff 다음 재연결 시각 설정 (syslogRetryMin부터 두 배씩 늘려 syslogRetryMax까지)
+func (w *syslogWriter) backoff() {
+	w.retryDelay = min(max(w.retryDelay*2, syslogRetryMin), syslogRetryMax)
+	w.retryAt = time.Now().Add(w.retryDelay)
+}
+
+// send 연결 종류에 맞게 메시지 구분 후 전송
+// TCP는 RFC 6587 octet counting, 유닉스 스트림 소켓은 줄바꿈으로 구분하고 데이터그램은 그대로 전송
+func (w *syslogWriter) send(msg string) error {
+	switch {
+	case w.network == "tcp":
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	case w.stream:
+		msg += "\n"
+	}
+	w.conn.SetWriteDeadline(time.Now().Add(syslogTimeout))
+	_, err := w.conn.Write([]byte(msg))
+	return err
+}
+
+// Close 큐에 남은 메시지를 전송한 뒤 syslog 서버 연결 종료
+func (w *syslogWriter) Close() error {
+	w.closeOnce.Do(func() { close(w.done) })
+	<-w.stopped
+	return nil
+}
+
+// SyslogDropped 큐가 가득 찼거나 syslog 서버에 전송하지 못해 버린 메시지 수
+func SyslogDropped() uint64 {
+	return syslogDropped.Load()
+}
@@ -0,0 +1,260 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package metrics
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/terminal"
+	"github.com/hoon-x/rootweb/pkg/task"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "rootweb"
+
+// 라우트가 없는 요청(404)의 route 라벨 (요청 경로를 그대로 쓰면 라벨 값이 무한히 늘어남)
+const unmatchedRoute = "unmatched"
+
+// 기본 레지스트리에 등록되는 다른 라이브러리의 메트릭이 섞이지 않도록 별도 레지스트리 사용
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "http", Name: "requests_total",
+		Help: "HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace, Subsystem: "http", Name: "request_duration_seconds",
+		Help:    "HTTP request latency by method and route (web socket connections excluded).",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+	loginSuccess = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "auth", Name: "login_success_total",
+		Help: "Successful logins by method.",
+	}, []string{"method"})
+	loginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "auth", Name: "login_failures_total",
+		Help: "Failed logins by reason.",
+	}, []string{"reason"})
+	terminalBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "terminal", Name: "relayed_bytes_total",
+		Help: "Bytes relayed between browsers and terminals (input: browser to PTY, output: PTY to browser).",
+	}, []string{"direction"})
+	wsWriteErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "websocket", Name: "write_errors_total",
+		Help: "Terminal web socket writes that failed and closed the connection.",
+	})
+	loginSessions = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace, Subsystem: "auth", Name: "login_sessions_active",
+		Help: "Login sessions that have not expired.",
+	}, countLoginSessions)
+	terminalSessions = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace, Subsystem: "terminal", Name: "sessions_active",
+		Help: "Running terminal sessions (PTYs), attached or detached.",
+	}, func() float64 { return float64(len(terminal.List())) })
+	syslogDropped = prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace, Subsystem: "log", Name: "syslog_dropped_total",
+		Help: "Log messages dropped because the syslog queue was full or the server was unreachable.",
+	}, func() float64 { return float64(logger.SyslogDropped()) })
+
+	// 입력 및 출력 방향별 카운터 (요청마다 라벨을 조회하지 않도록 미리 생성)
+	terminalInput  = terminalBytes.WithLabelValues("input")
+	terminalOutput = terminalBytes.WithLabelValues("output")
+)
+
+func init() {
+	registry.MustRegister(
+		httpRequests, httpDuration, loginSuccess, loginFailures,
+		terminalBytes, wsWriteErrors, loginSessions, terminalSessions, syslogDropped,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// ObserveHTTP 요청 수 및 처리 시간 기록 (route: 라우트 경로 패턴, 없으면 빈 문자열)
+func ObserveHTTP(method, route string, status int, latency time.Duration) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(latency.Seconds())
+}
+
+// CountHTTP 처리 시간 없이 요청 수만 기록 (연결 유지 시간이 처리 시간으로 집계되지 않도록 웹소켓 연결에 사용)
+func CountHTTP(method, route string, status int) {
+	if route == "" {
+		route = unmatchedRoute
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+}
+
+// LoginSucceeded 로그인 성공 기록 (method: otp, recovery_code, otp_enroll, passkey)
+func LoginSucceeded(method string) {
+	loginSuccess.WithLabelValues(method).Inc()
+}
+
+// LoginFailed 로그인 실패 기록 (reason: 감사 로그의 실패 사유)
+func LoginFailed(reason string) {
+	loginFailures.WithLabelValues(reason).Inc()
+}
+
+// TerminalInput 브라우저에서 터미널로 전달한 입력 크기 기록
+func TerminalInput(n int) {
+	terminalInput.Add(float64(n))
+}
+
+// TerminalOutput 터미널에서 브라우저로 전송한 출력 크기 기록
+func TerminalOutput(n int) {
+	terminalOutput.Add(float64(n))
+}
+
+// WebSocketWriteFailed 웹소켓 쓰기 실패 기록
+func WebSocketWriteFailed() {
+	wsWriteErrors.Inc()
+}
+
+// countLoginSessions 만료되지 않은 로그인 세션 수 (DB 초기화 전이면 0)
+func countLoginSessions() float64 {
+	if db.SqliteDB == nil {
+		return 0
+	}
+	var n int64
+	err := db.SqliteDB.Model(&db.WebSession{}).
+		Where("user_id > 0 AND expires_at > ?", time.Now()).Count(&n).Error
+	if err != nil {
+		logger.Server.Warnw("Failed to count login sessions for metrics", "err", err)
+	}
+	return float64(n)
+}
+
+// taskCollector 작업 관리자에 등록된 작업의 실행 상태 수집기
+type taskCollector struct {
+	tm *task.TaskManager
+}
+
+var (
+	taskRunningDesc = prometheus.NewDesc(namespace+"_task_running",
+		"Whether a background task is running (1) or has stopped (0).", []string{"task"}, nil)
+	taskPanicsDesc = prometheus.NewDesc(namespace+"_task_panics_total",
+		"Background task exits caused by a panic.", []string{"task"}, nil)
+)
+
+func (c taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- taskRunningDesc
+	ch <- taskPanicsDesc
+}
+
+func (c taskCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, s := range c.tm.States() {
+		running := 0.0
+		if s.Running {
+			running = 1
+		}
+		ch <- prometheus.MustNewConstMetric(taskRunningDesc, prometheus.GaugeValue, running, s.Name)
+		ch <- prometheus.MustNewConstMetric(taskPanicsDesc, prometheus.CounterValue, float64(s.Panics), s.Name)
+	}
+}
+
+// WatchTasks 작업 관리자의 작업 상태를 메트릭으로 노출
+func WatchTasks(tm *task.TaskManager) {
+	registry.MustRegister(taskCollector{tm: tm})
+}
+
+// 메트릭 조회 접근 제어 설정 (Configure로 지정)
+var access struct {
+	token string         // 비어 있지 않으면 Authorization: Bearer 헤더 필수
+	nets  []netip.Prefix // 비어 있지 않으면 접속 IP가 포함되어야 함
+}
+
+// Configure 메트릭 조회 접근 제어 설정
+// 토큰과 허용 IP 목록을 모두 지정하면 둘 다 만족해야 하며, 둘 다 비어 있으면 로컬 접속만 허용
+func Configure(token string, allowIPs []string) error {
+	nets := make([]netip.Prefix, 0, len(allowIPs))
+	for _, s := range allowIPs {
+		if p, err := netip.ParsePrefix(s); err == nil {
+			nets = append(nets, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return fmt.Errorf("invalid metrics allow IP: %s", s)
+		}
+		nets = append(nets, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	access.token, access.nets = token, nets
+	return nil
+}
+
+// Authorize 메트릭 조회 허용 여부 (ip: 접속 IP, authorization: Authorization 헤더 값)
+func Authorize(ip, authorization string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	if access.token != "" {
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(access.token)) != 1 {
+			return false
+		}
+	}
+	if len(access.nets) > 0 {
+		for _, p := range access.nets {
+			if p.Contains(addr) {
+				return true
+			}
+		}
+		return false
+	}
+	return access.token != "" || addr.IsLoopback()
+}
+
+// Handler 메트릭 조회 핸들러 (접근 제어는 호출 측에서 Authorize로 확인)
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// StandaloneHandler 별도 포트에서 사용할 /metrics 핸들러 (소켓 주소로 접속 IP를 판별하여 접근 제어)
+func StandaloneHandler() http.Handler {
+	h := Handler()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metrics", func(w http.ResponseWriter, r *http.Request) {
+		ip, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			ip = r.RemoteAddr
+		}
+		if !Authorize(ip, r.Header.Get("Authorization")) {
+			logger.Server.Warnw("Rejected metrics request", "ip", ip)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+	return mux
+}
@@ -0,0 +1,166 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+)
+
+// 감사 로그 조회 시 한 번에 반환하는 최대 이벤트 개수
+const maxAuditEvents = 500
+
+// recordAudit 로그인 사용자가 수행한 작업을 감사 로그에 기록
+func recordAudit(c *gin.Context, typ, target string, details map[string]interface{}) {
+	e := audit.Event{Type: typ, ClientIP: c.ClientIP(), Target: target, Details: details}
+	if user := middleware.CurrentUser(c); user != nil {
+		e.UserID, e.Username = user.ID, user.Username
+	}
+	audit.Record(e)
+}
+
+// auditEventView 감사 로그 조회 응답 항목
+type auditEventView struct {
+	ID       uint            `json:"id"`
+	Time     time.Time       `json:"time"`
+	Type     string          `json:"type"`
+	UserID   uint            `json:"userId,omitempty"`
+	Username string          `json:"username"`
+	ClientIP string          `json:"clientIp"`
+	Target   string          `json:"target"`
+	Details  json.RawMessage `json:"details"`
+	PrevHash string          `json:"prevHash"`
+	Hash     string          `json:"hash"`
+}
+
+// HtmlAudit [GET /audit] 감사 로그 조회 페이지 렌더링
+func HtmlAudit(c *gin.Context) {
+	c.HTML(http.StatusOK, "audit.html", gin.H{
+		"User":  middleware.CurrentUser(c),
+		"Types": audit.Types,
+	})
+}
+
+// ListAuditEvents [GET /api/audit/events] 감사 로그 조회 (최신순)
+// 조건: user(사용자 이름), ip, type(여러 개 지정 가능, "login.*"처럼 접두어 지정 가능),
+// from/to(RFC 3339 시각), before(이 ID보다 이전 이벤트, 다음 페이지 조회용), limit
+func ListAuditEvents(c *gin.Context) {
+	q := db.SqliteDB.Model(&db.AuditEvent{})
+	if user := c.Query("user"); user != "" {
+		q = q.Where("username = ?", user)
+	}
+	if ip := c.Query("ip"); ip != "" {
+		q = q.Where("client_ip = ?", ip)
+	}
+	if types := c.QueryArray("type"); len(types) > 0 {
+		cond := db.SqliteDB
+		for i, t := range types {
+			expr, arg := "type = ?", t
+			if prefix, ok := strings.CutSuffix(t, "*"); ok {
+				expr, arg = "type LIKE ? ESCAPE '\\'", escapeLike(prefix)+"%"
+			}
+			if i == 0 {
+				cond = cond.Where(expr, arg)
+			} else {
+				cond = cond.Or(expr, arg)
+			}
+		}
+		q = q.Where(cond)
+	}
+	for _, bound := range []struct{ param, expr string }{{"from", "time >= ?"}, {"to", "time < ?"}} {
+		v := c.Query(bound.param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 시각 형식입니다: " + bound.param})
+			return
+		}
+		q = q.Where(bound.expr, t.UTC())
+	}
+	if v := c.Query("before"); v != "" {
+		before, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+			return
+		}
+		q = q.Where("id < ?", before)
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	if limit <= 0 || limit > maxAuditEvents {
+		limit = maxAuditEvents
+	}
+
+	// 다음 페이지 존재 여부 확인을 위해 하나 더 조회
+	var events []db.AuditEvent
+	if err := q.Order("id DESC").Limit(limit + 1).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "감사 로그 조회 실패"})
+		logger.Server.Errorw("Failed to query audit events", "ip", c.ClientIP(), "err", err)
+		return
+	}
+	more := len(events) > limit
+	if more {
+		events = events[:limit]
+	}
+
+	views := make([]auditEventView, 0, len(events))
+	for _, e := range events {
+		views = append(views, auditEventView{
+			ID:       e.ID,
+			Time:     e.Time,
+			Type:     e.Type,
+			UserID:   e.UserID,
+			Username: e.Username,
+			ClientIP: e.ClientIP,
+			Target:   e.Target,
+			Details:  json.RawMessage(e.Details),
+			PrevHash: e.PrevHash,
+			Hash:     e.Hash,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"events": views, "more": more})
+}
+
+// VerifyAuditLog [GET /api/audit/verify] 감사 로그 해시 체인 검증
+func VerifyAuditLog(c *gin.Context) {
+	res, err := audit.Verify()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "감사 로그 검증 실패"})
+		logger.Server.Errorw("Failed to verify audit log", "ip", c.ClientIP(), "err", err)
+		return
+	}
+	if !res.OK {
+		logger.Server.Warnw("Audit log chain is broken",
+			"id", res.BrokenID, "problem", res.Problem, "by", middleware.CurrentUser(c).Username, "ip", c.ClientIP())
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// escapeLike LIKE 패턴의 특수 문자 이스케이프
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,519 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/filemgr"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+)
+
+// fileTransferIdleTimeout 파일 전송 중 데이터가 오가지 않을 때 연결을 끊기까지의 시간
+// 서버 기본 읽기/쓰기 제한 시간보다 오래 걸리는 전송을 허용하기 위해 입출력마다 연장
+const fileTransferIdleTimeout = time.Minute
+
+// 권한 변경 요청의 8진수 권한 형식
+var fileModePattern = regexp.MustCompile(`^[0-7]{3,4}$`)
+
+// fileReq 파일 작업 요청 형식
+type fileReq struct {
+	Path      string `json:"path"`
+	To        string `json:"to"`
+	Mode      string `json:"mode"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+// uploadChunkSize 업로드 요청 1회당 최대 크기 (미설정 시 8MB)
+func uploadChunkSize() int64 {
+	if mb := config.Conf.Files.ChunkSize; mb > 0 {
+		return int64(mb) << 20
+	}
+	return 8 << 20
+}
+
+// openUserFS 로그인 사용자에 매핑된 리눅스 계정의 파일 시스템 열기 (터미널과 동일한 계정 정책 적용)
+func openUserFS(c *gin.Context) (*filemgr.FS, bool) {
+	user := middleware.CurrentUser(c)
+	fsys, err := filemgr.Open(user)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "파일 관리자를 사용할 수 없는 계정입니다. (" + err.Error() + ")"})
+		logger.Server.Warnw("Refused to open file manager",
+			"user", user.Username, "unixUser", user.UnixUser, "ip", c.ClientIP(), "err", err)
+		return nil, false
+	}
+	return fsys, true
+}
+
+// cleanFilePath 요청 경로를 절대 경로로 정리 (mutate가 true이면 최상위 경로 자체에 대한 변경 거부)
+func cleanFilePath(c *gin.Context, path string, mutate bool) (string, bool) {
+	cleaned, err := filemgr.CleanPath(path)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "경로는 /로 시작하는 절대 경로여야 합니다."})
+		return "", false
+	}
+	if mutate && cleaned == "/" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "최상위 경로는 변경할 수 없습니다."})
+		return "", false
+	}
+	return cleaned, true
+}
+
+// bindFileReq JSON 요청 본문 해석
+func bindFileReq(c *gin.Context) (*fileReq, bool) {
+	var req fileReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return nil, false
+	}
+	return &req, true
+}
+
+// logFileOp 파일 작업 기록 (keysAndValues: 사용자 정보 뒤에 붙일 키, 값 목록)
+func logFileOp(c *gin.Context, fsys *filemgr.FS, msg string, keysAndValues ...any) {
+	user := middleware.CurrentUser(c)
+	kv := append([]any{"user", user.Username, "unixUser", fsys.Account.Name, "ip", c.ClientIP()}, keysAndValues...)
+	logger.Server.Infow(msg, kv...)
+}
+
+// auditFileOp 파일 조회, 변경 및 전송 작업을 감사 로그에 기록 (대상: 경로, 작업을 수행한 리눅스 계정 포함)
+func auditFileOp(c *gin.Context, fsys *filemgr.FS, typ, path string, details map[string]interface{}) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["unixUser"] = fsys.Account.Name
+	recordAudit(c, typ, path, details)
+}
+
+// fileOpError 파일 작업 오류를 응답 상태 코드로 변환하여 응답하고 기록
+func fileOpError(c *gin.Context, fsys *filemgr.FS, op, path string, err error) {
+	status, msg := http.StatusInternalServerError, "파일 작업에 실패했습니다."
+	switch {
+	case errors.Is(err, filemgr.ErrNotFound):
+		status, msg = http.StatusNotFound, "파일 또는 디렉터리를 찾을 수 없습니다."
+	case errors.Is(err, filemgr.ErrPermission):
+		status, msg = http.StatusForbidden, "권한이 없습니다."
+	case errors.Is(err, filemgr.ErrExist):
+		status, msg = http.StatusConflict, "같은 이름의 항목이 이미 있습니다."
+	case errors.Is(err, filemgr.ErrNotEmpty):
+		status, msg = http.StatusConflict, "디렉터리가 비어 있지 않습니다."
+	case errors.Is(err, filemgr.ErrOffset):
+		status, msg = http.StatusConflict, "업로드 위치가 일치하지 않습니다."
+	case errors.Is(err, filemgr.ErrInvalid):
+		status, msg = http.StatusBadRequest, "요청한 작업을 수행할 수 없는 항목입니다."
+	}
+	var maxErr *http.MaxBytesError
+	if errors.As(err, &maxErr) {
+		status, msg = http.StatusRequestEntityTooLarge, "업로드 요청 크기가 너무 큽니다."
+	}
+
+	c.JSON(status, gin.H{"error": msg})
+	user := middleware.CurrentUser(c)
+	logger.Server.Warnw("File operation failed",
+		"op", op, "user", user.Username, "unixUser", fsys.Account.Name, "ip", c.ClientIP(), "path", path, "err", err)
+}
+
+// HtmlFiles [GET /files] 파일 관리자 페이지 렌더링
+func HtmlFiles(c *gin.Context) {
+	c.HTML(http.StatusOK, "files.html", gin.H{
+		"User":      middleware.CurrentUser(c),
+		"ChunkSize": uploadChunkSize(),
+	})
+}
+
+// ListFiles [GET /api/files] 디렉터리 항목 조회 (path 쿼리가 없으면 홈 디렉터리)
+func ListFiles(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+
+	home := fsys.Account.Home
+	if home == "" {
+		home = "/"
+	}
+	dir, ok := cleanFilePath(c, c.DefaultQuery("path", home), false)
+	if !ok {
+		return
+	}
+
+	entries, err := fsys.List(c.Request.Context(), dir)
+	if err != nil {
+		fileOpError(c, fsys, "list", dir, err)
+		return
+	}
+
+	logFileOp(c, fsys, "Directory listed", "path", dir, "entries", len(entries))
+	auditFileOp(c, fsys, audit.TypeFileList, dir, nil)
+	c.JSON(http.StatusOK, gin.H{
+		"path":     dir,
+		"home":     home,
+		"unixUser": fsys.Account.Name,
+		"entries":  entries,
+	})
+}
+
+// DownloadFile [GET /api/files/download] 파일 다운로드 (Range 요청으로 중단된 다운로드 이어받기 지원)
+func DownloadFile(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, c.Query("path"), false)
+	if !ok {
+		return
+	}
+
+	offset, length, ranged := parseByteRange(c.GetHeader("Range"))
+	d, err := fsys.Download(c.Request.Context(), path, offset, length)
+	if err != nil {
+		fileOpError(c, fsys, "download", path, err)
+		return
+	}
+
+	// 이어받기 요청 이후 파일이 변경되었으면 전체 전송
+	etag := fmt.Sprintf(`"%x-%x"`, d.Info.Size, d.Info.ModTime.UnixNano())
+	lastModified := d.Info.ModTime.UTC().Format(http.TimeFormat)
+	if ifRange := c.GetHeader("If-Range"); ranged && ifRange != "" && ifRange != etag && ifRange != lastModified {
+		d.Close()
+		offset, length, ranged = 0, -1, false
+		if d, err = fsys.Download(c.Request.Context(), path, offset, length); err != nil {
+			fileOpError(c, fsys, "download", path, err)
+			return
+		}
+	}
+	defer func() { d.Close() }()
+
+	size := d.Info.Size
+	status := http.StatusOK
+	if ranged {
+		if offset >= size {
+			c.Header("Content-Range", fmt.Sprintf("bytes */%d", size))
+			c.Status(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		if length < 0 || offset+length > size {
+			length = size - offset
+		}
+		c.Header("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, size))
+		status = http.StatusPartialContent
+	} else {
+		length = size
+	}
+
+	c.Header("Accept-Ranges", "bytes")
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Length", strconv.FormatInt(length, 10))
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": d.Info.Name}))
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", lastModified)
+	c.Status(status)
+
+	rc := http.NewResponseController(c.Writer)
+	n, err := io.CopyN(deadlineWriter{w: c.Writer, rc: rc}, d, length)
+	// 중단된 다운로드도 전송된 만큼은 읽혀 나갔으므로 감사 로그에 기록
+	auditFileOp(c, fsys, audit.TypeFileDownload, path, map[string]interface{}{
+		"offset":   offset,
+		"sent":     n,
+		"size":     size,
+		"complete": err == nil,
+	})
+	if err != nil {
+		logFileOp(c, fsys, "File download interrupted", "path", path, "offset", offset, "sent", n, "size", size, "err", err)
+		return
+	}
+	logFileOp(c, fsys, "File downloaded", "path", path, "offset", offset, "sent", n, "size", size)
+}
+
+// parseByteRange 단일 Range 헤더(bytes=N- 또는 bytes=N-M) 해석 (그 외 형식은 전체 전송)
+func parseByteRange(header string) (offset, length int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, -1, false
+	}
+	start, end, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found || start == "" {
+		return 0, -1, false
+	}
+
+	offset, err := strconv.ParseInt(start, 10, 64)
+	if err != nil || offset < 0 {
+		return 0, -1, false
+	}
+	if end == "" {
+		return offset, -1, true
+	}
+	last, err := strconv.ParseInt(end, 10, 64)
+	if err != nil || last < offset {
+		return 0, -1, false
+	}
+	return offset, last - offset + 1, true
+}
+
+// UploadStatus [GET /api/files/upload] 업로드 대상 경로의 중단된 업로드 크기 및 기존 파일 존재 여부 조회
+func UploadStatus(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, c.Query("path"), true)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	offset, err := fsys.UploadOffset(ctx, path)
+	if err != nil {
+		fileOpError(c, fsys, "upload", path, err)
+		return
+	}
+	exists, err := fsys.Exists(ctx, path)
+	if err != nil {
+		fileOpError(c, fsys, "upload", path, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"offset": offset, "exists": exists})
+}
+
+// UploadChunk [PUT /api/files/upload] 업로드 데이터 일부를 offset 위치부터 임시 파일에 기록
+// offset이 0이면 새 업로드를 시작하고, 그 외에는 이전까지 기록된 크기와 같아야 함
+func UploadChunk(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, c.Query("path"), true)
+	if !ok {
+		return
+	}
+	offset, err := strconv.ParseInt(c.Query("offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	rc := http.NewResponseController(c.Writer)
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, uploadChunkSize())
+	size, err := fsys.WriteChunk(c.Request.Context(), path, offset, deadlineReader{r: body, rc: rc})
+	if err != nil {
+		fileOpError(c, fsys, "upload", path, err)
+		return
+	}
+
+	if offset == 0 {
+		logFileOp(c, fsys, "File upload started", "path", path)
+	}
+	logFileOp(c, fsys, "File upload chunk written", "path", path, "offset", offset, "bytes", size-offset)
+	auditFileOp(c, fsys, audit.TypeFileUploadChunk, path, map[string]interface{}{"offset": offset, "bytes": size - offset})
+	c.JSON(http.StatusOK, gin.H{"offset": size})
+}
+
+// CompleteUpload [POST /api/files/upload/complete] 전송이 끝난 임시 파일을 업로드 대상 경로로 이동
+func CompleteUpload(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	req, ok := bindFileReq(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, req.Path, true)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	size, err := fsys.UploadOffset(ctx, path)
+	if err == nil {
+		err = fsys.CompleteUpload(ctx, path, req.Overwrite)
+	}
+	if err != nil {
+		fileOpError(c, fsys, "upload", path, err)
+		return
+	}
+
+	logFileOp(c, fsys, "File uploaded", "path", path, "size", size, "overwrite", req.Overwrite)
+	auditFileOp(c, fsys, audit.TypeFileUpload, path, map[string]interface{}{"size": size, "overwrite": req.Overwrite})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// CancelUpload [DELETE /api/files/upload] 중단된 업로드의 임시 파일 삭제
+func CancelUpload(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, c.Query("path"), true)
+	if !ok {
+		return
+	}
+
+	// 취소 전까지 기록된 크기 (임시 파일이 없으면 0)
+	ctx := c.Request.Context()
+	offset, err := fsys.UploadOffset(ctx, path)
+	if err == nil {
+		err = fsys.CancelUpload(ctx, path)
+	}
+	if err != nil && !errors.Is(err, filemgr.ErrNotFound) {
+		fileOpError(c, fsys, "upload", path, err)
+		return
+	}
+
+	logFileOp(c, fsys, "File upload canceled", "path", path, "offset", offset, "bytes", offset)
+	auditFileOp(c, fsys, audit.TypeFileUploadCancel, path, map[string]interface{}{"offset": offset, "bytes": offset})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// MakeDir [POST /api/files/mkdir] 디렉터리 생성
+func MakeDir(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	req, ok := bindFileReq(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, req.Path, true)
+	if !ok {
+		return
+	}
+
+	if err := fsys.Mkdir(c.Request.Context(), path); err != nil {
+		fileOpError(c, fsys, "mkdir", path, err)
+		return
+	}
+
+	logFileOp(c, fsys, "Directory created", "path", path)
+	auditFileOp(c, fsys, audit.TypeFileMkdir, path, nil)
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// RenameFile [POST /api/files/rename] 이름 변경 및 이동 (대상 경로에 항목이 있으면 거부)
+func RenameFile(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	req, ok := bindFileReq(c)
+	if !ok {
+		return
+	}
+	from, ok := cleanFilePath(c, req.Path, true)
+	if !ok {
+		return
+	}
+	to, ok := cleanFilePath(c, req.To, true)
+	if !ok {
+		return
+	}
+
+	if err := fsys.Rename(c.Request.Context(), from, to); err != nil {
+		fileOpError(c, fsys, "rename", from, err)
+		return
+	}
+
+	logFileOp(c, fsys, "File renamed", "path", from, "to", to)
+	auditFileOp(c, fsys, audit.TypeFileRename, from, map[string]interface{}{"to": to})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// ChmodFile [POST /api/files/chmod] 권한 변경
+func ChmodFile(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	req, ok := bindFileReq(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, req.Path, true)
+	if !ok {
+		return
+	}
+	if !fileModePattern.MatchString(req.Mode) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "권한은 8진수 형식이어야 합니다. (예: 0644)"})
+		return
+	}
+
+	if err := fsys.Chmod(c.Request.Context(), path, req.Mode); err != nil {
+		fileOpError(c, fsys, "chmod", path, err)
+		return
+	}
+
+	logFileOp(c, fsys, "File mode changed", "path", path, "mode", req.Mode)
+	auditFileOp(c, fsys, audit.TypeFileChmod, path, map[string]interface{}{"mode": req.Mode})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// DeleteFile [DELETE /api/files] 파일 또는 디렉터리 삭제 (recursive 쿼리가 없으면 비어 있는 디렉터리만 삭제)
+func DeleteFile(c *gin.Context) {
+	fsys, ok := openUserFS(c)
+	if !ok {
+		return
+	}
+	path, ok := cleanFilePath(c, c.Query("path"), true)
+	if !ok {
+		return
+	}
+	recursive := c.Query("recursive") == "1"
+
+	if err := fsys.Remove(c.Request.Context(), path, recursive); err != nil {
+		fileOpError(c, fsys, "delete", path, err)
+		return
+	}
+
+	logFileOp(c, fsys, "File deleted", "path", path, "recursive", recursive)
+	auditFileOp(c, fsys, audit.TypeFileDelete, path, map[string]interface{}{"recursive": recursive})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// deadlineReader 요청 본문을 읽을 때마다 연결 읽기 제한 시간 연장
+type deadlineReader struct {
+	r  io.Reader
+	rc *http.ResponseController
+}
+
+func (d deadlineReader) Read(p []byte) (int, error) {
+	_ = d.rc.SetReadDeadline(time.Now().Add(fileTransferIdleTimeout))
+	return d.r.Read(p)
+}
+
+// deadlineWriter 응답을 쓸 때마다 연결 쓰기 제한 시간 연장
+type deadlineWriter struct {
+	w  io.Writer
+	rc *http.ResponseController
+}
+
+func (d deadlineWriter) Write(p []byte) (int, error) {
+	_ = d.rc.SetWriteDeadline(time.Now().Add(fileTransferIdleTimeout))
+	return d.w.Write(p)
+}
@@ -18,39 +18,27 @@ package handler
 
 import (
 	"encoding/base64"
-	"encoding/json"
 	"errors"
 	"net/http"
-	"os"
-	"os/exec"
-	"sync"
+	osuser "os/user"
+	"strings"
 	"time"
 
-	"github.com/creack/pty"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/audit"
 	"github.com/hoon-x/rootweb/internal/db"
 	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/metrics"
 	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
 	"github.com/pquerna/otp/totp"
 	"github.com/skip2/go-qrcode"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-type wsMsg struct {
-	MsgType string `json:"type"`
-	Cols    int    `json:"cols"`
-	Rows    int    `json:"rows"`
-}
-
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  8192,
-	WriteBufferSize: 8192,
-	CheckOrigin:     func(r *http.Request) bool { return true },
-}
-
 // HtmlSetup [GET /setup] 최초 접속 시 관리자 계정 /setup 페이지 렌더링
 func HtmlSetup(c *gin.Context) {
 	// 관리자 계정이 존재하면 바로 /login 경로로 리다이렉트
@@ -59,35 +47,42 @@ func HtmlSetup(c *gin.Context) {
 		return
 	}
 
-	// Google OTP용 Secret 생성
+	// Google OTP용 Secret 및 QR코드 생성
+	secret, qrBase64, ok := generateOTP(c, "admin")
+	if !ok {
+		return
+	}
+
+	// 템플릿 렌더링
+	// Secret: 폼 제출 시 다시 받아야 하므로 hidden input용
+	// QRBase64: QR 이미지 (data URI)
+	c.HTML(http.StatusOK, "setup.html", gin.H{
+		"Secret":   secret,
+		"QRBase64": qrBase64,
+	})
+}
+
+// generateOTP Google OTP용 Secret과 등록용 QR코드(Base64) 생성
+func generateOTP(c *gin.Context, username string) (string, string, bool) {
 	key, err := totp.Generate(totp.GenerateOpts{
 		Issuer:      "RootWeb",
-		AccountName: "admin@rootweb",
+		AccountName: username + "@rootweb",
 	})
-
 	if err != nil {
 		c.String(http.StatusInternalServerError, "OTP 생성 중 오류가 발생했습니다.")
-		logger.LogError("Failed to create OTP: IP=%s, err=%v", c.ClientIP(), err)
-		return
+		logger.Auth.Errorw("Failed to create OTP", "ip", c.ClientIP(), "err", err)
+		return "", "", false
 	}
 
 	// QR코드 생성
-	var png []byte
-	png, err = qrcode.Encode(key.URL(), qrcode.Medium, 256)
+	png, err := qrcode.Encode(key.URL(), qrcode.Medium, 256)
 	if err != nil {
 		c.String(http.StatusInternalServerError, "QR 생성 실패")
-		logger.LogError("Failed to create QR: IP=%s, err=%v", c.ClientIP(), err)
-		return
+		logger.Auth.Errorw("Failed to create QR", "ip", c.ClientIP(), "err", err)
+		return "", "", false
 	}
-	qrBase64 := base64.StdEncoding.EncodeToString(png)
 
-	// 템플릿 렌더링
-	// Secret: 폼 제출 시 다시 받아야 하므로 hidden input용
-	// QRURL: 구글 차트 API가 QR 이미지를 생성할 수 있도록 넘겨주는 주소
-	c.HTML(http.StatusOK, "setup.html", gin.H{
-		"Secret":   key.Secret(),
-		"QRBase64": qrBase64,
-	})
+	return key.Secret(), base64.StdEncoding.EncodeToString(png), true
 }
 
 // RegisterAdmin [POST /setup] 관리자 계정 등록
@@ -104,8 +99,25 @@ func RegisterAdmin(c *gin.Context) {
 		return
 	}
 
+	// 아이디 형식 확인
+	username = strings.TrimSpace(username)
+	if !db.IsValidUsername(username) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": usernameRuleMessage})
+		return
+	}
+
+	// 연속 실패한 IP는 일정 시간 동안 시도 제한
+	if wait := checkLoginThrottle("", c.ClientIP()); wait > 0 {
+		auditLoginFailure(c, username, failThrottled)
+		setRetryAfter(c, wait)
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttleMessage(wait)})
+		return
+	}
+
 	// OTP 번호 유효성 검증
-	if !totp.Validate(token, secret) {
+	step, ok := validateTOTP(token, secret)
+	if !ok {
+		recordLoginFailure(c, username, failSetup, false)
 		c.JSON(http.StatusBadRequest, gin.H{"error": "OTP 인증 번호가 일치하지 않습니다."})
 		return
 	}
@@ -114,26 +126,39 @@ func RegisterAdmin(c *gin.Context) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "비밀번호 암호화 실패"})
-		logger.LogError("Failed to hash password: IP=%s, err=%v", c.ClientIP(), err)
+		logger.Auth.Errorw("Failed to hash password", "ip", c.ClientIP(), "err", err)
 		return
 	}
 
 	// 관리자 계정 생성
 	newAdmin := db.User{
-		Username:  username,
-		Password:  string(hashedPassword),
-		OTPSecret: secret,
-		IsAdmin:   true,
+		Username:    username,
+		Password:    string(hashedPassword),
+		OTPSecret:   secret,
+		OTPLastStep: step,
+		Role:        db.RoleAdmin,
+	}
+
+	// 최초 관리자는 데몬 실행 계정으로 쉘을 사용하도록 매핑
+	if current, err := osuser.Current(); err == nil {
+		newAdmin.UnixUser = current.Username
+		newAdmin.AllowRoot = current.Uid == "0"
 	}
 
 	// DB에 관리자 계정 등록
+	var codes []string
 	err = db.SqliteDB.Transaction(func(tx *gorm.DB) error {
 		var count int64
-		tx.Model(&db.User{}).Where("is_admin = ?", true).Count(&count)
+		tx.Model(&db.User{}).Where("role = ?", db.RoleAdmin).Count(&count)
 		if count > 0 {
 			return errors.New("already_initialized")
 		}
-		return tx.Create(&newAdmin).Error
+		if err := tx.Create(&newAdmin).Error; err != nil {
+			return err
+		}
+		// OTP 분실에 대비한 복구 코드 발급
+		codes, err = generateRecoveryCodes(tx, newAdmin.ID)
+		return err
 	})
 
 	if err != nil {
@@ -141,16 +166,28 @@ func RegisterAdmin(c *gin.Context) {
 			c.JSON(http.StatusForbidden, gin.H{"error": "관리자가 이미 존재합니다."})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "관리자 계정 저장 실패"})
-			logger.LogError("Failed to store admin account: IP=%s, err=%v", c.ClientIP(), err)
+			logger.Auth.Errorw("Failed to store admin account", "ip", c.ClientIP(), "err", err)
 		}
 		return
 	}
 
 	// 전역 캐시 업데이트 (이제부터 모든 미들웨어는 DB 조회 없이 통과)
 	middleware.AdminExists = true
+	audit.Record(audit.Event{
+		Type:     audit.TypeSetup,
+		UserID:   newAdmin.ID,
+		Username: newAdmin.Username,
+		ClientIP: c.ClientIP(),
+		Target:   newAdmin.Username,
+		Details:  map[string]interface{}{"unixUser": newAdmin.UnixUser, "allowRoot": newAdmin.AllowRoot},
+	})
 
-	// 설정 완료 후 로그인 페이지로 이동
-	c.Redirect(http.StatusFound, "/login")
+	// 설정 완료 후 복구 코드 안내 (확인 후 로그인 페이지로 이동)
+	c.HTML(http.StatusOK, "recovery_codes.html", gin.H{
+		"Codes":    codes,
+		"Next":     "/login",
+		"NextText": "로그인 페이지로 이동",
+	})
 }
 
 // HtmlLogin [GET /login] 로그인 페이지 렌더링
@@ -168,182 +205,255 @@ func HtmlLogin(c *gin.Context) {
 func Login(c *gin.Context) {
 	username := c.PostForm("username")
 	password := c.PostForm("password")
-	otpToken := c.PostForm("otp_token") // 추가: 폼에서 otp_token으로 보내기
+	otpToken := c.PostForm("otp_token")
+
+	// 연속 실패한 아이디 또는 IP는 비밀번호 확인 없이 거부
+	if wait := checkLoginThrottle(username, c.ClientIP()); wait > 0 {
+		auditLoginFailure(c, username, failThrottled)
+		setRetryAfter(c, wait)
+		c.HTML(http.StatusTooManyRequests, "login.html", gin.H{"Error": throttleMessage(wait)})
+		return
+	}
 
 	// ID 조회
-	var admin db.User
-	if err := db.SqliteDB.Where("username = ? AND is_admin = ?", username, true).First(&admin).Error; err != nil {
+	var user db.User
+	if err := db.SqliteDB.Where("username = ?", username).First(&user).Error; err != nil {
+		recordLoginFailure(c, username, failPassword, true)
 		c.HTML(http.StatusUnauthorized, "login.html", gin.H{"Error": "아이디 또는 비밀번호가 올바르지 않습니다."})
 		return
 	}
 
 	// PW 검증
-	if bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)) != nil {
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		recordLoginFailure(c, username, failPassword, true)
 		c.HTML(http.StatusUnauthorized, "login.html", gin.H{"Error": "아이디 또는 비밀번호가 올바르지 않습니다."})
 		return
 	}
 
-	// OTP 검증
-	if !totp.Validate(otpToken, admin.OTPSecret) {
+	// 비활성화 계정 확인
+	if user.Disabled {
+		auditLoginFailure(c, username, failDisabled)
+		c.HTML(http.StatusForbidden, "login.html", gin.H{"Error": "비활성화된 계정입니다. 관리자에게 문의하세요."})
+		return
+	}
+
+	// OTP 번호를 비워두었고 패스키가 등록된 사용자는 패스키 인증 페이지로 이동
+	if otpToken == "" && countPasskeys(user.ID) > 0 {
+		if err := startMFA(c, &user); err != nil {
+			c.HTML(http.StatusInternalServerError, "login.html", gin.H{"Error": "세션 저장 실패"})
+			logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+			return
+		}
+		c.Redirect(http.StatusFound, "/login/passkey")
+		return
+	}
+
+	// OTP 미등록 사용자는 OTP 등록 페이지로 이동
+	if user.OTPSecret == "" {
+		sess := sessions.Default(c)
+		sess.Clear()
+		sess.Set("enroll_user_id", user.ID)
+		sess.Set("enroll_started_at", time.Now().Unix())
+		err := middleware.RotateCSRFToken(c, sess)
+		if err == nil {
+			err = sess.Save()
+		}
+		if err != nil {
+			c.HTML(http.StatusInternalServerError, "login.html", gin.H{"Error": "세션 저장 실패"})
+			logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+			return
+		}
+		c.Redirect(http.StatusFound, "/enroll")
+		return
+	}
+
+	// OTP 번호 또는 복구 코드 검증
+	ok, recovered := verifySecondFactor(&user, otpToken)
+	if !ok {
+		recordLoginFailure(c, username, failOTP, true)
 		c.HTML(http.StatusUnauthorized, "login.html", gin.H{"Error": "OTP 인증 번호가 일치하지 않습니다."})
 		return
 	}
 
 	// 세션 저장
+	method := "otp"
+	if recovered {
+		method = "recovery_code"
+	}
+	if err := startUserSession(c, &user, method); err != nil {
+		c.HTML(http.StatusInternalServerError, "login.html", gin.H{"Error": "세션 저장 실패"})
+		logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	// 복구 코드로 로그인한 경우 OTP 재등록 페이지로 이동
+	if recovered {
+		logger.Auth.Warnw("Recovery code used", "user", user.Username, "ip", c.ClientIP())
+		c.Redirect(http.StatusFound, "/account/otp?recovered=1")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/")
+}
+
+// startUserSession 인증을 마친 사용자의 로그인 세션 생성 (아이디의 연속 실패 기록 초기화, CSRF 토큰 재발급)
+// method: 감사 로그에 기록할 2단계 인증 방식
+func startUserSession(c *gin.Context, user *db.User, method string) error {
+	resetLoginThrottle(user.Username)
+
 	sess := sessions.Default(c)
 	sess.Clear()
-	sess.Set("user_id", admin.ID)
+	sess.Set("user_id", user.ID)
 	now := time.Now().Unix()
+	sess.Set("login_at", now)
 	sess.Set("last_seen", now)
 	sess.Set("otp_verified_at", now)
+	if err := middleware.RotateCSRFToken(c, sess); err != nil {
+		return err
+	}
 	if err := sess.Save(); err != nil {
-		c.HTML(http.StatusInternalServerError, "login.html", gin.H{"Error": "세션 저장 실패"})
-		logger.LogError("Failed to save session info: IP=%s, err=%v", c.ClientIP(), err)
-		return
+		return err
 	}
 
-	c.Redirect(http.StatusFound, "/")
+	audit.Record(audit.Event{
+		Type:     audit.TypeLoginSuccess,
+		UserID:   user.ID,
+		Username: user.Username,
+		ClientIP: c.ClientIP(),
+		Target:   sessionstore.Handle(sess.ID()),
+		Details:  map[string]interface{}{"method": method},
+	})
+	metrics.LoginSucceeded(method)
+	return nil
 }
 
-// Logout [GET /logout] 로그아웃 처리
-func Logout(c *gin.Context) {
+// enrollingUser OTP 등록 진행 중인 사용자 조회 (비밀번호 인증 후 10분 이내만 유효)
+func enrollingUser(c *gin.Context) (*db.User, bool) {
 	sess := sessions.Default(c)
-	sess.Clear()
-	sess.Options(sessions.Options{MaxAge: -1, Path: "/"})
-	sess.Save()
-	c.Redirect(http.StatusFound, "/login")
-}
+	userID := sess.Get("enroll_user_id")
+	startedAt, _ := sess.Get("enroll_started_at").(int64)
+	if userID == nil || time.Since(time.Unix(startedAt, 0)) > 10*time.Minute {
+		return nil, false
+	}
 
-// HtmlIndex [GET /] 메인 페이지 렌더링
-func HtmlIndex(c *gin.Context) {
-	c.HTML(http.StatusOK, "index.html", gin.H{})
+	var user db.User
+	if err := db.SqliteDB.First(&user, userID).Error; err != nil || user.Disabled || user.OTPSecret != "" {
+		return nil, false
+	}
+	return &user, true
 }
 
-// HtmlTerminal [GET /terminal] 터미널 페이지 렌더링
-func HtmlTerminal(c *gin.Context) {
-	c.HTML(http.StatusOK, "terminal.html", gin.H{})
+// HtmlEnroll [GET /enroll] 최초 로그인 사용자의 OTP 등록 페이지 렌더링
+func HtmlEnroll(c *gin.Context) {
+	user, ok := enrollingUser(c)
+	if !ok {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	secret, qrBase64, ok := generateOTP(c, user.Username)
+	if !ok {
+		return
+	}
+
+	c.HTML(http.StatusOK, "enroll.html", gin.H{
+		"Username": user.Username,
+		"Secret":   secret,
+		"QRBase64": qrBase64,
+	})
 }
 
-// TerminalWS [GET /terminal/ws] 클라이언트와 서버 PTY 간의 웹소켓 브라우징 중
-func TerminalWS(c *gin.Context) {
-	// HTTP 연결을 웹소켓 프로토콜로 업그레이드 (Handshake)
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
-	if err != nil {
-		logger.LogError("Failed to upgrade web socket: IP=%s path=%s origin=%q UA=%q err=%v",
-			c.ClientIP(),
-			c.Request.URL.Path,
-			c.GetHeader("Origin"),
-			c.Request.UserAgent(),
-			err)
+// EnrollOTP [POST /enroll] OTP 등록 후 로그인 처리
+func EnrollOTP(c *gin.Context) {
+	user, ok := enrollingUser(c)
+	if !ok {
+		c.Redirect(http.StatusFound, "/login")
 		return
 	}
 
-	// 실행할 쉘 설정 (기본 bash) 및 환경 변수/작업 디렉토리 지정
-	cmd := exec.Command("/bin/bash")
-	cmd.Env = append(os.Environ(),
-		"TERM=xterm-256color",
-	)
-	cmd.Dir = "/root"
+	secret := c.PostForm("secret")
+	token := c.PostForm("otp_token")
 
-	// PTY(가상 터미널) 시작 및 쉘 실행
-	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Cols: 120, Rows: 30})
-	if err != nil {
-		conn.WriteMessage(websocket.TextMessage, []byte("failed to open pty"))
-		logger.LogError("Failed to start PTY: IP=%s, err=%v", c.ClientIP(), err)
-		conn.Close()
+	// OTP 번호 유효성 검증
+	step, ok := validateTOTP(token, secret)
+	if secret == "" || !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "OTP 인증 번호가 일치하지 않습니다."})
 		return
 	}
 
-	// 동기화 및 종료 처리를 위한 채널들
-	done1 := make(chan struct{})
-	done2 := make(chan struct{})
-	stopCh := make(chan struct{})
+	// 동시 요청으로 인한 중복 등록 방지 (미등록 상태인 경우에만 저장)
+	result := db.SqliteDB.Model(&db.User{}).
+		Where("id = ? AND (otp_secret IS NULL OR otp_secret = '')", user.ID).
+		Updates(map[string]interface{}{"otp_secret": secret, "otp_last_step": step})
+	if result.Error != nil || result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "OTP 등록에 실패했습니다. 다시 로그인하세요."})
+		if result.Error != nil {
+			logger.Auth.Errorw("Failed to store OTP secret", "ip", c.ClientIP(), "err", result.Error)
+		}
+		return
+	}
+
+	if err := startUserSession(c, user, "otp_enroll"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "세션 저장 실패"})
+		logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	// OTP 분실에 대비한 복구 코드 발급
+	var codes []string
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = generateRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		logger.Auth.Errorw("Failed to generate recovery codes", "ip", c.ClientIP(), "err", err)
+	}
+
+	logger.Auth.Infow("OTP enrolled", "user", user.Username, "ip", c.ClientIP())
+	audit.Record(audit.Event{Type: audit.TypeOTPEnroll, UserID: user.ID, Username: user.Username, ClientIP: c.ClientIP()})
+	c.HTML(http.StatusOK, "recovery_codes.html", gin.H{
+		"Codes":    codes,
+		"Next":     "/",
+		"NextText": "대시보드로 이동",
+	})
+}
+
+// Logout [GET /logout] 로그아웃 처리 (세션에서 열린 터미널 연결도 해제)
+// 다른 사이트의 링크로 로그아웃되지 않도록 CSRF 토큰이 일치할 때만 처리
+func Logout(c *gin.Context) {
+	if !middleware.ValidCSRFToken(c, c.Query(middleware.CSRFQueryParam)) {
+		c.Redirect(http.StatusFound, "/")
+		return
+	}
 
-	// 중복 종료를 방지하기 위한 안전 장치 (sync.Once)
-	var once sync.Once
-	stop := func() {
-		once.Do(func() {
-			// stopCh를 닫아 대기 중인 메인 루프에 종료 알림
-			close(stopCh)
+	sess := sessions.Default(c)
+	if id := sess.ID(); id != "" {
+		closeWSClients(id)
+	}
+	if userID, ok := sess.Get("user_id").(uint); ok {
+		audit.Record(audit.Event{
+			Type:     audit.TypeLogout,
+			UserID:   userID,
+			ClientIP: c.ClientIP(),
+			Target:   sessionstore.Handle(sess.ID()),
 		})
 	}
+	sess.Clear()
+	sess.Options(sessions.Options{MaxAge: -1, Path: "/"})
+	sess.Save()
+	c.Redirect(http.StatusFound, "/login")
+}
 
-	// --- 고루틴 A: 서버 PTY의 출력을 읽어서 클라이언트(웹브라우저)로 전달 ---
-	go func() {
-		defer close(done1)
-		defer stop()
-		buf := make([]byte, 8192) // 쉘로부터 출력 데이터 읽기
-		for {
-			n, rerr := ptmx.Read(buf)
-			if n > 0 {
-				// 웹소켓 쓰기 타임아웃 설정 (네트워크 지연 시 무한 대기 방지)
-				if err := conn.SetWriteDeadline(time.Now().Add(5 * time.Second)); err != nil {
-					logger.LogError("Failed to set write deadline: IP=%s, err=%v", c.ClientIP(), err)
-					return
-				}
-
-				// 읽은 데이터를 바이너리 형식으로 웹소켓 전송
-				if err := conn.WriteMessage(websocket.BinaryMessage, buf[:n]); err != nil {
-					logger.LogError("Failed to write message: IP=%s, err=%v", c.ClientIP(), err)
-					return
-				}
-			}
-			if rerr != nil {
-				return
-			}
-		}
-	}()
-
-	// --- 고루틴 B: 클라이언트의 입력을 읽어서 서버 PTY로 전달 ---
-	go func() {
-		defer close(done2)
-		defer stop()
-		for {
-			// 클라이언트로부터 웹소켓 메시지 수신
-			msgType, msg, err := conn.ReadMessage()
-			if err != nil {
-				logger.LogError("Failed to read message: %v", err)
-				return
-			}
-
-			// Case 1: 순수 터미널 입력 데이터 (바이너리)
-			if msgType == websocket.BinaryMessage {
-				if len(msg) > 0 {
-					// 수신한 키 입력 등을 실제 쉘 프로세스(PTY)에 쓰기
-					if _, err := ptmx.Write(msg); err != nil {
-						logger.LogError("Failed to write PTY: IP=%s, err=%v", c.ClientIP(), err)
-						return
-					}
-				}
-			} else if msgType == websocket.TextMessage {
-				// Case 2: 터미널 제어용 메시지 (JSON 형식, 예: 리사이즈)
-				var r wsMsg
-				if err := json.Unmarshal(msg, &r); err != nil {
-					logger.LogWarn("Failed to Unmarshal: IP=%s, err=%v", c.ClientIP(), err)
-					continue
-				}
-				// 브라우저 창 크기가 변했을 때 PTY 크기도 동기화
-				if r.MsgType == "resize" {
-					if r.Cols > 0 && r.Rows > 0 {
-						pty.Setsize(ptmx, &pty.Winsize{
-							Cols: uint16(r.Cols),
-							Rows: uint16(r.Rows),
-						})
-					}
-				}
-			}
-		}
-	}()
-
-	// 종료 처리 대기 (어느 한쪽 고루틴이라도 stop()을 호출하면 해제됨)
-	<-stopCh
-	// 자원 정리 (연결 해제 및 프로세스 종료)
-	conn.Close()       // 웹소켓 닫기
-	<-done2            // WS 읽기 고루틴 종료 대기
-	ptmx.Close()       // PTY 디바이스 닫기
-	cmd.Process.Kill() // 쉘 프로세스 강제 종료
-	cmd.Process.Wait() // 좀비 프로세스 방지를 위한 상태 대기
-	<-done1            // PTY 읽기 고루틴 종료 대기
+// HtmlIndex [GET /] 메인 페이지 렌더링
+func HtmlIndex(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+	c.HTML(http.StatusOK, "index.html", gin.H{
+		"User":         user,
+		"IsAdmin":      user.IsAdmin(),
+		"IsOperator":   user.HasRole(db.RoleOperator),
+		"FilesEnabled": config.Conf.Files.Enabled,
+	})
 }
 
 // Ping [GET /ping] 세션 유지를 위한 단순 응답 핸들러
@@ -0,0 +1,141 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
+)
+
+// loginSessionView 로그인 세션 목록 항목
+type loginSessionView struct {
+	Handle     string // 세션 ID 대신 화면에 노출하는 식별자
+	Username   string
+	ClientIP   string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	Terminals  int  // 열려 있는 터미널 연결 수
+	Current    bool // 요청한 관리자 본인의 세션 여부
+}
+
+// activeLoginSessions 만료되지 않은 로그인 세션 목록 조회 (최근 접속 순)
+func activeLoginSessions() ([]db.WebSession, error) {
+	var rows []db.WebSession
+	err := db.SqliteDB.Omit("data").
+		Where("user_id > 0 AND expires_at > ?", time.Now()).
+		Order("last_seen_at DESC").
+		Find(&rows).Error
+	return rows, err
+}
+
+// HtmlLoginSessions [GET /login-sessions] 로그인 세션 관리 페이지 렌더링
+func HtmlLoginSessions(c *gin.Context) {
+	rows, err := activeLoginSessions()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "로그인 세션 조회 실패")
+		logger.Auth.Errorw("Failed to query login sessions", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	var users []db.User
+	db.SqliteDB.Select("id", "username").Find(&users)
+	usernames := make(map[uint]string, len(users))
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+	}
+
+	current := sessions.Default(c).ID()
+	views := make([]loginSessionView, 0, len(rows))
+	for _, row := range rows {
+		views = append(views, loginSessionView{
+			Handle:     sessionstore.Handle(row.ID),
+			Username:   usernames[row.UserID],
+			ClientIP:   row.ClientIP,
+			UserAgent:  row.UserAgent,
+			CreatedAt:  row.CreatedAt,
+			LastSeenAt: row.LastSeenAt,
+			ExpiresAt:  row.ExpiresAt,
+			Terminals:  countWSClients(row.ID),
+			Current:    row.ID == current,
+		})
+	}
+
+	c.HTML(http.StatusOK, "login_sessions.html", gin.H{
+		"User":     middleware.CurrentUser(c),
+		"Sessions": views,
+	})
+}
+
+// RevokeLoginSession [DELETE /api/login-sessions/:id] 로그인 세션 강제 종료 (열린 터미널 연결 포함)
+func RevokeLoginSession(c *gin.Context) {
+	rows, err := activeLoginSessions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "로그인 세션 조회 실패"})
+		logger.Auth.Errorw("Failed to query login sessions", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	var target *db.WebSession
+	for i := range rows {
+		if sessionstore.Handle(rows[i].ID) == c.Param("id") {
+			target = &rows[i]
+			break
+		}
+	}
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "세션을 찾을 수 없습니다."})
+		return
+	}
+
+	if err := sessionstore.Revoke(target.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "세션 종료 실패"})
+		logger.Auth.Errorw("Failed to revoke login session", "ip", c.ClientIP(), "err", err)
+		return
+	}
+	closed := closeWSClients(target.ID)
+
+	logger.Auth.Infow("Login session revoked",
+		"userID", target.UserID, "sessionIP", target.ClientIP, "terminals", closed, "by", middleware.CurrentUser(c).Username, "ip", c.ClientIP())
+	var owner db.User
+	db.SqliteDB.Select("username").First(&owner, target.UserID)
+	recordAudit(c, audit.TypeSessionRevoke, owner.Username, map[string]interface{}{
+		"session":   c.Param("id"),
+		"sessionIp": target.ClientIP,
+		"terminals": closed,
+	})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// revokeUserSessions 사용자의 모든 로그인 세션과 열린 터미널 연결 종료
+func revokeUserSessions(c *gin.Context, user *db.User) {
+	ids, err := sessionstore.RevokeUser(user.ID)
+	if err != nil {
+		logger.Auth.Errorw("Failed to revoke login sessions", "user", user.Username, "ip", c.ClientIP(), "err", err)
+		return
+	}
+	closeWSClients(ids...)
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+)
+
+// logLevelRequest 로그 레벨 변경 요청
+type logLevelRequest struct {
+	Level string `json:"level" binding:"required"`
+}
+
+// GetLogLevel [GET /api/log/level] 현재 로그 레벨 조회
+func GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"level":  logger.Level(),
+		"base":   logger.BaseLevel(),
+		"levels": logger.Levels,
+	})
+}
+
+// SetLogLevel [PUT /api/log/level] 재시작 없이 로그 레벨 변경 (재시작하면 설정 파일의 레벨로 돌아감)
+func SetLogLevel(c *gin.Context) {
+	var req logLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	prev := logger.Level()
+	if err := logger.SetLevel(req.Level); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "지원하지 않는 로그 레벨입니다: " + req.Level})
+		return
+	}
+	if prev != req.Level {
+		recordAudit(c, audit.TypeLogLevel, "", map[string]interface{}{"from": prev, "to": req.Level})
+		logger.Server.Warnw("Log level changed",
+			"from", prev, "to", req.Level, "by", middleware.CurrentUser(c).Username, "ip", c.ClientIP())
+	}
+	GetLogLevel(c)
+}
@@ -0,0 +1,38 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/metrics"
+)
+
+// 메트릭 조회 핸들러 (요청마다 생성하지 않도록 한 번만 생성)
+var metricsHandler = metrics.Handler()
+
+// Metrics [GET /metrics] Prometheus 메트릭 조회 (설정된 토큰 또는 허용 IP로 접근 제어)
+func Metrics(c *gin.Context) {
+	if !metrics.Authorize(c.ClientIP(), c.GetHeader("Authorization")) {
+		logger.Server.Warnw("Rejected metrics request", "ip", c.ClientIP())
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	metricsHandler.ServeHTTP(c.Writer, c.Request)
+}
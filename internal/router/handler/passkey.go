@@ -0,0 +1,512 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+)
+
+// 패스키 이름 최대 길이
+const maxPasskeyNameLen = 64
+
+// 2단계 인증(패스키) 대기 유효 시간
+const mfaPendingTimeout = 5 * time.Minute
+
+// webauthnUser webauthn.User 인터페이스 구현 (사용자와 등록된 인증 장치)
+type webauthnUser struct {
+	user  *db.User
+	creds []db.WebAuthnCredential
+}
+
+// WebAuthnID 사용자 핸들 (사용자 ID)
+func (u *webauthnUser) WebAuthnID() []byte {
+	id := make([]byte, 8)
+	binary.BigEndian.PutUint64(id, uint64(u.user.ID))
+	return id
+}
+
+// WebAuthnName 사용자 아이디
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Username
+}
+
+// WebAuthnDisplayName 표시 이름
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	return u.user.Username
+}
+
+// WebAuthnIcon 아이콘 URL (사용하지 않음)
+func (u *webauthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+// WebAuthnCredentials 등록된 인증 장치 목록
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	list := make([]webauthn.Credential, 0, len(u.creds))
+	for _, c := range u.creds {
+		var cred webauthn.Credential
+		if err := json.Unmarshal(c.Data, &cred); err != nil {
+			logger.Auth.Warnw("Failed to decode passkey", "id", c.ID, "err", err)
+			continue
+		}
+		list = append(list, cred)
+	}
+	return list
+}
+
+// loadWebAuthnUser 사용자와 등록된 인증 장치 조회
+func loadWebAuthnUser(user *db.User) (*webauthnUser, error) {
+	var creds []db.WebAuthnCredential
+	if err := db.SqliteDB.Where("user_id = ?", user.ID).Order("id").Find(&creds).Error; err != nil {
+		return nil, err
+	}
+	return &webauthnUser{user: user, creds: creds}, nil
+}
+
+// countPasskeys 사용자가 등록한 패스키 개수
+func countPasskeys(userID uint) int64 {
+	var count int64
+	db.SqliteDB.Model(&db.WebAuthnCredential{}).Where("user_id = ?", userID).Count(&count)
+	return count
+}
+
+// newWebAuthn Relying Party 설정 생성 (설정 값이 없으면 접속 호스트 기준)
+func newWebAuthn(c *gin.Context) (*webauthn.WebAuthn, error) {
+	rpID := config.Conf.WebAuthn.RPID
+	origins := config.Conf.WebAuthn.RPOrigins
+
+	if rpID == "" || len(origins) == 0 {
+		host := c.Request.Host
+		hostname := host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			hostname = h
+		}
+		if rpID == "" {
+			rpID = hostname
+		}
+		if len(origins) == 0 {
+			origins = []string{"https://" + host}
+		}
+	}
+
+	return webauthn.New(&webauthn.Config{
+		RPID:          rpID,
+		RPDisplayName: "RootWeb",
+		RPOrigins:     origins,
+	})
+}
+
+// saveWebAuthnSession 인증 절차(challenge) 정보를 세션에 저장
+func saveWebAuthnSession(c *gin.Context, key string, data *webauthn.SessionData) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	sess := sessions.Default(c)
+	sess.Set(key, string(raw))
+	return sess.Save()
+}
+
+// loadWebAuthnSession 세션에 저장된 인증 절차 정보 조회 (재사용 방지를 위해 조회 후 삭제)
+func loadWebAuthnSession(c *gin.Context, key string) (*webauthn.SessionData, bool) {
+	sess := sessions.Default(c)
+	raw, _ := sess.Get(key).(string)
+	sess.Delete(key)
+	sess.Save()
+	if raw == "" {
+		return nil, false
+	}
+
+	var data webauthn.SessionData
+	if err := json.Unmarshal([]byte(raw), &data); err != nil {
+		return nil, false
+	}
+	return &data, true
+}
+
+// passkeyView 패스키 목록 응답 형식
+type passkeyView struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+}
+
+// HtmlPasskeys [GET /account/passkeys] 패스키 관리 페이지 렌더링
+func HtmlPasskeys(c *gin.Context) {
+	c.HTML(http.StatusOK, "passkeys.html", gin.H{
+		"User": middleware.CurrentUser(c),
+	})
+}
+
+// ListPasskeys [GET /api/passkeys] 본인 패스키 목록 조회
+func ListPasskeys(c *gin.Context) {
+	var creds []db.WebAuthnCredential
+	err := db.SqliteDB.Where("user_id = ?", middleware.CurrentUser(c).ID).Order("id").Find(&creds).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 목록 조회 실패"})
+		logger.Auth.Errorw("Failed to query passkeys", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	views := make([]passkeyView, 0, len(creds))
+	for _, cred := range creds {
+		views = append(views, passkeyView{
+			ID:         cred.ID,
+			Name:       cred.Name,
+			CreatedAt:  cred.CreatedAt,
+			LastUsedAt: cred.LastUsedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"passkeys": views})
+}
+
+// BeginPasskeyRegistration [POST /api/passkeys/register/begin] 패스키 등록 옵션 생성
+func BeginPasskeyRegistration(c *gin.Context) {
+	wa, err := newWebAuthn(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "WebAuthn 설정 오류"})
+		logger.Auth.Errorw("Failed to configure WebAuthn", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	wu, err := loadWebAuthnUser(middleware.CurrentUser(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 목록 조회 실패"})
+		logger.Auth.Errorw("Failed to query passkeys", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	// 이미 등록된 인증 장치는 중복 등록 방지
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(wu.creds))
+	for _, cred := range wu.WebAuthnCredentials() {
+		exclusions = append(exclusions, cred.Descriptor())
+	}
+
+	options, sessionData, err := wa.BeginRegistration(wu,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 등록 준비 실패"})
+		logger.Auth.Errorw("Failed to begin passkey registration", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	if err := saveWebAuthnSession(c, "webauthn_reg", sessionData); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "세션 저장 실패"})
+		logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishPasskeyRegistration [POST /api/passkeys/register/finish?name=] 인증 장치 응답 검증 후 패스키 저장
+func FinishPasskeyRegistration(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+
+	name := strings.TrimSpace(c.Query("name"))
+	if name == "" || utf8.RuneCountInString(name) > maxPasskeyNameLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "패스키 이름은 1~64자로 입력하세요."})
+		return
+	}
+
+	sessionData, ok := loadWebAuthnSession(c, "webauthn_reg")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "등록 요청이 만료되었습니다. 다시 시도하세요."})
+		return
+	}
+
+	wa, err := newWebAuthn(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "WebAuthn 설정 오류"})
+		logger.Auth.Errorw("Failed to configure WebAuthn", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	wu, err := loadWebAuthnUser(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 목록 조회 실패"})
+		logger.Auth.Errorw("Failed to query passkeys", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	cred, err := wa.FinishRegistration(wu, *sessionData, c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "패스키 등록에 실패했습니다."})
+		logger.Auth.Warnw("Failed to verify passkey registration", "user", user.Username, "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	data, err := json.Marshal(cred)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 저장 실패"})
+		logger.Auth.Errorw("Failed to encode passkey", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	record := db.WebAuthnCredential{
+		UserID:       user.ID,
+		Name:         name,
+		CredentialID: cred.ID,
+		Data:         data,
+	}
+	if err := db.SqliteDB.Create(&record).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "이미 등록된 인증 장치입니다."})
+		logger.Auth.Warnw("Failed to store passkey", "user", user.Username, "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.Auth.Infow("Passkey registered", "user", user.Username, "name", name, "ip", c.ClientIP())
+	recordAudit(c, audit.TypePasskeyAdd, user.Username, map[string]interface{}{"id": record.ID, "name": name})
+	c.JSON(http.StatusCreated, gin.H{"passkey": passkeyView{ID: record.ID, Name: record.Name, CreatedAt: record.CreatedAt}})
+}
+
+// DeletePasskey [DELETE /api/passkeys/:id] 본인 패스키 삭제
+func DeletePasskey(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	// OTP 미등록 사용자의 마지막 패스키는 삭제 불가 (2단계 인증 수단이 없어짐)
+	if user.OTPSecret == "" && countPasskeys(user.ID) <= 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "마지막 2단계 인증 수단은 삭제할 수 없습니다."})
+		return
+	}
+
+	result := db.SqliteDB.Unscoped().Where("id = ? AND user_id = ?", id, user.ID).Delete(&db.WebAuthnCredential{})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 삭제 실패"})
+		logger.Auth.Errorw("Failed to delete passkey", "ip", c.ClientIP(), "err", result.Error)
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "패스키를 찾을 수 없습니다."})
+		return
+	}
+
+	logger.Auth.Infow("Passkey deleted", "user", user.Username, "id", id, "ip", c.ClientIP())
+	recordAudit(c, audit.TypePasskeyDelete, user.Username, map[string]interface{}{"id": id})
+	c.Status(http.StatusNoContent)
+}
+
+// startMFA 비밀번호 인증을 마친 사용자의 2단계(패스키) 인증 대기 상태 저장 (CSRF 토큰 재발급)
+func startMFA(c *gin.Context, user *db.User) error {
+	sess := sessions.Default(c)
+	sess.Clear()
+	sess.Set("mfa_user_id", user.ID)
+	sess.Set("mfa_started_at", time.Now().Unix())
+	if err := middleware.RotateCSRFToken(c, sess); err != nil {
+		return err
+	}
+	return sess.Save()
+}
+
+// pendingMFAUser 2단계 인증 대기 중인 사용자 조회 (비밀번호 인증 후 5분 이내만 유효)
+func pendingMFAUser(c *gin.Context) (*db.User, bool) {
+	sess := sessions.Default(c)
+	userID := sess.Get("mfa_user_id")
+	startedAt, _ := sess.Get("mfa_started_at").(int64)
+	if userID == nil || time.Since(time.Unix(startedAt, 0)) > mfaPendingTimeout {
+		return nil, false
+	}
+
+	var user db.User
+	if err := db.SqliteDB.First(&user, userID).Error; err != nil || user.Disabled {
+		return nil, false
+	}
+	return &user, true
+}
+
+// HtmlPasskeyLogin [GET /login/passkey] 패스키 인증 페이지 렌더링 (인증 장치 선택)
+func HtmlPasskeyLogin(c *gin.Context) {
+	user, ok := pendingMFAUser(c)
+	if !ok {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	var creds []db.WebAuthnCredential
+	db.SqliteDB.Where("user_id = ?", user.ID).Order("id").Find(&creds)
+	if len(creds) == 0 {
+		c.Redirect(http.StatusFound, "/login")
+		return
+	}
+
+	c.HTML(http.StatusOK, "passkey_login.html", gin.H{
+		"Username": user.Username,
+		"Passkeys": creds,
+	})
+}
+
+// BeginPasskeyLogin [POST /login/passkey/begin?id=] 패스키 인증 옵션 생성 (id 지정 시 해당 인증 장치만 허용)
+func BeginPasskeyLogin(c *gin.Context) {
+	user, ok := pendingMFAUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "로그인이 만료되었습니다. 다시 로그인하세요."})
+		return
+	}
+
+	// OTP 인증과 동일하게 연속 실패한 아이디 또는 IP는 거부
+	if wait := checkLoginThrottle(user.Username, c.ClientIP()); wait > 0 {
+		auditLoginFailure(c, user.Username, failThrottled)
+		setRetryAfter(c, wait)
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttleMessage(wait)})
+		return
+	}
+
+	wa, err := newWebAuthn(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "WebAuthn 설정 오류"})
+		logger.Auth.Errorw("Failed to configure WebAuthn", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	wu, err := loadWebAuthnUser(user)
+	if err != nil || len(wu.creds) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "등록된 패스키가 없습니다."})
+		return
+	}
+
+	// 선택한 인증 장치만 허용
+	if id := c.Query("id"); id != "" {
+		var selected []db.WebAuthnCredential
+		for _, cred := range wu.creds {
+			if strconv.FormatUint(uint64(cred.ID), 10) == id {
+				selected = append(selected, cred)
+			}
+		}
+		if len(selected) == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "패스키를 찾을 수 없습니다."})
+			return
+		}
+		wu.creds = selected
+	}
+
+	options, sessionData, err := wa.BeginLogin(wu)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 인증 준비 실패"})
+		logger.Auth.Errorw("Failed to begin passkey login", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	if err := saveWebAuthnSession(c, "webauthn_login", sessionData); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "세션 저장 실패"})
+		logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// FinishPasskeyLogin [POST /login/passkey/finish] 인증 장치 서명 검증 후 로그인 처리
+func FinishPasskeyLogin(c *gin.Context) {
+	user, ok := pendingMFAUser(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "로그인이 만료되었습니다. 다시 로그인하세요."})
+		return
+	}
+
+	// OTP 인증과 동일하게 연속 실패한 아이디 또는 IP는 거부
+	if wait := checkLoginThrottle(user.Username, c.ClientIP()); wait > 0 {
+		auditLoginFailure(c, user.Username, failThrottled)
+		setRetryAfter(c, wait)
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttleMessage(wait)})
+		return
+	}
+
+	sessionData, ok := loadWebAuthnSession(c, "webauthn_login")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "인증 요청이 만료되었습니다. 다시 시도하세요."})
+		return
+	}
+
+	wa, err := newWebAuthn(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "WebAuthn 설정 오류"})
+		logger.Auth.Errorw("Failed to configure WebAuthn", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	wu, err := loadWebAuthnUser(user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "패스키 목록 조회 실패"})
+		logger.Auth.Errorw("Failed to query passkeys", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	cred, err := wa.FinishLogin(wu, *sessionData, c.Request)
+	if err != nil {
+		recordLoginFailure(c, user.Username, failPasskey, true)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "패스키 인증에 실패했습니다."})
+		logger.Auth.Warnw("Failed to verify passkey assertion", "user", user.Username, "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	// 서명 카운터가 역행하면 복제된 인증 장치로 판단하여 거부
+	if cred.Authenticator.CloneWarning {
+		auditLoginFailure(c, user.Username, failClone)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "패스키 인증에 실패했습니다."})
+		logger.Auth.Warnw("Passkey sign counter regressed (possible clone)", "user", user.Username, "ip", c.ClientIP())
+		return
+	}
+
+	// 서명 카운터 및 사용 시각 갱신
+	for _, record := range wu.creds {
+		if !bytes.Equal(record.CredentialID, cred.ID) {
+			continue
+		}
+		data, err := json.Marshal(cred)
+		if err != nil {
+			break
+		}
+		now := time.Now()
+		db.SqliteDB.Model(&record).Updates(map[string]any{"data": data, "last_used_at": &now})
+	}
+
+	if err := startUserSession(c, user, "passkey"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "세션 저장 실패"})
+		logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.Auth.Infow("Passkey login", "user", user.Username, "ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"redirect": "/"})
+}
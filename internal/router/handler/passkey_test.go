@@ -0,0 +1,436 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+const (
+	testRPID   = "rootweb.test"
+	testOrigin = "https://rootweb.test"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "rootweb-handler")
+	if err != nil {
+		panic(err)
+	}
+	logger.InitializeLogger(filepath.Join(dir, "test.log"), 1, 1, 1, false, false, logger.FormatConsole, "")
+	if err := db.InitSqliteDB(filepath.Join(dir, "test.db")); err != nil {
+		panic(err)
+	}
+	gin.SetMode(gin.TestMode)
+	config.Conf.WebAuthn.RPID = testRPID
+	config.Conf.WebAuthn.RPOrigins = []string{testOrigin}
+
+	code := m.Run()
+	logger.FinalizeLogger()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// softAuthenticator 단위 테스트용 소프트웨어 인증 장치 (ECDSA P-256, "none" 증명)
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	id := make([]byte, 16)
+	rand.Read(id)
+	return &softAuthenticator{key: key, id: id}
+}
+
+// b64 base64url (패딩 없음) 인코딩
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// clientData 브라우저가 생성하는 clientDataJSON
+func clientData(typ, challenge, origin string) []byte {
+	raw, _ := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": origin})
+	return raw
+}
+
+// authData 인증 장치 데이터 (rpIdHash, flags, signCount, 등록 시 공개키 포함)
+func (a *softAuthenticator) authData(attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	buf := bytes.NewBuffer(rpIDHash[:])
+
+	flags := byte(0x01 | 0x04) // UP, UV
+	if attested {
+		flags |= 0x40 // AT
+	}
+	buf.WriteByte(flags)
+	binary.Write(buf, binary.BigEndian, a.signCount)
+
+	if attested {
+		buf.Write(make([]byte, 16)) // AAGUID
+		binary.Write(buf, binary.BigEndian, uint16(len(a.id)))
+		buf.Write(a.id)
+		x := make([]byte, 32)
+		y := make([]byte, 32)
+		a.key.X.FillBytes(x)
+		a.key.Y.FillBytes(y)
+		coseKey, _ := webauthncbor.Marshal(map[int]interface{}{1: 2, 3: -7, -1: 1, -2: x, -3: y})
+		buf.Write(coseKey)
+	}
+	return buf.Bytes()
+}
+
+// create 등록 요청(challenge)에 대한 인증 장치 응답 (navigator.credentials.create 결과)
+func (a *softAuthenticator) create(t *testing.T, challenge, origin string) []byte {
+	t.Helper()
+
+	attObj, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(true),
+	})
+	if err != nil {
+		t.Fatalf("encode attestation object: %v", err)
+	}
+	raw, _ := json.Marshal(map[string]interface{}{
+		"id":    b64(a.id),
+		"rawId": b64(a.id),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(clientData("webauthn.create", challenge, origin)),
+			"attestationObject": b64(attObj),
+		},
+	})
+	return raw
+}
+
+// get 인증 요청(challenge)에 대한 서명 응답 (navigator.credentials.get 결과, 서명 카운터 1 증가)
+func (a *softAuthenticator) get(t *testing.T, challenge, origin string, userHandle []byte) []byte {
+	t.Helper()
+
+	a.signCount++
+	authData := a.authData(false)
+	cd := clientData("webauthn.get", challenge, origin)
+	cdHash := sha256.Sum256(cd)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), cdHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatalf("sign assertion: %v", err)
+	}
+
+	raw, _ := json.Marshal(map[string]interface{}{
+		"id":    b64(a.id),
+		"rawId": b64(a.id),
+		"type":  "public-key",
+		"response": map[string]string{
+			"clientDataJSON":    b64(cd),
+			"authenticatorData": b64(authData),
+			"signature":         b64(sig),
+			"userHandle":        b64(userHandle),
+		},
+	})
+	return raw
+}
+
+// createTestUser 테스트용 사용자 생성
+func createTestUser(t *testing.T, username string) *db.User {
+	t.Helper()
+
+	user := &db.User{Username: username, Password: "x", Role: db.RoleAdmin}
+	if err := db.SqliteDB.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// registerPasskey 등록 절차를 수행하고 검증된 인증 장치 정보를 반환
+func registerPasskey(t *testing.T, wa *webauthn.WebAuthn, user *db.User, auth *softAuthenticator, origin string) (*webauthn.Credential, error) {
+	t.Helper()
+
+	wu := &webauthnUser{user: user}
+	_, sessionData, err := wa.BeginRegistration(wu)
+	if err != nil {
+		return nil, err
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/passkeys/register/finish", bytes.NewReader(auth.create(t, sessionData.Challenge, origin)))
+	return wa.FinishRegistration(wu, *sessionData, req)
+}
+
+func testWebAuthn(t *testing.T) *webauthn.WebAuthn {
+	t.Helper()
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	wa, err := newWebAuthn(c)
+	if err != nil {
+		t.Fatalf("newWebAuthn: %v", err)
+	}
+	return wa
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	wa := testWebAuthn(t)
+	user := createTestUser(t, "reg_user")
+
+	auth := newSoftAuthenticator(t)
+	cred, err := registerPasskey(t, wa, user, auth, testOrigin)
+	if err != nil {
+		t.Fatalf("registration failed: %v", err)
+	}
+	if !bytes.Equal(cred.ID, auth.id) {
+		t.Errorf("credential ID = %x, want %x", cred.ID, auth.id)
+	}
+	if cred.AttestationType != "none" {
+		t.Errorf("attestation type = %q, want %q", cred.AttestationType, "none")
+	}
+
+	// 다른 출처에서 생성된 응답은 거부
+	if _, err := registerPasskey(t, wa, user, newSoftAuthenticator(t), "https://evil.example.com"); err == nil {
+		t.Error("registration from a foreign origin succeeded")
+	}
+}
+
+// newPasskeyLoginServer 비밀번호 인증을 마친 상태(/mfa)와 패스키 인증 라우트만 등록한 테스트 서버 생성
+func newPasskeyLoginServer(t *testing.T, user *db.User) *httptest.Server {
+	t.Helper()
+
+	r := gin.New()
+	r.Use(sessions.Sessions("rootweb_sess", cookie.NewStore([]byte("0123456789abcdef0123456789abcdef"))))
+	r.POST("/mfa", func(c *gin.Context) {
+		if err := startMFA(c, user); err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	r.POST("/login/passkey/begin", BeginPasskeyLogin)
+	r.POST("/login/passkey/finish", FinishPasskeyLogin)
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// passkeyLogin 2단계 인증 대기 상태에서 패스키 인증 절차를 수행하고 응답 상태 코드 반환
+func passkeyLogin(t *testing.T, srv *httptest.Server, user *db.User, auth *softAuthenticator, origin string) int {
+	t.Helper()
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	post := func(path string, body []byte) *http.Response {
+		resp, err := client.Post(srv.URL+path, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		return resp
+	}
+
+	post("/mfa", nil).Body.Close()
+
+	resp := post("/login/passkey/begin", nil)
+	var options struct {
+		PublicKey struct {
+			Challenge string `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	err := json.NewDecoder(resp.Body).Decode(&options)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || err != nil {
+		t.Fatalf("begin login: status=%d, err=%v", resp.StatusCode, err)
+	}
+
+	wu := &webauthnUser{user: user}
+	resp = post("/login/passkey/finish", auth.get(t, options.PublicKey.Challenge, origin, wu.WebAuthnID()))
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+// storedSignCount DB에 저장된 인증 장치의 서명 카운터
+func storedSignCount(t *testing.T, credID []byte) uint32 {
+	t.Helper()
+
+	var record db.WebAuthnCredential
+	if err := db.SqliteDB.Where("credential_id = ?", credID).First(&record).Error; err != nil {
+		t.Fatalf("load passkey: %v", err)
+	}
+	var cred webauthn.Credential
+	if err := json.Unmarshal(record.Data, &cred); err != nil {
+		t.Fatalf("decode passkey: %v", err)
+	}
+	return cred.Authenticator.SignCount
+}
+
+// countLoginFailures 사유별 로그인 실패 감사 로그 수
+func countLoginFailures(t *testing.T, username, reason string) int64 {
+	t.Helper()
+
+	var count int64
+	db.SqliteDB.Model(&db.AuditEvent{}).
+		Where("type = ? AND username = ? AND details LIKE ?", audit.TypeLoginFailure, username, `%"`+reason+`"%`).
+		Count(&count)
+	return count
+}
+
+// clearLoginThrottles 앞선 인증 실패로 생긴 시도 제한 해제 (실패 직후 다시 인증하기 위함)
+func clearLoginThrottles(t *testing.T) {
+	t.Helper()
+
+	if err := db.SqliteDB.Unscoped().Where("1 = 1").Delete(&db.LoginThrottle{}).Error; err != nil {
+		t.Fatalf("clear login throttles: %v", err)
+	}
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	wa := testWebAuthn(t)
+	user := createTestUser(t, "login_user")
+	auth := newSoftAuthenticator(t)
+
+	cred, err := registerPasskey(t, wa, user, auth, testOrigin)
+	if err != nil {
+		t.Fatalf("registration failed: %v", err)
+	}
+	data, _ := json.Marshal(cred)
+	if err := db.SqliteDB.Create(&db.WebAuthnCredential{UserID: user.ID, Name: "soft", CredentialID: cred.ID, Data: data}).Error; err != nil {
+		t.Fatalf("store passkey: %v", err)
+	}
+
+	srv := newPasskeyLoginServer(t, user)
+
+	if got := passkeyLogin(t, srv, user, auth, testOrigin); got != http.StatusOK {
+		t.Fatalf("login status = %d, want %d", got, http.StatusOK)
+	}
+	if got := storedSignCount(t, cred.ID); got != auth.signCount {
+		t.Errorf("stored sign count = %d, want %d", got, auth.signCount)
+	}
+
+	// 다른 출처에서 서명한 응답은 거부
+	if got := passkeyLogin(t, srv, user, auth, "https://evil.example.com"); got != http.StatusUnauthorized {
+		t.Errorf("foreign origin: login status = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := countLoginFailures(t, user.Username, failPasskey); got != 1 {
+		t.Errorf("passkey failure events = %d, want 1", got)
+	}
+	clearLoginThrottles(t)
+
+	// 서명 카운터가 저장된 값 이하로 역행하면 복제된 인증 장치로 판단
+	stored := storedSignCount(t, cred.ID)
+	auth.signCount = stored - 1
+	if got := passkeyLogin(t, srv, user, auth, testOrigin); got != http.StatusUnauthorized {
+		t.Errorf("regressed counter: login status = %d, want %d", got, http.StatusUnauthorized)
+	}
+	if got := countLoginFailures(t, user.Username, failClone); got != 1 {
+		t.Errorf("passkey clone events = %d, want 1", got)
+	}
+	if got := storedSignCount(t, cred.ID); got != stored {
+		t.Errorf("stored sign count after clone = %d, want %d", got, stored)
+	}
+
+	// 카운터가 다시 증가하면 정상 인증
+	auth.signCount = stored
+	if got := passkeyLogin(t, srv, user, auth, testOrigin); got != http.StatusOK {
+		t.Errorf("login after clone check: status = %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestPasskeyLoginThrottled(t *testing.T) {
+	wa := testWebAuthn(t)
+	user := createTestUser(t, "locked_user")
+	auth := newSoftAuthenticator(t)
+
+	cred, err := registerPasskey(t, wa, user, auth, testOrigin)
+	if err != nil {
+		t.Fatalf("registration failed: %v", err)
+	}
+	data, _ := json.Marshal(cred)
+	if err := db.SqliteDB.Create(&db.WebAuthnCredential{UserID: user.ID, Name: "soft", CredentialID: cred.ID, Data: data}).Error; err != nil {
+		t.Fatalf("store passkey: %v", err)
+	}
+
+	clearLoginThrottles(t)
+	srv := newPasskeyLoginServer(t, user)
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	post := func(path string, body []byte) *http.Response {
+		resp, err := client.Post(srv.URL+path, "application/json", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST %s: %v", path, err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	post("/mfa", nil)
+	if resp := post("/login/passkey/begin", nil); resp.StatusCode != http.StatusOK {
+		t.Fatalf("begin login: status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	// 2단계 인증 대기 중에 계정이 잠기면 시작과 완료 모두 거부
+	lock := db.LoginThrottle{
+		Subject:       throttleUserPrefix + user.Username,
+		Failures:      loadThrottlePolicy().userMaxFailures,
+		LastFailureAt: time.Now(),
+		BlockedUntil:  time.Now().Add(time.Minute),
+		Locked:        true,
+	}
+	if err := db.SqliteDB.Create(&lock).Error; err != nil {
+		t.Fatalf("create throttle: %v", err)
+	}
+	t.Cleanup(func() { db.SqliteDB.Unscoped().Delete(&lock) })
+
+	for _, path := range []string{"/login/passkey/finish", "/login/passkey/begin"} {
+		resp := post(path, nil)
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.Errorf("%s: status = %d, want %d", path, resp.StatusCode, http.StatusTooManyRequests)
+		}
+		if resp.Header.Get("Retry-After") == "" {
+			t.Errorf("%s: missing Retry-After header", path)
+		}
+	}
+	if got := countLoginFailures(t, user.Username, failThrottled); got != 2 {
+		t.Errorf("throttled failure events = %d, want 2", got)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/pkg/asciicast"
+)
+
+// 재생 시 이벤트 사이 최대 대기 시간 (장시간 무입력 구간 생략)
+const replayMaxIdle = 3 * time.Second
+
+// findRecording URL 파라미터의 ID로 녹화 정보 조회 (관리자가 아니면 본인 녹화만 허용)
+func findRecording(c *gin.Context) (*db.Recording, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "잘못된 요청입니다.")
+		return nil, false
+	}
+
+	var rec db.Recording
+	query := db.SqliteDB
+	if user := middleware.CurrentUser(c); !user.IsAdmin() {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	if err := query.First(&rec, id).Error; err != nil {
+		c.String(http.StatusNotFound, "녹화 기록을 찾을 수 없습니다.")
+		return nil, false
+	}
+
+	return &rec, true
+}
+
+// HtmlRecordings [GET /recordings] 세션 녹화 목록 페이지 렌더링
+func HtmlRecordings(c *gin.Context) {
+	var records []db.Recording
+	query := db.SqliteDB.Order("started_at DESC").Limit(200)
+	if user := middleware.CurrentUser(c); !user.IsAdmin() {
+		query = query.Where("user_id = ?", user.ID)
+	}
+	if err := query.Find(&records).Error; err != nil {
+		c.String(http.StatusInternalServerError, "녹화 목록 조회 실패")
+		logger.Server.Errorw("Failed to query recordings", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	c.HTML(http.StatusOK, "recordings.html", gin.H{
+		"Recordings": records,
+	})
+}
+
+// HtmlReplay [GET /recordings/:id] 세션 녹화 재생 페이지 렌더링
+func HtmlReplay(c *gin.Context) {
+	rec, ok := findRecording(c)
+	if !ok {
+		return
+	}
+
+	c.HTML(http.StatusOK, "replay.html", gin.H{
+		"Recording": rec,
+	})
+}
+
+// DownloadRecording [GET /recordings/:id/download] 녹화 파일(asciicast v2) 다운로드
+func DownloadRecording(c *gin.Context) {
+	rec, ok := findRecording(c)
+	if !ok {
+		return
+	}
+
+	c.FileAttachment(rec.FilePath, filepath.Base(rec.FilePath))
+}
+
+// ReplayWS [GET /recordings/:id/ws] 녹화 파일을 실제 시간 간격(배속 적용)으로 웹소켓 전송
+func ReplayWS(c *gin.Context) {
+	rec, ok := findRecording(c)
+	if !ok {
+		return
+	}
+
+	// 재생 속도 (0.25 ~ 16 배속)
+	speed, err := strconv.ParseFloat(c.DefaultQuery("speed", "1"), 64)
+	if err != nil || speed < 0.25 || speed > 16 {
+		speed = 1
+	}
+
+	file, err := os.Open(rec.FilePath)
+	if err != nil {
+		c.String(http.StatusNotFound, "녹화 파일을 열 수 없습니다.")
+		logger.Server.Errorw("Failed to open recording file", "path", rec.FilePath, "err", err)
+		return
+	}
+	defer file.Close()
+
+	reader, err := asciicast.NewReader(file)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "녹화 파일 형식이 올바르지 않습니다.")
+		logger.Server.Errorw("Failed to read recording header", "path", rec.FilePath, "err", err)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Server.Errorw("Failed to upgrade web socket", "ip", c.ClientIP(), "path", c.Request.URL.Path, "err", err)
+		return
+	}
+	defer conn.Close()
+
+	// 클라이언트 연결 종료 감지
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// 초기 터미널 크기 전송
+	conn.WriteJSON(wsMsg{MsgType: "resize", Cols: reader.Header.Width, Rows: reader.Header.Height})
+
+	var prev float64
+	for {
+		evt, err := reader.Next()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				logger.Server.Warnw("Failed to read recording event", "path", rec.FilePath, "err", err)
+			}
+			break
+		}
+
+		// 이벤트 간격만큼 대기
+		delay := time.Duration((evt.Time - prev) * float64(time.Second))
+		if delay > replayMaxIdle {
+			delay = replayMaxIdle
+		}
+		prev = evt.Time
+		if delay > 0 {
+			select {
+			case <-closed:
+				return
+			case <-time.After(time.Duration(float64(delay) / speed)):
+			}
+		}
+
+		switch evt.Type {
+		case asciicast.EventOutput:
+			err = conn.WriteMessage(websocket.BinaryMessage, []byte(evt.Data))
+		case asciicast.EventResize:
+			if cols, rows, perr := asciicast.ParseResize(evt.Data); perr == nil {
+				err = conn.WriteJSON(wsMsg{MsgType: "resize", Cols: cols, Rows: rows})
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	// 재생 완료 알림
+	conn.WriteJSON(wsMsg{MsgType: "end"})
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+)
+
+// OTP 번호 갱신 주기 (단위:초)
+const otpPeriod = 30
+
+// 사용자당 발급하는 복구 코드 개수
+const recoveryCodeCount = 10
+
+// 복구 코드 인코딩 (소문자 base32, 12자 = 60비트)
+var recoveryEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// normalizeRecoveryCode 입력된 복구 코드에서 구분자와 공백을 제거하고 소문자로 변환
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
+
+// hashRecoveryCode 복구 코드 해시 (충분한 엔트로피를 가진 임의 값이므로 bcrypt 대신 SHA-256 사용)
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(normalizeRecoveryCode(code)))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateRecoveryCodes 기존 복구 코드를 폐기하고 새 복구 코드 발급 (평문은 반환 시에만 노출)
+func generateRecoveryCodes(tx *gorm.DB, userID uint) ([]string, error) {
+	if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&db.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	records := make([]db.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 8)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		enc := recoveryEncoding.EncodeToString(raw)[:12]
+		code := enc[0:4] + "-" + enc[4:8] + "-" + enc[8:12]
+
+		codes = append(codes, code)
+		records = append(records, db.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+
+	if err := tx.Create(&records).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// useRecoveryCode 미사용 복구 코드인지 확인 후 사용 처리 (동시 사용 방지를 위해 조건부 갱신)
+func useRecoveryCode(userID uint, code string) bool {
+	result := db.SqliteDB.Model(&db.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	return result.Error == nil && result.RowsAffected == 1
+}
+
+// remainingRecoveryCodes 사용하지 않은 복구 코드 개수
+func remainingRecoveryCodes(userID uint) int64 {
+	var count int64
+	db.SqliteDB.Model(&db.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userID).Count(&count)
+	return count
+}
+
+// isTOTPCode 6자리 숫자(OTP 번호) 형식 여부
+func isTOTPCode(token string) bool {
+	if len(token) != 6 {
+		return false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// validateTOTP OTP 번호 검증 후 일치한 시간 단계 반환 (앞뒤 1단계 시간 오차 허용)
+func validateTOTP(token, secret string) (int64, bool) {
+	opts := totp.ValidateOpts{Period: otpPeriod, Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	now := time.Now()
+	for _, skew := range []int64{0, -1, 1} {
+		at := now.Add(time.Duration(skew*otpPeriod) * time.Second)
+		if ok, _ := totp.ValidateCustom(token, secret, at, opts); ok {
+			return at.Unix() / otpPeriod, true
+		}
+	}
+	return 0, false
+}
+
+// consumeTOTP OTP 번호 검증 후 사용 처리
+// 이미 사용된 시간 단계 이하의 번호는 거부 (동시 사용 방지를 위해 조건부 갱신)
+func consumeTOTP(user *db.User, token string) bool {
+	step, ok := validateTOTP(token, user.OTPSecret)
+	if !ok {
+		return false
+	}
+
+	result := db.SqliteDB.Model(&db.User{}).
+		Where("id = ? AND otp_last_step < ?", user.ID, step).
+		Update("otp_last_step", step)
+	if result.Error == nil && result.RowsAffected == 1 {
+		user.OTPLastStep = step
+		return true
+	}
+	logger.Auth.Warnw("OTP code reuse rejected", "user", user.Username)
+	return false
+}
+
+// verifySecondFactor OTP 번호 또는 복구 코드 검증 (복구 코드 사용 여부 반환)
+func verifySecondFactor(user *db.User, token string) (ok bool, recovered bool) {
+	token = strings.TrimSpace(token)
+	if isTOTPCode(token) {
+		return user.OTPSecret != "" && consumeTOTP(user, token), false
+	}
+	if token == "" || !useRecoveryCode(user.ID, token) {
+		return false, false
+	}
+	return true, true
+}
+
+// HtmlAccountOTP [GET /account/otp] OTP 재등록 및 복구 코드 관리 페이지 렌더링
+func HtmlAccountOTP(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+
+	// 새로 등록할 OTP Secret 및 QR코드 생성
+	secret, qrBase64, ok := generateOTP(c, user.Username)
+	if !ok {
+		return
+	}
+
+	c.HTML(http.StatusOK, "account_otp.html", gin.H{
+		"User":      user,
+		"Enrolled":  user.OTPSecret != "",
+		"Secret":    secret,
+		"QRBase64":  qrBase64,
+		"Remaining": remainingRecoveryCodes(user.ID),
+		"Recovered": c.Query("recovered") != "",
+	})
+}
+
+// otpRotateReq OTP 재등록 요청 형식
+type otpRotateReq struct {
+	Current string `json:"current"` // 현재 OTP 번호 또는 복구 코드
+	Secret  string `json:"secret"`  // 새 OTP Secret
+	Token   string `json:"token"`   // 새 Secret으로 생성한 OTP 번호
+}
+
+// proveCurrentFactor 현재 OTP 번호 또는 복구 코드로 본인 확인 (OTP 미등록 사용자는 생략)
+func proveCurrentFactor(c *gin.Context, user *db.User, current string) bool {
+	if user.OTPSecret == "" {
+		return true
+	}
+
+	// 로그인과 동일하게 연속 실패 시 시도 제한
+	if wait := checkLoginThrottle(user.Username, c.ClientIP()); wait > 0 {
+		auditLoginFailure(c, user.Username, failThrottled)
+		setRetryAfter(c, wait)
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": throttleMessage(wait)})
+		return false
+	}
+
+	ok, recovered := verifySecondFactor(user, current)
+	if !ok {
+		recordLoginFailure(c, user.Username, failOTP, true)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "현재 OTP 번호 또는 복구 코드가 일치하지 않습니다."})
+		return false
+	}
+	if recovered {
+		logger.Auth.Warnw("Recovery code used", "user", user.Username, "ip", c.ClientIP())
+	}
+	return true
+}
+
+// RotateOTP [POST /api/account/otp] 현재 OTP(또는 복구 코드) 확인 후 새 OTP Secret으로 교체
+func RotateOTP(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+
+	var req otpRotateReq
+	if err := c.ShouldBindJSON(&req); err != nil || req.Secret == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	// 새 Secret으로 생성한 OTP 번호 확인 (인증 앱 등록 여부 검증)
+	step, ok := validateTOTP(req.Token, req.Secret)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "새 OTP 인증 번호가 일치하지 않습니다."})
+		return
+	}
+
+	if !proveCurrentFactor(c, user, req.Current) {
+		return
+	}
+
+	// 남은 복구 코드가 없으면 새로 발급
+	issueCodes := remainingRecoveryCodes(user.ID) == 0
+
+	var codes []string
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"otp_secret": req.Secret, "otp_last_step": step}
+		if err := tx.Model(user).Updates(updates).Error; err != nil {
+			return err
+		}
+		if !issueCodes {
+			return nil
+		}
+		var err error
+		codes, err = generateRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "OTP 변경 실패"})
+		logger.Auth.Errorw("Failed to rotate OTP secret", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.Auth.Infow("OTP secret rotated", "user", user.Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeOTPRotate, user.Username, map[string]interface{}{"recoveryCodesIssued": issueCodes})
+	c.JSON(http.StatusOK, gin.H{"codes": codes})
+}
+
+// RegenerateRecoveryCodes [POST /api/account/recovery-codes] 현재 OTP(또는 복구 코드) 확인 후 복구 코드 재발급
+func RegenerateRecoveryCodes(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+
+	var req otpRotateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	if !proveCurrentFactor(c, user, req.Current) {
+		return
+	}
+
+	var codes []string
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		codes, err = generateRecoveryCodes(tx, user.ID)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "복구 코드 발급 실패"})
+		logger.Auth.Errorw("Failed to generate recovery codes", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.Auth.Infow("Recovery codes regenerated", "user", user.Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeRecoveryCodes, user.Username, nil)
+	c.JSON(http.StatusOK, gin.H{"codes": codes})
+}
@@ -0,0 +1,506 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/internal/snippet"
+	"github.com/hoon-x/rootweb/internal/terminal"
+)
+
+// 저장된 명령 입력값 최대 길이
+const (
+	maxSnippetNameLen    = 100
+	maxSnippetDescLen    = 1000
+	maxSnippetCommandLen = 16 * 1024
+)
+
+// 실행 기록 목록 최대 개수
+const maxSnippetRunList = 100
+
+// snippetView 저장된 명령 응답 형식
+type snippetView struct {
+	ID          uint      `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Command     string    `json:"command"`
+	Params      []string  `json:"params"`
+	Shared      bool      `json:"shared"`
+	Owner       string    `json:"owner"`
+	Own         bool      `json:"own"`
+	Editable    bool      `json:"editable"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// snippetReq 저장된 명령 생성/수정 요청 형식
+type snippetReq struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Command     *string `json:"command"`
+	Shared      *bool   `json:"shared"`
+}
+
+// snippetUseReq 저장된 명령 실행/전송 요청 형식
+type snippetUseReq struct {
+	Command string            `json:"command"` // 화면에 표시된 명령 (실행/전송 시 저장된 명령과 같아야 함)
+	Params  map[string]string `json:"params"`
+	Session string            `json:"session"` // 전송할 터미널 세션 ID
+	Execute bool              `json:"execute"` // 전송 후 Enter 입력 여부
+}
+
+// snippetRunView 실행 기록 응답 형식 (목록에서는 출력 제외)
+type snippetRunView struct {
+	ID          uint       `json:"id"`
+	SnippetID   uint       `json:"snippetId"`
+	SnippetName string     `json:"snippetName"`
+	Username    string     `json:"username"`
+	UnixUser    string     `json:"unixUser"`
+	Command     string     `json:"command"`
+	Status      string     `json:"status"`
+	ExitCode    *int       `json:"exitCode,omitempty"`
+	Signal      string     `json:"signal,omitempty"`
+	Output      *string    `json:"output,omitempty"`
+	Truncated   bool       `json:"truncated"`
+	StartedAt   time.Time  `json:"startedAt"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"`
+}
+
+// newSnippetView 저장된 명령을 응답 형식으로 변환
+func newSnippetView(s *db.Snippet, me *db.User, owner string) snippetView {
+	return snippetView{
+		ID:          s.ID,
+		Name:        s.Name,
+		Description: s.Description,
+		Command:     s.Command,
+		Params:      snippet.Params(s.Command),
+		Shared:      s.Shared,
+		Owner:       owner,
+		Own:         s.UserID == me.ID,
+		Editable:    canEditSnippet(s, me),
+		UpdatedAt:   s.UpdatedAt,
+	}
+}
+
+// newSnippetRunView 실행 기록을 응답 형식으로 변환
+func newSnippetRunView(r *db.SnippetRun, withOutput bool) snippetRunView {
+	view := snippetRunView{
+		ID:          r.ID,
+		SnippetID:   r.SnippetID,
+		SnippetName: r.SnippetName,
+		Username:    r.Username,
+		UnixUser:    r.UnixUser,
+		Command:     r.Command,
+		Status:      r.Status,
+		ExitCode:    r.ExitCode,
+		Signal:      r.Signal,
+		Truncated:   r.Truncated,
+		StartedAt:   r.StartedAt,
+		EndedAt:     r.EndedAt,
+	}
+	if withOutput {
+		view.Output = &r.Output
+	}
+	return view
+}
+
+// canEditSnippet 저장된 명령 수정 권한 (작성자, 공유된 명령은 관리자도 가능)
+func canEditSnippet(s *db.Snippet, me *db.User) bool {
+	return s.UserID == me.ID || (s.Shared && me.IsAdmin())
+}
+
+// findSnippet URL 파라미터의 ID로 사용 가능한 저장된 명령 조회 (본인 또는 공유된 명령)
+func findSnippet(c *gin.Context, me *db.User) (*db.Snippet, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return nil, false
+	}
+
+	var s db.Snippet
+	if err := db.SqliteDB.First(&s, id).Error; err != nil || (s.UserID != me.ID && !s.Shared) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "저장된 명령을 찾을 수 없습니다."})
+		return nil, false
+	}
+	return &s, true
+}
+
+// validateSnippet 저장된 명령 입력값 정리 및 확인
+func validateSnippet(c *gin.Context, s *db.Snippet) bool {
+	s.Name = strings.TrimSpace(s.Name)
+	s.Description = strings.TrimSpace(s.Description)
+	switch {
+	case s.Name == "" || len(s.Name) > maxSnippetNameLen:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "이름은 1~100자로 입력하세요."})
+	case len(s.Description) > maxSnippetDescLen:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "설명은 1000자 이하로 입력하세요."})
+	case strings.TrimSpace(s.Command) == "" || len(s.Command) > maxSnippetCommandLen:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "명령은 1~16KB로 입력하세요."})
+	case strings.ContainsRune(s.Command, 0):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "명령에 NUL 문자를 사용할 수 없습니다."})
+	default:
+		return true
+	}
+	return false
+}
+
+// checkSnippetCommand 사용자가 확인한 명령이 현재 저장된 명령과 같은지 확인
+// (공유된 명령을 확인한 뒤 작성자가 바꾼 내용이 다른 계정 권한으로 실행되지 않도록 함)
+func checkSnippetCommand(c *gin.Context, s *db.Snippet, req *snippetUseReq) bool {
+	if req.Command != s.Command {
+		c.JSON(http.StatusConflict, gin.H{"error": "저장된 명령이 변경되었습니다. 목록을 새로고침한 후 다시 확인하세요."})
+		return false
+	}
+	return true
+}
+
+// renderSnippet 요청한 매개변수 값으로 명령 생성 (값이 빠졌거나 잘못되었으면 오류 응답)
+func renderSnippet(c *gin.Context, s *db.Snippet, req *snippetUseReq) (string, bool) {
+	command, err := snippet.Render(s.Command, req.Params)
+	if err != nil {
+		msg := "매개변수 값이 올바르지 않습니다."
+		if errors.Is(err, snippet.ErrMissingParam) {
+			msg = "매개변수 값을 모두 입력하세요."
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg, "detail": err.Error()})
+		return "", false
+	}
+	return command, true
+}
+
+// HtmlSnippets [GET /snippets] 저장된 명령 페이지 렌더링
+func HtmlSnippets(c *gin.Context) {
+	c.HTML(http.StatusOK, "snippets.html", gin.H{
+		"User": middleware.CurrentUser(c),
+	})
+}
+
+// ListSnippets [GET /api/snippets] 본인 및 공유된 명령 목록 조회
+func ListSnippets(c *gin.Context) {
+	me := middleware.CurrentUser(c)
+
+	var list []db.Snippet
+	if err := db.SqliteDB.Where("user_id = ? OR shared = ?", me.ID, true).Order("name, id").Find(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "저장된 명령 조회 실패"})
+		logger.PTY.Errorw("Failed to query snippets", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	var users []db.User
+	db.SqliteDB.Select("id", "username").Find(&users)
+	usernames := make(map[uint]string, len(users))
+	for _, u := range users {
+		usernames[u.ID] = u.Username
+	}
+
+	views := make([]snippetView, 0, len(list))
+	for i := range list {
+		views = append(views, newSnippetView(&list[i], me, usernames[list[i].UserID]))
+	}
+	c.JSON(http.StatusOK, gin.H{"snippets": views})
+}
+
+// CreateSnippet [POST /api/snippets] 명령 저장
+func CreateSnippet(c *gin.Context) {
+	var req snippetReq
+	if err := c.ShouldBindJSON(&req); err != nil || req.Name == nil || req.Command == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	me := middleware.CurrentUser(c)
+	s := db.Snippet{UserID: me.ID, Name: *req.Name, Command: *req.Command}
+	if req.Description != nil {
+		s.Description = *req.Description
+	}
+	if req.Shared != nil {
+		s.Shared = *req.Shared
+	}
+	if !validateSnippet(c, &s) {
+		return
+	}
+
+	if err := db.SqliteDB.Create(&s).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "명령 저장 실패"})
+		logger.PTY.Errorw("Failed to create snippet", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.PTY.Infow("Snippet created", "id", s.ID, "name", s.Name, "shared", s.Shared, "by", me.Username, "ip", c.ClientIP())
+	c.JSON(http.StatusCreated, gin.H{"snippet": newSnippetView(&s, me, me.Username)})
+}
+
+// UpdateSnippet [PATCH /api/snippets/:id] 저장된 명령 수정
+func UpdateSnippet(c *gin.Context) {
+	var req snippetReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	me := middleware.CurrentUser(c)
+	s, ok := findSnippet(c, me)
+	if !ok {
+		return
+	}
+	if !canEditSnippet(s, me) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "작성자만 수정할 수 있습니다."})
+		return
+	}
+
+	if req.Name != nil {
+		s.Name = *req.Name
+	}
+	if req.Description != nil {
+		s.Description = *req.Description
+	}
+	if req.Command != nil {
+		s.Command = *req.Command
+	}
+	if req.Shared != nil {
+		s.Shared = *req.Shared
+	}
+	if !validateSnippet(c, s) {
+		return
+	}
+
+	err := db.SqliteDB.Model(s).Select("name", "description", "command", "shared").Updates(s).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "명령 수정 실패"})
+		logger.PTY.Errorw("Failed to update snippet", "id", s.ID, "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	var owner db.User
+	db.SqliteDB.Select("id", "username").First(&owner, s.UserID)
+
+	logger.PTY.Infow("Snippet updated", "id", s.ID, "name", s.Name, "shared", s.Shared, "by", me.Username, "ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"snippet": newSnippetView(s, me, owner.Username)})
+}
+
+// DeleteSnippet [DELETE /api/snippets/:id] 저장된 명령 삭제 (실행 기록은 유지)
+func DeleteSnippet(c *gin.Context) {
+	me := middleware.CurrentUser(c)
+	s, ok := findSnippet(c, me)
+	if !ok {
+		return
+	}
+	if !canEditSnippet(s, me) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "작성자만 삭제할 수 있습니다."})
+		return
+	}
+
+	if err := db.SqliteDB.Unscoped().Delete(s).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "명령 삭제 실패"})
+		logger.PTY.Errorw("Failed to delete snippet", "id", s.ID, "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.PTY.Infow("Snippet deleted", "id", s.ID, "name", s.Name, "by", me.Username, "ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// RenderSnippet [POST /api/snippets/:id/render] 매개변수 값을 넣은 명령 생성 (터미널 화면에서 입력할 명령)
+func RenderSnippet(c *gin.Context) {
+	var req snippetUseReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	s, ok := findSnippet(c, middleware.CurrentUser(c))
+	if !ok {
+		return
+	}
+	command, ok := renderSnippet(c, s, &req)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"command": command})
+}
+
+// SendSnippet [POST /api/snippets/:id/send] 열려 있는 본인 터미널 세션에 명령 입력
+func SendSnippet(c *gin.Context) {
+	var req snippetUseReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	me := middleware.CurrentUser(c)
+	s, ok := findSnippet(c, me)
+	if !ok {
+		return
+	}
+	if !checkSnippetCommand(c, s, &req) {
+		return
+	}
+	command, ok := renderSnippet(c, s, &req)
+	if !ok {
+		return
+	}
+
+	sess := terminal.Find(req.Session)
+	if sess == nil || sess.UserID != me.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "터미널 세션을 찾을 수 없습니다."})
+		return
+	}
+
+	data := command
+	if req.Execute {
+		data += "\r"
+	}
+	if err := sess.Paste([]byte(data), "Snippet "+s.Name+" sent by "+me.Username); err != nil {
+		msg := "명령 전송 실패"
+		switch {
+		case errors.Is(err, terminal.ErrSessionLocked):
+			msg = "잠긴 세션입니다. 재접속 후 다시 시도하세요."
+		case errors.Is(err, terminal.ErrUploadInProgress):
+			msg = "파일 업로드 중에는 명령을 보낼 수 없습니다."
+		}
+		c.JSON(http.StatusConflict, gin.H{"error": msg})
+		logger.PTY.Warnw("Failed to send snippet",
+			"id", s.ID, "session", sess.ID, "user", me.Username, "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.PTY.Infow("Snippet sent to terminal",
+		"id", s.ID, "name", s.Name, "session", sess.ID, "execute", req.Execute, "user", me.Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeSnippetSend, sess.ID, map[string]interface{}{
+		"snippet": s.ID,
+		"name":    s.Name,
+		"command": command,
+		"execute": req.Execute,
+	})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// RunSnippet [POST /api/snippets/:id/run] 명령을 비대화형으로 실행 (완료 결과는 실행 기록에서 조회)
+func RunSnippet(c *gin.Context) {
+	var req snippetUseReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	me := middleware.CurrentUser(c)
+	s, ok := findSnippet(c, me)
+	if !ok {
+		return
+	}
+	if !checkSnippetCommand(c, s, &req) {
+		return
+	}
+	command, ok := renderSnippet(c, s, &req)
+	if !ok {
+		return
+	}
+
+	run, err := snippet.Start(me, s, command, c.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, snippet.ErrTooManyRuns):
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "동시에 실행할 수 있는 명령 개수를 초과했습니다."})
+		case errors.Is(err, snippet.ErrShuttingDown):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "서버가 종료 중입니다."})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "명령을 실행할 수 없습니다. (" + err.Error() + ")"})
+		}
+		logger.PTY.Warnw("Refused to run snippet",
+			"id", s.ID, "user", me.Username, "unixUser", me.UnixUser, "ip", c.ClientIP(), "err", err)
+		return
+	}
+	recordAudit(c, audit.TypeSnippetRun, strconv.FormatUint(uint64(run.ID), 10), map[string]interface{}{
+		"snippet":  s.ID,
+		"name":     s.Name,
+		"command":  command,
+		"unixUser": run.UnixUser,
+	})
+	c.JSON(http.StatusAccepted, gin.H{"run": newSnippetRunView(run, false)})
+}
+
+// findSnippetRun URL 파라미터의 ID로 실행 기록 조회 (본인 기록, 관리자는 전체)
+func findSnippetRun(c *gin.Context, me *db.User) (*db.SnippetRun, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return nil, false
+	}
+
+	var run db.SnippetRun
+	if err := db.SqliteDB.First(&run, id).Error; err != nil || (run.UserID != me.ID && !me.IsAdmin()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "실행 기록을 찾을 수 없습니다."})
+		return nil, false
+	}
+	return &run, true
+}
+
+// ListSnippetRuns [GET /api/snippet-runs] 최근 실행 기록 조회 (본인 기록, 관리자는 전체)
+func ListSnippetRuns(c *gin.Context) {
+	me := middleware.CurrentUser(c)
+
+	query := db.SqliteDB.Omit("output").Order("id DESC").Limit(maxSnippetRunList)
+	if !me.IsAdmin() {
+		query = query.Where("user_id = ?", me.ID)
+	}
+	var runs []db.SnippetRun
+	if err := query.Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "실행 기록 조회 실패"})
+		logger.PTY.Errorw("Failed to query snippet runs", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	views := make([]snippetRunView, 0, len(runs))
+	for i := range runs {
+		views = append(views, newSnippetRunView(&runs[i], false))
+	}
+	c.JSON(http.StatusOK, gin.H{"runs": views})
+}
+
+// GetSnippetRun [GET /api/snippet-runs/:id] 실행 기록 및 출력 조회
+func GetSnippetRun(c *gin.Context) {
+	run, ok := findSnippetRun(c, middleware.CurrentUser(c))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"run": newSnippetRunView(run, true)})
+}
+
+// CancelSnippetRun [POST /api/snippet-runs/:id/cancel] 실행 중인 명령 취소
+func CancelSnippetRun(c *gin.Context) {
+	me := middleware.CurrentUser(c)
+	run, ok := findSnippetRun(c, me)
+	if !ok {
+		return
+	}
+	if !snippet.Cancel(run.ID) {
+		c.JSON(http.StatusConflict, gin.H{"error": "실행 중인 명령이 아닙니다."})
+		return
+	}
+
+	logger.PTY.Infow("Snippet run canceled", "run", run.ID, "owner", run.Username, "by", me.Username, "ip", c.ClientIP())
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
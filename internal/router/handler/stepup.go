@@ -0,0 +1,121 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
+)
+
+// stepUpNext 재인증 후 이동할 경로 (다른 사이트로 이동하지 않도록 내부 경로만 허용)
+// 브라우저는 URL의 탭/개행 문자를 제거하므로 "/\t/host" 같은 값도 "//host"로 해석됨
+func stepUpNext(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.Contains(next, "\\") {
+		return "/"
+	}
+	if strings.IndexFunc(next, func(r rune) bool { return unicode.IsControl(r) || unicode.IsSpace(r) }) >= 0 {
+		return "/"
+	}
+	u, err := url.Parse(next)
+	if err != nil || u.Scheme != "" || u.Host != "" || !strings.HasPrefix(u.Path, "/") || strings.HasPrefix(u.Path, "//") {
+		return "/"
+	}
+	return next
+}
+
+// HtmlStepUp [GET /account/verify] OTP 재인증 페이지 렌더링
+// force 쿼리가 있으면 최근에 인증했더라도 다시 인증 (잠긴 터미널 세션 해제 등)
+func HtmlStepUp(c *gin.Context) {
+	next := stepUpNext(c.Query("next"))
+	if c.Query("force") == "" && !middleware.StepUpRequired(c) {
+		c.Redirect(http.StatusFound, next)
+		return
+	}
+	renderStepUp(c, http.StatusOK, next, "")
+}
+
+// renderStepUp 재인증 페이지 렌더링 (OTP 미등록 사용자는 안내 메시지만 표시)
+func renderStepUp(c *gin.Context, status int, next, errMsg string) {
+	user := middleware.CurrentUser(c)
+	c.HTML(status, "stepup.html", gin.H{
+		"User":     user,
+		"Enrolled": user.OTPSecret != "",
+		"Next":     next,
+		"Error":    errMsg,
+	})
+}
+
+// VerifyStepUp [POST /account/verify] OTP 번호 또는 복구 코드로 재인증 후 원래 페이지로 이동
+func VerifyStepUp(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+	next := stepUpNext(c.PostForm("next"))
+
+	if user.OTPSecret == "" {
+		renderStepUp(c, http.StatusBadRequest, next, "")
+		return
+	}
+
+	// 로그인과 동일하게 연속 실패 시 시도 제한
+	if wait := checkLoginThrottle(user.Username, c.ClientIP()); wait > 0 {
+		auditLoginFailure(c, user.Username, failThrottled)
+		setRetryAfter(c, wait)
+		renderStepUp(c, http.StatusTooManyRequests, next, throttleMessage(wait))
+		return
+	}
+
+	ok, recovered := verifySecondFactor(user, c.PostForm("otp_token"))
+	if !ok {
+		recordLoginFailure(c, user.Username, failOTP, true)
+		renderStepUp(c, http.StatusUnauthorized, next, "OTP 인증 번호가 일치하지 않습니다.")
+		return
+	}
+	resetLoginThrottle(user.Username)
+
+	sess := sessions.Default(c)
+	sess.Set("otp_verified_at", time.Now().Unix())
+	if err := sess.Save(); err != nil {
+		renderStepUp(c, http.StatusInternalServerError, next, "세션 저장 실패")
+		logger.Auth.Errorw("Failed to save session info", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	if recovered {
+		logger.Auth.Warnw("Recovery code used", "user", user.Username, "ip", c.ClientIP())
+	}
+	logger.Auth.Infow("Step-up verification succeeded", "user", user.Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeStepUp, sessionstore.Handle(sess.ID()), map[string]interface{}{"recoveryCode": recovered})
+	c.Redirect(http.StatusFound, next)
+}
+
+// StepUpStatus [GET /api/account/step-up] 재인증 필요 여부 및 만료 시각 조회
+func StepUpStatus(c *gin.Context) {
+	resp := gin.H{"required": middleware.StepUpRequired(c)}
+	if expiresAt := middleware.StepUpExpiresAt(c); !expiresAt.IsZero() {
+		resp["expiresAt"] = expiresAt
+	}
+	c.JSON(http.StatusOK, resp)
+}
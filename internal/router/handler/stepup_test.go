@@ -0,0 +1,49 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import "testing"
+
+func TestStepUpNext(t *testing.T) {
+	tests := []struct {
+		next string
+		want string
+	}{
+		{"", "/"},
+		{"/", "/"},
+		{"/terminal", "/terminal"},
+		{"/terminal?sid=abc&x=1", "/terminal?sid=abc&x=1"},
+		{"/files#top", "/files#top"},
+		{"terminal", "/"},
+		{"https://evil.com/", "/"},
+		{"//evil.com", "/"},
+		{"/\\evil.com", "/"},
+		{"/\\\\x", "/"},
+		{"/a\\b", "/"},
+		{"/\t/x", "/"},
+		{"/\n/x", "/"},
+		{"/\r/x", "/"},
+		{"/ /x", "/"},
+		{"/\x00/x", "/"},
+		{"/%2F/evil.com", "/"},
+	}
+	for _, tt := range tests {
+		if got := stepUpNext(tt.next); got != tt.want {
+			t.Errorf("stepUpNext(%q) = %q, want %q", tt.next, got, tt.want)
+		}
+	}
+}
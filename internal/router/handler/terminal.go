@@ -0,0 +1,785 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/metrics"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
+	"github.com/hoon-x/rootweb/internal/terminal"
+	"gorm.io/gorm"
+)
+
+// 웹소켓 송신 대기열 크기 (초과 시 느린 클라이언트로 판단하여 연결 해제)
+const wsSendQueueSize = 256
+
+// 로그인 세션 종료로 인한 연결 해제 사유
+const (
+	reasonSignedOut      = "signed_out"      // 로그아웃 또는 로그인 세션 강제 종료
+	reasonSessionExpired = "session_expired" // 로그인 세션 유지 시간 만료
+	reasonStepUp         = "step_up"         // OTP 재인증 필요 (다중 채널 연결에서 새 세션을 열 때 확인)
+	reasonProtocol       = "protocol"        // 지원하지 않는 프로토콜 버전
+)
+
+// 터미널 웹소켓을 연 로그인 세션의 만료 확인 주기
+const wsSessionCheckInterval = 30 * time.Second
+
+// 로그인 세션 만료 경고 시점 (만료까지 남은 시간)
+const wsSessionWarnBefore = 2 * time.Minute
+
+// 세션 종료 사유별 웹소켓 close 코드 (클라이언트의 재접속 여부 판단에 사용)
+var wsCloseCodes = map[string]int{
+	terminal.ReasonExited:      websocket.CloseNormalClosure,
+	terminal.ReasonShutdown:    websocket.CloseGoingAway,
+	terminal.ReasonTakeover:    4001,
+	terminal.ReasonTerminated:  4002,
+	terminal.ReasonSlowClient:  4003,
+	terminal.ReasonKicked:      4004,
+	reasonSignedOut:            4005,
+	reasonSessionExpired:       4006,
+	terminal.ReasonLocked:      4007,
+	terminal.ReasonIdle:        4008,
+	terminal.ReasonMaxDuration: 4009,
+	reasonStepUp:               4010,
+	reasonProtocol:             4011,
+}
+
+// 로그인 세션별로 열려 있는 터미널 웹소켓 (로그인 세션 종료 시 함께 연결 해제)
+var wsClients = struct {
+	sync.Mutex
+	bySession map[string]map[*wsClient]struct{}
+}{bySession: map[string]map[*wsClient]struct{}{}}
+
+type wsMsg struct {
+	MsgType string `json:"type"`
+	Channel uint16 `json:"ch,omitempty"`
+	Session string `json:"session,omitempty"`
+	ID      string `json:"id,omitempty"`
+	Mode    string `json:"mode,omitempty"`
+	Cols    int    `json:"cols,omitempty"`
+	Rows    int    `json:"rows,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Data    string `json:"data,omitempty"`
+	Signal  string `json:"signal,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Seq     int64  `json:"seq,omitempty"`
+}
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  8192,
+	WriteBufferSize: 8192,
+	// CSRFProtect 미들웨어에서도 검증하지만 업그레이드 시 한 번 더 확인
+	CheckOrigin: middleware.OriginAllowed,
+}
+
+// wsFrame 송신 대기열 항목
+type wsFrame struct {
+	msgType int
+	data    []byte
+	output  int // 포함된 터미널 출력 크기 (채널 번호 제외, 메트릭 집계용)
+}
+
+// wsClient 터미널 세션에 연결된 웹소켓 클라이언트
+// PTY 읽기가 네트워크 지연으로 막히지 않도록 송신은 별도 고루틴에서 처리
+type wsClient struct {
+	conn      *websocket.Conn
+	ip        string
+	readLimit int64 // 수신 메시지 최대 크기
+
+	mu       sync.Mutex
+	sendCh   chan wsFrame
+	closed   bool
+	code     int
+	done     chan struct{}
+	channels map[uint16]*wsChannel // 다중 채널 연결의 채널 목록 (단일 세션 연결이면 nil)
+	warned   bool                  // 로그인 세션 만료 경고 전송 여부 (warnWSClients 참고)
+
+	version int // 클라이언트가 hello로 알린 프로토콜 버전 (읽기 고루틴에서만 사용)
+
+	// 공동 입력이 가능한 참여 연결의 OTP 재인증 만료 시각 (zero이면 확인하지 않음, 읽기 고루틴에서만 사용)
+	stepUpExpiresAt time.Time
+}
+
+// newWSClient 웹소켓 클라이언트 생성 및 송신 고루틴 시작
+func newWSClient(conn *websocket.Conn, ip string) *wsClient {
+	cl := &wsClient{
+		conn:      conn,
+		ip:        ip,
+		readLimit: wsReadLimit(),
+		sendCh:    make(chan wsFrame, wsSendQueueSize),
+		code:      websocket.CloseNormalClosure,
+		done:      make(chan struct{}),
+	}
+	// 최대 크기를 넘는 메시지는 readMessage에서 버리며 오류로 알림
+	// 그보다 훨씬 큰 메시지는 읽지 않고 연결 종료 (1009)
+	conn.SetReadLimit(2 * cl.readLimit)
+	go cl.writeLoop()
+	return cl
+}
+
+// Send 터미널 출력 전송 (대기열이 가득 차면 false)
+func (cl *wsClient) Send(data []byte) bool {
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	return cl.enqueue(wsFrame{msgType: websocket.BinaryMessage, data: buf, output: len(data)})
+}
+
+// SendJSON 제어 메시지 전송
+func (cl *wsClient) SendJSON(v any) bool {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+	return cl.enqueue(wsFrame{msgType: websocket.TextMessage, data: data})
+}
+
+// Notify 세션 제어 이벤트 전송
+func (cl *wsClient) Notify(evt terminal.Event) bool {
+	return cl.SendJSON(evt)
+}
+
+// enqueue 송신 대기열에 추가 (블로킹하지 않음)
+func (cl *wsClient) enqueue(f wsFrame) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.closed {
+		return true
+	}
+	select {
+	case cl.sendCh <- f:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close 대기 중인 출력 전송 후 사유에 해당하는 close 코드로 연결 종료
+func (cl *wsClient) Close(reason string) {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+
+	if cl.closed {
+		return
+	}
+	cl.closed = true
+	if code, ok := wsCloseCodes[reason]; ok {
+		cl.code = code
+	}
+	close(cl.sendCh)
+}
+
+// writeLoop 송신 대기열의 메시지를 웹소켓으로 전송
+func (cl *wsClient) writeLoop() {
+	defer close(cl.done)
+	defer cl.conn.Close()
+
+	failed := false
+	for f := range cl.sendCh {
+		if failed {
+			continue
+		}
+		// 웹소켓 쓰기 타임아웃 설정 (네트워크 지연 시 무한 대기 방지)
+		cl.conn.SetWriteDeadline(time.Now().Add(5 * time.Second))
+		if err := cl.conn.WriteMessage(f.msgType, f.data); err != nil {
+			logger.PTY.Warnw("Failed to write message", "ip", cl.ip, "err", err)
+			metrics.WebSocketWriteFailed()
+			// 읽기 루프가 종료되도록 연결을 닫고 남은 대기열은 버림
+			cl.conn.Close()
+			failed = true
+		} else if f.output > 0 {
+			metrics.TerminalOutput(f.output)
+		}
+	}
+
+	if !failed {
+		cl.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		cl.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(cl.code, ""))
+	}
+}
+
+// trackWSClient 로그인 세션의 웹소켓 목록에 등록 (반환된 함수로 등록 해제)
+func trackWSClient(c *gin.Context, cl *wsClient) func() {
+	id := sessions.Default(c).ID()
+
+	wsClients.Lock()
+	defer wsClients.Unlock()
+	if wsClients.bySession[id] == nil {
+		wsClients.bySession[id] = map[*wsClient]struct{}{}
+	}
+	wsClients.bySession[id][cl] = struct{}{}
+
+	return func() {
+		wsClients.Lock()
+		defer wsClients.Unlock()
+		delete(wsClients.bySession[id], cl)
+		if len(wsClients.bySession[id]) == 0 {
+			delete(wsClients.bySession, id)
+		}
+	}
+}
+
+// closeWSClients 로그인 세션에 열려 있는 웹소켓 연결 해제 (터미널 세션은 분리 상태로 유지)
+func closeWSClients(sessionIDs ...string) int {
+	return disconnectWSClients(reasonSignedOut, "signed out", sessionIDs...)
+}
+
+// disconnectWSClients 로그인 세션에 열려 있는 웹소켓에 안내 문구를 출력하고 사유에 해당하는 코드로 연결 해제
+func disconnectWSClients(reason, notice string, sessionIDs ...string) int {
+	wsClients.Lock()
+	defer wsClients.Unlock()
+
+	n := 0
+	for _, id := range sessionIDs {
+		for cl := range wsClients.bySession[id] {
+			cl.notice(notice)
+			cl.Close(reason)
+			n++
+		}
+	}
+	return n
+}
+
+// WatchLoginSessions 유지 시간이 만료된 로그인 세션의 터미널 웹소켓을 주기적으로 연결 해제
+// HTTP 요청이 없어도 웹소켓은 계속 열려 있으므로 세션 만료 시점을 별도로 확인
+func WatchLoginSessions(ctx context.Context) {
+	ticker := time.NewTicker(wsSessionCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		wsClients.Lock()
+		ids := make([]string, 0, len(wsClients.bySession))
+		for id := range wsClients.bySession {
+			ids = append(ids, id)
+		}
+		wsClients.Unlock()
+		if len(ids) == 0 {
+			continue
+		}
+
+		expiresAt, err := sessionstore.ExpiresAt(ids)
+		if err != nil {
+			logger.PTY.Errorw("Failed to check login sessions of terminal connections", "err", err)
+			continue
+		}
+
+		var expired []string
+		for _, id := range ids {
+			exp, ok := expiresAt[id]
+			if !ok {
+				expired = append(expired, id)
+				continue
+			}
+			warnWSClients(id, time.Until(exp))
+		}
+		if n := disconnectWSClients(reasonSessionExpired, "login session expired", expired...); n > 0 {
+			logger.PTY.Infow("Disconnected terminal connections of expired login sessions",
+				"sessions", len(expired), "connections", n)
+		}
+	}
+}
+
+// warnWSClients 로그인 세션 만료가 가까워지면 웹소켓에 한 번 경고 (세션이 연장되면 다시 경고 가능)
+// warned는 이 함수에서만 사용하며 wsClients 잠금으로 보호
+func warnWSClients(sessionID string, remain time.Duration) {
+	wsClients.Lock()
+	defer wsClients.Unlock()
+
+	for cl := range wsClients.bySession[sessionID] {
+		if remain > wsSessionWarnBefore {
+			cl.warned = false
+			continue
+		}
+		if !cl.warned {
+			cl.warned = true
+			cl.SendJSON(terminal.Event{
+				Type:    terminal.EventWarning,
+				Reason:  reasonSessionExpired,
+				Seconds: int(remain.Round(time.Second).Seconds()),
+			})
+		}
+	}
+}
+
+// countWSClients 로그인 세션에 열려 있는 웹소켓 개수
+func countWSClients(sessionID string) int {
+	wsClients.Lock()
+	defer wsClients.Unlock()
+	return len(wsClients.bySession[sessionID])
+}
+
+// failWith 오류 메시지를 터미널에 출력하고 사유에 해당하는 close 코드로 연결 종료
+func (cl *wsClient) failWith(reason, msg string) {
+	cl.Send([]byte("\r\n[RootWeb] " + msg + "\r\n"))
+	cl.Close(reason)
+	<-cl.done
+}
+
+// keepAliveMillis 터미널 페이지의 세션 유지 요청(/ping) 간격 (단위:밀리초)
+func keepAliveMillis() int64 {
+	return middleware.LoadSessionPolicy().KeepAliveInterval().Milliseconds()
+}
+
+// HtmlTerminal [GET /terminal] 터미널 페이지 렌더링
+func HtmlTerminal(c *gin.Context) {
+	c.HTML(http.StatusOK, "terminal.html", gin.H{
+		"KeepAlive":       keepAliveMillis(),
+		"TransferMaxSize": terminal.TransferMaxSize(),
+	})
+}
+
+// TerminalWS [GET /terminal/ws] 웹소켓 연결을 터미널 세션에 연결
+// session 쿼리가 있으면 연결이 끊긴 기존 세션에 재접속, 없으면 새 세션 생성
+func TerminalWS(c *gin.Context) {
+	// HTTP 연결을 웹소켓 프로토콜로 업그레이드 (Handshake)
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.PTY.Errorw("Failed to upgrade web socket",
+			"ip", c.ClientIP(), "path", c.Request.URL.Path, "origin", c.GetHeader("Origin"), "userAgent", c.Request.UserAgent(), "err", err)
+		return
+	}
+	cl := newWSClient(conn, c.ClientIP())
+	defer trackWSClient(c, cl)()
+
+	cl.sendHello(c.Query("mux") == "1", true)
+	if c.Query("mux") == "1" {
+		serveMux(c, cl, func(ch *wsChannel, r wsMsg) (*terminal.Session, string, string) {
+			// 연결 이후 재인증 유효 시간이 지났으면 새 세션을 열거나 재접속하지 않음
+			if middleware.StepUpRequired(c) {
+				return nil, reasonStepUp, "OTP verification required"
+			}
+			ch.owner = true
+			return attachOwnSession(c, ch, r.Session, r.Cols, r.Rows)
+		}, true)
+		return
+	}
+
+	sess, reason, msg := attachOwnSession(c, cl, c.Query("session"), 0, 0)
+	if sess == nil {
+		cl.failWith(reason, msg)
+		return
+	}
+	serveTerminal(c, cl, sess)
+}
+
+// attachOwnSession 로그인 사용자의 세션에 소유자로 연결 (id가 비어 있으면 새 세션 생성)
+// 실패하면 연결 종료 사유와 안내 문구 반환
+func attachOwnSession(c *gin.Context, cl terminal.Client, id string, cols, rows int) (*terminal.Session, string, string) {
+	user := middleware.CurrentUser(c)
+	var sess *terminal.Session
+	if id != "" {
+		// 본인 세션에만 재접속 허용
+		sess = terminal.Find(id)
+		if sess == nil || sess.UserID != user.ID {
+			return nil, terminal.ReasonTerminated, "session not found"
+		}
+		// 입력 없음으로 잠긴 세션은 잠긴 이후 OTP를 다시 인증한 경우에만 재접속 허용
+		if lockedAt := sess.LockedAt(); !lockedAt.IsZero() {
+			if !middleware.OTPVerifiedAt(c).After(lockedAt) {
+				return nil, terminal.ReasonLocked, terminal.ErrSessionLocked.Error()
+			}
+			sess.Unlock(c.ClientIP())
+		}
+	} else {
+		if cols <= 0 || rows <= 0 {
+			cols, rows = 120, 30
+		}
+		var err error
+		sess, err = terminal.NewSession(user, c.ClientIP(), cols, rows)
+		if err != nil {
+			logger.PTY.Warnw("Refused to start shell",
+				"user", user.Username, "unixUser", user.UnixUser, "ip", c.ClientIP(), "err", err)
+			return nil, terminal.ReasonTerminated, err.Error()
+		}
+		recordAudit(c, audit.TypeTerminalOpen, sess.ID, map[string]interface{}{
+			"unixUser": sess.UnixUser,
+			"cols":     cols,
+			"rows":     rows,
+		})
+	}
+
+	// 재접속에 사용할 세션 ID 알림 (스크롤백 재전송보다 먼저 전송)
+	cl.Notify(terminal.Event{Type: "session", ID: sess.ID, Mode: terminal.ModeOwner})
+	if !sess.Attach(cl) {
+		return nil, terminal.ReasonTerminated, "session has been closed"
+	}
+	logger.PTY.Infow("Terminal session attached", "id", sess.ID, "user", user.Username, "ip", c.ClientIP())
+	if id != "" {
+		recordAudit(c, audit.TypeTerminalAttach, sess.ID, nil)
+	}
+	return sess, "", ""
+}
+
+// HtmlJoinTerminal [GET /terminal/join/:token] 공유 터미널 참여 페이지 렌더링
+func HtmlJoinTerminal(c *gin.Context) {
+	if terminal.FindByInvite(c.Param("token")) == nil {
+		c.String(http.StatusNotFound, "초대 링크가 유효하지 않거나 만료되었습니다.")
+		return
+	}
+	c.HTML(http.StatusOK, "terminal.html", gin.H{
+		"Invite":    c.Param("token"),
+		"KeepAlive": keepAliveMillis(),
+	})
+}
+
+// JoinTerminalWS [GET /terminal/join/:token/ws] 초대 토큰으로 공유 터미널에 읽기 전용 참여
+func JoinTerminalWS(c *gin.Context) {
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.PTY.Errorw("Failed to upgrade web socket", "ip", c.ClientIP(), "path", c.Request.URL.Path, "err", err)
+		return
+	}
+	cl := newWSClient(conn, c.ClientIP())
+	defer trackWSClient(c, cl)()
+
+	// 참여 연결의 입력은 재인증 유효 시간 동안만 허용 (입력 프레임마다 checkStepUp으로 확인)
+	if !middleware.StepUpRequired(c) {
+		cl.stepUpExpiresAt = middleware.StepUpExpiresAt(c)
+	}
+
+	cl.sendHello(c.Query("mux") == "1", false)
+	if c.Query("mux") == "1" {
+		serveMux(c, cl, func(ch *wsChannel, r wsMsg) (*terminal.Session, string, string) {
+			return joinSession(c, ch, c.Param("token"))
+		}, false)
+		return
+	}
+
+	sess, reason, msg := joinSession(c, cl, c.Param("token"))
+	if sess == nil {
+		cl.failWith(reason, msg)
+		return
+	}
+	serveTerminal(c, cl, sess)
+}
+
+// checkStepUp 참여 연결의 OTP 재인증 유효 시간 확인 (입력 프레임마다 호출)
+// 유효 시간이 지나면 저장된 로그인 세션을 다시 읽어 다른 창에서 마친 재인증을 반영하고,
+// 그래도 만료 상태이면 false 반환 (이후 입력 권한은 재인증 후 다시 참여해야 얻을 수 있으므로 더 확인하지 않음)
+func (cl *wsClient) checkStepUp(c *gin.Context) bool {
+	if cl.stepUpExpiresAt.IsZero() || time.Now().Before(cl.stepUpExpiresAt) {
+		return true
+	}
+
+	expiresAt, err := middleware.StoredStepUpExpiresAt(c)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		logger.PTY.Errorw("Failed to check step-up verification", "ip", c.ClientIP(), "err", err)
+	}
+	if err == nil && time.Now().Before(expiresAt) {
+		cl.stepUpExpiresAt = expiresAt
+		return true
+	}
+	cl.stepUpExpiresAt = time.Time{}
+	return false
+}
+
+// revokeStepUpWrite 재인증이 만료된 참여자의 입력 권한을 회수하고, 입력 중이었으면 step_up 오류로 알림
+func revokeStepUpWrite(c *gin.Context, cl *wsClient, ch uint16, sess *terminal.Session, client terminal.Client) {
+	if !sess.RevokeWrite(client) {
+		return
+	}
+	logger.PTY.Infow("Terminal input revoked, step-up verification expired",
+		"id", sess.ID, "user", middleware.CurrentUser(c).Username, "ip", c.ClientIP())
+	cl.sendError(ch, "", errStepUpRequired)
+}
+
+// joinSession 초대 토큰으로 세션에 읽기 전용 참여자로 연결
+// 실패하면 연결 종료 사유와 안내 문구 반환
+func joinSession(c *gin.Context, cl terminal.Client, token string) (*terminal.Session, string, string) {
+	user := middleware.CurrentUser(c)
+	sess := terminal.FindByInvite(token)
+	if sess == nil {
+		return nil, terminal.ReasonTerminated, terminal.ErrInvalidInvite.Error()
+	}
+
+	// 입력 권한은 터미널 사용 권한(operator 이상)이 있고 OTP 재인증을 마친 계정에만 승인 가능
+	// (터미널 열기와 같은 기준, 재인증 전에 참여했다면 재인증 후 다시 참여해야 함)
+	// 참여 후 재인증 유효 시간이 지나면 입력 시 checkStepUp에서 확인하여 입력 권한 회수
+	canWrite := user.HasRole(db.RoleOperator) && !middleware.StepUpRequired(c)
+	cl.Notify(terminal.Event{Type: "session", Mode: terminal.ModeRead})
+	if err := sess.Join(cl, user, token, canWrite); err != nil {
+		return nil, terminal.ReasonTerminated, err.Error()
+	}
+	logger.PTY.Infow("Terminal session joined",
+		"id", sess.ID, "owner", sess.Username, "user", user.Username, "canWrite", canWrite, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeTerminalJoin, sess.ID, map[string]interface{}{"owner": sess.Username, "canWrite": canWrite})
+	return sess, "", ""
+}
+
+// serveTerminal 클라이언트 입력을 세션으로 전달 (연결이 끊기면 세션은 유지한 채 분리)
+func serveTerminal(c *gin.Context, cl *wsClient, sess *terminal.Session) {
+	for {
+		msgType, msg, err := cl.readMessage()
+		if err != nil {
+			break
+		}
+
+		if msgType == websocket.BinaryMessage {
+			// 순수 터미널 입력 데이터 (읽기 전용 참여자의 입력은 무시)
+			if len(msg) > 0 && !cl.checkStepUp(c) {
+				revokeStepUpWrite(c, cl, 0, sess, cl)
+			}
+			if len(msg) > 0 {
+				if err := sess.Write(cl, msg); err == nil {
+					metrics.TerminalInput(len(msg))
+				} else if !errors.Is(err, terminal.ErrReadOnly) {
+					break
+				}
+			}
+		} else if msgType == websocket.TextMessage {
+			// 터미널 제어용 메시지 (JSON 형식, 예: 리사이즈, 참여자 관리)
+			var r wsMsg
+			if err := json.Unmarshal(msg, &r); err != nil {
+				logger.PTY.Warnw("Failed to Unmarshal", "ip", c.ClientIP(), "err", err)
+				cl.SendJSON(wsControl{Type: msgError, Error: errCodeBadRequest, Message: "invalid JSON"})
+				continue
+			}
+			if handleConnMsg(c, cl, r) {
+				continue
+			}
+
+			if err := handleTerminalMsg(c, sess, cl, r); err != nil {
+				logger.PTY.Warnw("Failed to handle terminal message",
+					"type", r.MsgType, "id", sess.ID, "ip", c.ClientIP(), "err", err)
+				cl.sendError(0, r.MsgType, err)
+			}
+		}
+	}
+
+	sess.Detach(cl)
+	cl.Close("")
+	<-cl.done
+}
+
+// 터미널 크기 변경 감사 기록 대기 시간 (창 크기를 조절하는 동안의 연속 변경은 마지막 크기만 기록)
+const resizeAuditDelay = 2 * time.Second
+
+// pendingResize 기록 대기 중인 터미널 크기 변경
+type pendingResize struct {
+	timer *time.Timer
+	event audit.Event
+}
+
+// 터미널 세션별 기록 대기 중인 크기 변경
+var resizeAudits = struct {
+	sync.Mutex
+	pending map[string]*pendingResize
+}{pending: make(map[string]*pendingResize)}
+
+// auditResize 터미널 크기 변경을 감사 로그에 기록 (세션별로 resizeAuditDelay 동안 변경이 없을 때 기록)
+func auditResize(c *gin.Context, sessID string, cols, rows int) {
+	e := audit.Event{
+		Type:     audit.TypeTerminalResize,
+		ClientIP: c.ClientIP(),
+		Target:   sessID,
+		Details:  map[string]interface{}{"cols": cols, "rows": rows},
+	}
+	if user := middleware.CurrentUser(c); user != nil {
+		e.UserID, e.Username = user.ID, user.Username
+	}
+
+	resizeAudits.Lock()
+	defer resizeAudits.Unlock()
+	// 아직 기록되지 않았으면 마지막 크기로 바꾸고 대기 시간 연장
+	if p, ok := resizeAudits.pending[sessID]; ok && p.timer.Stop() {
+		p.event = e
+		p.timer.Reset(resizeAuditDelay)
+		return
+	}
+	p := &pendingResize{event: e}
+	p.timer = time.AfterFunc(resizeAuditDelay, func() {
+		resizeAudits.Lock()
+		if resizeAudits.pending[sessID] == p {
+			delete(resizeAudits.pending, sessID)
+		}
+		e := p.event
+		resizeAudits.Unlock()
+		audit.Record(e)
+	})
+	resizeAudits.pending[sessID] = p
+}
+
+// handleTerminalMsg 세션 제어 메시지 처리 (리사이즈, 참여자 관리, 파일 전송, 시그널)
+func handleTerminalMsg(c *gin.Context, sess *terminal.Session, cl terminal.Client, r wsMsg) error {
+	switch r.MsgType {
+	case "resize":
+		// PTY 크기가 실제로 바뀐 경우만 기록
+		cols, rows, changed, err := sess.Resize(cl, r.Cols, r.Rows)
+		if changed {
+			logger.PTY.Infow("Terminal resized",
+				"id", sess.ID, "cols", cols, "rows", rows, "user", middleware.CurrentUser(c).Username, "ip", c.ClientIP())
+			auditResize(c, sess.ID, cols, rows)
+		}
+		return err
+	case "request_write":
+		return sess.RequestWrite(cl)
+	case "grant":
+		return sess.SetMode(cl, r.ID, terminal.ModeWrite)
+	case "revoke":
+		return sess.SetMode(cl, r.ID, terminal.ModeRead)
+	case "kick":
+		return sess.Kick(cl, r.ID)
+	case "upload":
+		return sess.Upload(cl, r.Name, r.Data)
+	case "upload_cancel":
+		return sess.CancelUpload(cl)
+	case "signal":
+		return sess.Signal(cl, r.Signal)
+	}
+	return errUnknownType
+}
+
+// termSessionView 터미널 세션 목록 응답 항목
+type termSessionView struct {
+	ID         string     `json:"id"`
+	Username   string     `json:"username"`
+	UnixUser   string     `json:"unixUser"`
+	ClientIP   string     `json:"clientIp"`
+	StartedAt  time.Time  `json:"startedAt"`
+	Attached   bool       `json:"attached"`
+	Locked     bool       `json:"locked"`
+	DetachedAt *time.Time `json:"detachedAt,omitempty"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	Own        bool       `json:"own"`
+	Viewers    int        `json:"viewers"`
+}
+
+// listTermSessions 현재 사용자가 볼 수 있는 세션 목록 (관리자는 전체)
+func listTermSessions(c *gin.Context) []termSessionView {
+	user := middleware.CurrentUser(c)
+
+	list := terminal.ListByUser(user.ID)
+	if user.IsAdmin() {
+		list = terminal.List()
+	}
+
+	views := make([]termSessionView, 0, len(list))
+	for _, s := range list {
+		v := termSessionView{
+			ID:        s.ID,
+			Username:  s.Username,
+			UnixUser:  s.UnixUser,
+			ClientIP:  s.ClientIP,
+			StartedAt: s.StartedAt,
+			Own:       s.UserID == user.ID,
+			Viewers:   s.Participants(),
+			Locked:    !s.LockedAt().IsZero(),
+		}
+		if detachedAt := s.DetachedAt(); detachedAt.IsZero() {
+			v.Attached = true
+		} else {
+			expiresAt := detachedAt.Add(terminal.DetachTimeout())
+			v.DetachedAt = &detachedAt
+			v.ExpiresAt = &expiresAt
+		}
+		views = append(views, v)
+	}
+	return views
+}
+
+// HtmlTerminalSessions [GET /terminal/sessions] 터미널 세션 목록 페이지 렌더링
+func HtmlTerminalSessions(c *gin.Context) {
+	c.HTML(http.StatusOK, "sessions.html", gin.H{
+		"Sessions": listTermSessions(c),
+		"IsAdmin":  middleware.CurrentUser(c).IsAdmin(),
+	})
+}
+
+// ListTerminalSessions [GET /api/terminal/sessions] 터미널 세션 목록 조회
+func ListTerminalSessions(c *gin.Context) {
+	c.JSON(http.StatusOK, listTermSessions(c))
+}
+
+// findOwnTermSession URL 파라미터의 ID로 본인 세션 조회
+func findOwnTermSession(c *gin.Context) (*terminal.Session, bool) {
+	sess := terminal.Find(c.Param("id"))
+	if sess == nil || sess.UserID != middleware.CurrentUser(c).ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "세션을 찾을 수 없습니다."})
+		return nil, false
+	}
+	return sess, true
+}
+
+// CreateTerminalInvite [POST /api/terminal/sessions/:id/invites] 세션 공유 초대 링크 생성 (소유자 전용)
+func CreateTerminalInvite(c *gin.Context) {
+	sess, ok := findOwnTermSession(c)
+	if !ok {
+		return
+	}
+
+	token, expiresAt, err := sess.CreateInvite()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "초대 링크 생성 실패"})
+		logger.PTY.Errorw("Failed to create invite", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.PTY.Infow("Terminal session invite created", "id", sess.ID, "user", sess.Username, "ip", c.ClientIP())
+	c.JSON(http.StatusCreated, gin.H{
+		"url":       "/terminal/join/" + token,
+		"expiresAt": expiresAt,
+	})
+}
+
+// RevokeTerminalInvites [DELETE /api/terminal/sessions/:id/invites] 발급된 초대 링크 전체 폐기 (소유자 전용)
+func RevokeTerminalInvites(c *gin.Context) {
+	sess, ok := findOwnTermSession(c)
+	if !ok {
+		return
+	}
+
+	sess.RevokeInvites()
+	logger.PTY.Infow("Terminal session invites revoked", "id", sess.ID, "user", sess.Username, "ip", c.ClientIP())
+	c.Status(http.StatusNoContent)
+}
+
+// TerminateTerminalSession [DELETE /api/terminal/sessions/:id] 터미널 세션 강제 종료 (본인 또는 관리자)
+func TerminateTerminalSession(c *gin.Context) {
+	user := middleware.CurrentUser(c)
+
+	sess := terminal.Find(c.Param("id"))
+	if sess == nil || (sess.UserID != user.ID && !user.IsAdmin()) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "세션을 찾을 수 없습니다."})
+		return
+	}
+
+	sess.Terminate(terminal.ReasonTerminated)
+	logger.PTY.Infow("Terminal session terminated",
+		"id", sess.ID, "owner", sess.Username, "by", user.Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeTerminalTerminate, sess.ID,
+		map[string]interface{}{"owner": sess.Username, "action": "terminate"})
+	c.Status(http.StatusNoContent)
+}
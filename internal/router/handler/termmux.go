@@ -0,0 +1,314 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/metrics"
+	"github.com/hoon-x/rootweb/internal/terminal"
+)
+
+// 다중 채널 프로토콜 (웹소켓 연결 시 mux=1 쿼리 지정)
+// 하나의 웹소켓 연결로 여러 터미널 세션(PTY)을 채널 단위로 열고, 크기를 조정하고, 닫을 수 있음
+//   - 바이너리 프레임: 채널 번호(2바이트, big endian) + 터미널 입출력 데이터
+//   - 텍스트 프레임: ch 필드로 채널을 지정한 JSON 제어 메시지
+//     클라이언트 -> 서버: open(채널 생성, session 지정 시 재접속), detach(세션 유지), close(쉘 종료),
+//     restart(같은 채널에서 새 쉘 시작) 및 세션 제어 메시지
+//     서버 -> 클라이언트: session, closed(채널 종료, reason/code) 및 세션 제어 이벤트
+const (
+	muxHeaderSize  = 2  // 바이너리 프레임의 채널 번호 크기
+	maxMuxChannels = 16 // 연결당 최대 채널 개수
+)
+
+// 채널 제어 메시지 종류
+const (
+	muxOpen    = "open"    // 채널 생성 (새 세션 또는 기존 세션 재접속)
+	muxDetach  = "detach"  // 채널 닫기 (세션은 분리 상태로 유지)
+	muxClose   = "close"   // 채널 닫기 및 쉘 종료 (참여자는 detach와 동일)
+	muxRestart = "restart" // 같은 채널에서 새 쉘 시작 (실행 중인 쉘은 종료, 소유자 연결만 가능)
+	muxClosed  = "closed"  // 서버가 채널을 닫음 (Reason: 종료 사유, Code: 단일 세션 연결의 close 코드와 동일)
+)
+
+// muxEvent 채널 번호를 붙인 제어 이벤트
+type muxEvent struct {
+	Channel uint16 `json:"ch"`
+	Code    int    `json:"code,omitempty"`
+	terminal.Event
+}
+
+// muxOpener 채널에 세션을 연결하는 함수 (실패하면 종료 사유와 안내 문구 반환)
+type muxOpener func(ch *wsChannel, r wsMsg) (*terminal.Session, string, string)
+
+// wsChannel 다중 채널 연결의 채널 (채널마다 하나의 터미널 세션에 연결)
+type wsChannel struct {
+	cl    *wsClient
+	id    uint16
+	sess  *terminal.Session
+	owner bool
+}
+
+// Send 채널 번호를 붙여 터미널 출력 전송
+func (ch *wsChannel) Send(data []byte) bool {
+	buf := make([]byte, muxHeaderSize+len(data))
+	binary.BigEndian.PutUint16(buf, ch.id)
+	copy(buf[muxHeaderSize:], data)
+	return ch.cl.enqueue(wsFrame{msgType: websocket.BinaryMessage, data: buf, output: len(data)})
+}
+
+// Notify 채널 번호를 붙여 제어 이벤트 전송
+func (ch *wsChannel) Notify(evt terminal.Event) bool {
+	return ch.cl.SendJSON(muxEvent{Channel: ch.id, Event: evt})
+}
+
+// Close 채널을 닫고 종료 사유 전달 (웹소켓 연결과 다른 채널은 유지)
+// 송신 대기열이 가득 찬 경우에는 모든 채널이 영향을 받으므로 연결 전체를 종료
+func (ch *wsChannel) Close(reason string) {
+	if reason == terminal.ReasonSlowClient {
+		ch.cl.Close(reason)
+		return
+	}
+	if ch.cl.removeChannel(ch) {
+		ch.closed(reason)
+	}
+}
+
+// closed 채널 종료 사유 전송
+func (ch *wsChannel) closed(reason string) {
+	ch.cl.SendJSON(muxEvent{
+		Channel: ch.id,
+		Code:    wsCloseCodes[reason],
+		Event:   terminal.Event{Type: muxClosed, Reason: reason},
+	})
+}
+
+// fail 오류 메시지를 채널에 출력하고 채널 종료
+func (ch *wsChannel) fail(reason, msg string) {
+	ch.Send([]byte("\r\n[RootWeb] " + msg + "\r\n"))
+	ch.Close(reason)
+}
+
+// channel 채널 번호로 채널 조회
+func (cl *wsClient) channel(id uint16) *wsChannel {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	return cl.channels[id]
+}
+
+// addChannel 채널 등록 (최대 개수를 넘으면 false)
+func (cl *wsClient) addChannel(ch *wsChannel) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if len(cl.channels) >= maxMuxChannels {
+		return false
+	}
+	cl.channels[ch.id] = ch
+	return true
+}
+
+// removeChannel 채널 등록 해제 (이미 해제되었으면 false)
+func (cl *wsClient) removeChannel(ch *wsChannel) bool {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	if cl.channels[ch.id] != ch {
+		return false
+	}
+	delete(cl.channels, ch.id)
+	return true
+}
+
+// channelList 등록된 채널 목록
+func (cl *wsClient) channelList() []*wsChannel {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	list := make([]*wsChannel, 0, len(cl.channels))
+	for _, ch := range cl.channels {
+		list = append(list, ch)
+	}
+	return list
+}
+
+// takeChannels 모든 채널 등록 해제 후 목록 반환
+func (cl *wsClient) takeChannels() []*wsChannel {
+	cl.mu.Lock()
+	defer cl.mu.Unlock()
+	list := make([]*wsChannel, 0, len(cl.channels))
+	for _, ch := range cl.channels {
+		list = append(list, ch)
+	}
+	cl.channels = map[uint16]*wsChannel{}
+	return list
+}
+
+// notice 안내 문구를 터미널에 출력 (다중 채널 연결은 모든 채널에 출력)
+func (cl *wsClient) notice(msg string) {
+	data := []byte("\r\n[RootWeb] " + msg + "\r\n")
+
+	cl.mu.Lock()
+	if cl.channels == nil {
+		cl.mu.Unlock()
+		cl.Send(data)
+		return
+	}
+	cl.mu.Unlock()
+
+	for _, ch := range cl.channelList() {
+		ch.Send(data)
+	}
+}
+
+// serveMux 다중 채널 연결의 메시지 처리 (연결이 끊기면 모든 채널의 세션을 유지한 채 분리)
+// restart: 새 쉘 시작 허용 여부 (본인 세션을 여는 연결만 허용, 공유 세션 참여 연결은 불가)
+func serveMux(c *gin.Context, cl *wsClient, open muxOpener, restart bool) {
+	cl.mu.Lock()
+	cl.channels = map[uint16]*wsChannel{}
+	cl.mu.Unlock()
+
+	for {
+		msgType, msg, err := cl.readMessage()
+		if err != nil {
+			break
+		}
+
+		if msgType == websocket.BinaryMessage {
+			// 채널 번호 + 터미널 입력 데이터 (읽기 전용 참여자의 입력은 무시)
+			if len(msg) <= muxHeaderSize {
+				continue
+			}
+			ch := cl.channel(binary.BigEndian.Uint16(msg))
+			if ch == nil {
+				continue
+			}
+			if !cl.checkStepUp(c) {
+				for _, joined := range cl.channelList() {
+					revokeStepUpWrite(c, cl, joined.id, joined.sess, joined)
+				}
+			}
+			// PTY 쓰기 오류는 쉘 종료 중에만 발생하며, 채널은 세션 종료 시 닫힘
+			if err := ch.sess.Write(ch, msg[muxHeaderSize:]); err == nil {
+				metrics.TerminalInput(len(msg) - muxHeaderSize)
+			} else if !errors.Is(err, terminal.ErrReadOnly) {
+				logger.PTY.Warnw("Failed to write terminal input", "id", ch.sess.ID, "ip", c.ClientIP(), "err", err)
+			}
+			continue
+		}
+
+		var r wsMsg
+		if err := json.Unmarshal(msg, &r); err != nil {
+			logger.PTY.Warnw("Failed to Unmarshal", "ip", c.ClientIP(), "err", err)
+			cl.SendJSON(wsControl{Type: msgError, Error: errCodeBadRequest, Message: "invalid JSON"})
+			continue
+		}
+		if handleConnMsg(c, cl, r) {
+			continue
+		}
+
+		if r.MsgType == muxOpen {
+			openChannel(c, cl, r, open)
+			continue
+		}
+		if r.MsgType == muxRestart {
+			if !restart {
+				cl.sendError(r.Channel, r.MsgType, terminal.ErrNotOwner)
+				continue
+			}
+			restartChannel(c, cl, r, open)
+			continue
+		}
+
+		ch := cl.channel(r.Channel)
+		if ch == nil {
+			cl.sendError(r.Channel, r.MsgType, errUnknownChannel)
+			continue
+		}
+		switch r.MsgType {
+		case muxDetach:
+			cl.removeChannel(ch)
+			ch.sess.Detach(ch)
+		case muxClose:
+			cl.removeChannel(ch)
+			ch.sess.Detach(ch)
+			if ch.owner {
+				ch.sess.Terminate(terminal.ReasonTerminated)
+				logger.PTY.Infow("Terminal session closed by owner",
+					"id", ch.sess.ID, "user", ch.sess.Username, "ip", c.ClientIP())
+				recordAudit(c, audit.TypeTerminalTerminate, ch.sess.ID,
+					map[string]interface{}{"owner": ch.sess.Username, "action": "close"})
+			}
+		default:
+			if err := handleTerminalMsg(c, ch.sess, ch, r); err != nil {
+				logger.PTY.Warnw("Failed to handle terminal message",
+					"type", r.MsgType, "id", ch.sess.ID, "ch", ch.id, "ip", c.ClientIP(), "err", err)
+				cl.sendError(ch.id, r.MsgType, err)
+			}
+		}
+	}
+
+	for _, ch := range cl.takeChannels() {
+		ch.sess.Detach(ch)
+	}
+	cl.Close("")
+	<-cl.done
+}
+
+// openChannel 새 채널을 등록하고 세션에 연결
+func openChannel(c *gin.Context, cl *wsClient, r wsMsg, open muxOpener) {
+	ch := &wsChannel{cl: cl, id: r.Channel}
+	if ch.id == 0 || cl.channel(ch.id) != nil {
+		logger.PTY.Warnw("Refused to open terminal channel", "ch", r.Channel, "ip", c.ClientIP(), "err", errChannelInUse)
+		cl.sendError(r.Channel, r.MsgType, errChannelInUse)
+		return
+	}
+	if !cl.addChannel(ch) {
+		logger.PTY.Warnw("Refused to open terminal channel", "ch", r.Channel, "ip", c.ClientIP(), "err", "too many channels")
+		ch.Send([]byte("\r\n[RootWeb] too many terminals in one connection\r\n"))
+		ch.closed(terminal.ReasonTerminated)
+		return
+	}
+
+	sess, reason, msg := open(ch, r)
+	if sess == nil {
+		ch.fail(reason, msg)
+		return
+	}
+	ch.sess = sess
+}
+
+// restartChannel 채널의 쉘을 종료하고 같은 채널에서 새 쉘 시작 (쉘이 이미 종료된 채널도 가능)
+func restartChannel(c *gin.Context, cl *wsClient, r wsMsg, open muxOpener) {
+	if ch := cl.channel(r.Channel); ch != nil {
+		if !ch.owner {
+			cl.sendError(r.Channel, r.MsgType, terminal.ErrNotOwner)
+			return
+		}
+		cl.removeChannel(ch)
+		ch.sess.Detach(ch)
+		ch.sess.Terminate(terminal.ReasonTerminated)
+		logger.PTY.Infow("Terminal session restarted by owner", "id", ch.sess.ID, "user", ch.sess.Username, "ip", c.ClientIP())
+		recordAudit(c, audit.TypeTerminalTerminate, ch.sess.ID,
+			map[string]interface{}{"owner": ch.sess.Username, "action": "restart"})
+	}
+
+	r.Session = ""
+	openChannel(c, cl, r, open)
+}
@@ -0,0 +1,204 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/terminal"
+)
+
+// 터미널 웹소켓 제어 프로토콜 (docs/terminal-protocol.md 참고)
+// 서버는 연결 직후 hello로 프로토콜 버전과 지원 기능을 알리고,
+// 클라이언트가 hello로 사용할 버전을 알리면 해당 버전으로 동작 (hello를 보내지 않는 클라이언트는 버전 0으로 취급)
+const protocolVersion = 1
+
+// 서버가 지원하는 프로토콜 버전
+var protocolVersions = []int{protocolVersion}
+
+// 하트비트 간격 (버전 1 이상의 클라이언트가 이 간격의 3배 동안 아무 메시지도 보내지 않으면 연결 종료)
+const wsHeartbeatInterval = 30 * time.Second
+
+// 웹소켓 메시지 최대 크기 (파일 전송이 비활성화되어 있으면 이 크기만 허용)
+const wsMaxMessageSize = 1 << 20
+
+// 업로드 메시지에서 파일 데이터 외 JSON 필드에 허용하는 여유분
+const wsMessageOverhead = 64 << 10
+
+// 연결 수준 제어 메시지 종류
+const (
+	msgHello = "hello" // 프로토콜 버전 및 지원 기능 교환
+	msgPing  = "ping"  // 클라이언트 하트비트 (서버는 같은 seq로 pong 응답)
+	msgPong  = "pong"
+	msgError = "error" // 처리하지 못한 요청에 대한 오류 (Error: 오류 코드, Request: 요청 종류)
+)
+
+// 오류 코드
+const (
+	errCodeBadRequest         = "bad_request"         // JSON 형식 오류
+	errCodeUnknownType        = "unknown_type"        // 알 수 없는 메시지 종류
+	errCodeUnknownChannel     = "unknown_channel"     // 열려 있지 않은 채널
+	errCodeUnsupportedVersion = "unsupported_version" // 지원하지 않는 프로토콜 버전 (연결 종료)
+	errCodeForbidden          = "forbidden"           // 권한 없음 (소유자 전용, 읽기 전용 참여자 등)
+	errCodeNotFound           = "not_found"           // 대상 참여자 없음
+	errCodeInvalid            = "invalid"             // 잘못된 값 (지원하지 않는 시그널, 손상된 전송 데이터 등)
+	errCodeInvalidState       = "invalid_state"       // 현재 상태에서 할 수 없는 요청 (업로드 요청 없음, 이미 열린 채널 등)
+	errCodeTooLarge           = "too_large"           // 크기 제한 초과
+	errCodeStepUp             = "step_up"             // OTP 재인증 만료로 입력 권한 회수
+	errCodeInternal           = "internal"            // 서버 내부 오류
+)
+
+var (
+	errUnknownType        = errors.New("unknown message type")
+	errUnknownChannel     = errors.New("channel is not open")
+	errChannelInUse       = errors.New("channel is already open")
+	errUnsupportedVersion = errors.New("unsupported protocol version")
+	errStepUpRequired     = errors.New("OTP verification required")
+)
+
+// wsControl 서버가 보내는 연결 수준 제어 메시지
+type wsControl struct {
+	Type         string   `json:"type"`
+	Channel      uint16   `json:"ch,omitempty"`
+	Version      int      `json:"version,omitempty"`
+	Versions     []int    `json:"versions,omitempty"`
+	Capabilities []string `json:"capabilities,omitempty"`
+	Heartbeat    int      `json:"heartbeat,omitempty"`
+	Seq          int64    `json:"seq,omitempty"`
+	Error        string   `json:"error,omitempty"`
+	Message      string   `json:"message,omitempty"`
+	Request      string   `json:"request,omitempty"`
+}
+
+// sendHello 프로토콜 버전과 지원 기능 알림 (연결 직후 가장 먼저 전송)
+// owner: 본인 세션을 여는 연결 여부 (다중 채널 연결에서 쉘 재시작 가능)
+func (cl *wsClient) sendHello(mux, owner bool) {
+	caps := []string{"signal", "exit", "heartbeat"}
+	if mux {
+		caps = append(caps, "mux")
+		if owner {
+			caps = append(caps, "restart")
+		}
+	}
+	if terminal.TransferMaxSize() > 0 {
+		caps = append(caps, "transfer")
+	}
+	cl.SendJSON(wsControl{
+		Type:         msgHello,
+		Version:      protocolVersion,
+		Versions:     protocolVersions,
+		Capabilities: caps,
+		Heartbeat:    int(wsHeartbeatInterval.Seconds()),
+	})
+}
+
+// sendError 요청 처리 실패를 오류 코드와 함께 전달
+func (cl *wsClient) sendError(ch uint16, request string, err error) {
+	code := errorCode(err)
+	msg := err.Error()
+	if code == errCodeInternal {
+		msg = "internal error"
+	}
+	cl.SendJSON(wsControl{Type: msgError, Channel: ch, Error: code, Message: msg, Request: request})
+}
+
+// errorCode 오류에 해당하는 오류 코드
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, errUnknownType):
+		return errCodeUnknownType
+	case errors.Is(err, errUnknownChannel):
+		return errCodeUnknownChannel
+	case errors.Is(err, errUnsupportedVersion):
+		return errCodeUnsupportedVersion
+	case errors.Is(err, terminal.ErrNotOwner), errors.Is(err, terminal.ErrReadOnly),
+		errors.Is(err, terminal.ErrCannotWrite), errors.Is(err, terminal.ErrSignalDenied):
+		return errCodeForbidden
+	case errors.Is(err, terminal.ErrNoParticipant):
+		return errCodeNotFound
+	case errors.Is(err, terminal.ErrInvalidSignal), errors.Is(err, terminal.ErrTransferInvalid):
+		return errCodeInvalid
+	case errors.Is(err, terminal.ErrNoUpload), errors.Is(err, errChannelInUse), errors.Is(err, terminal.ErrNoForeground):
+		return errCodeInvalidState
+	case errors.Is(err, terminal.ErrTransferTooLarge):
+		return errCodeTooLarge
+	case errors.Is(err, errStepUpRequired):
+		return errCodeStepUp
+	}
+	return errCodeInternal
+}
+
+// wsReadLimit 수신 메시지 최대 크기 (파일 전송이 활성화되어 있으면 base64로 인코딩된 최대 업로드 크기까지 허용)
+func wsReadLimit() int64 {
+	if maxSize := terminal.TransferMaxSize(); maxSize > 0 {
+		return max(int64(base64.StdEncoding.EncodedLen(maxSize))+wsMessageOverhead, wsMaxMessageSize)
+	}
+	return wsMaxMessageSize
+}
+
+// readMessage 웹소켓 메시지 수신 (하트비트를 보내는 클라이언트는 응답이 끊기면 타임아웃)
+// 최대 크기를 넘는 메시지는 버퍼에 담지 않고 버린 뒤 too_large 오류로 알림
+func (cl *wsClient) readMessage() (int, []byte, error) {
+	for {
+		msgType, r, err := cl.conn.NextReader()
+		if err != nil {
+			return msgType, nil, err
+		}
+		msg, err := io.ReadAll(io.LimitReader(r, cl.readLimit+1))
+		if err == nil && int64(len(msg)) > cl.readLimit {
+			_, err = io.Copy(io.Discard, r)
+			if err == nil {
+				logger.PTY.Warnw("Rejected oversized terminal message", "limit", cl.readLimit, "ip", cl.ip)
+				cl.SendJSON(wsControl{Type: msgError, Error: errCodeTooLarge, Message: "message too large"})
+				continue
+			}
+		}
+		if err != nil {
+			return msgType, nil, err
+		}
+		if cl.version > 0 {
+			cl.conn.SetReadDeadline(time.Now().Add(3 * wsHeartbeatInterval))
+		}
+		return msgType, msg, nil
+	}
+}
+
+// handleConnMsg 연결 수준 제어 메시지 처리 (처리했으면 true)
+func handleConnMsg(c *gin.Context, cl *wsClient, r wsMsg) bool {
+	switch r.MsgType {
+	case msgHello:
+		if !slices.Contains(protocolVersions, r.Version) {
+			logger.PTY.Warnw("Unsupported terminal protocol version", "version", r.Version, "ip", c.ClientIP())
+			cl.sendError(0, r.MsgType, errUnsupportedVersion)
+			cl.Close(reasonProtocol)
+			return true
+		}
+		cl.version = r.Version
+		cl.conn.SetReadDeadline(time.Now().Add(3 * wsHeartbeatInterval))
+	case msgPing:
+		cl.SendJSON(wsControl{Type: msgPong, Seq: r.Seq})
+	default:
+		return false
+	}
+	return true
+}
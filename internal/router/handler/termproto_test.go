@@ -0,0 +1,84 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+func TestReadMessageTooLarge(t *testing.T) {
+	const limit = 16
+	received := make(chan string, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		cl := newWSClient(conn, "127.0.0.1")
+		cl.readLimit = limit
+		conn.SetReadLimit(2 * limit)
+		defer cl.Close("")
+		for {
+			_, msg, err := cl.readMessage()
+			if err != nil {
+				close(received)
+				return
+			}
+			received <- string(msg)
+		}
+	}))
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	// 최대 크기를 넘는 메시지는 버리고 too_large 오류 전달 (연결 유지)
+	conn.WriteMessage(websocket.TextMessage, bytes.Repeat([]byte("x"), limit+1))
+	var resp wsControl
+	if err := conn.ReadJSON(&resp); err != nil {
+		t.Fatalf("read error message: %v", err)
+	}
+	if resp.Type != msgError || resp.Error != errCodeTooLarge {
+		t.Errorf("response = %+v, want %s error", resp, errCodeTooLarge)
+	}
+
+	conn.WriteMessage(websocket.TextMessage, []byte("ok"))
+	if got := <-received; got != "ok" {
+		t.Errorf("next message = %q, want %q", got, "ok")
+	}
+
+	// 최대 크기의 2배를 넘으면 읽지 않고 연결 종료
+	conn.WriteMessage(websocket.TextMessage, bytes.Repeat([]byte("x"), 2*limit+1))
+	if _, ok := <-received; ok {
+		t.Error("oversized message was delivered")
+	}
+	_, _, err = conn.ReadMessage()
+	if !websocket.IsCloseError(err, websocket.CloseMessageTooBig) {
+		t.Errorf("close = %v, want %d", err, websocket.CloseMessageTooBig)
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/metrics"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"gorm.io/gorm"
+)
+
+// 로그인 실패 사유
+const (
+	failPassword = "password" // 아이디 또는 비밀번호 불일치
+	failOTP      = "otp"      // OTP 번호 또는 복구 코드 불일치 (재사용 포함)
+	failPasskey  = "passkey"  // 패스키 인증 실패
+	failSetup    = "setup"    // 초기 설정 시 OTP 번호 불일치
+	// 감사 로그에만 기록하는 사유
+	failThrottled = "throttled"     // 시도 제한 중 요청
+	failDisabled  = "disabled"      // 비활성화된 계정
+	failClone     = "passkey_clone" // 서명 카운터가 역행한 패스키
+)
+
+// 제한 대상 구분 접두어
+const (
+	throttleUserPrefix = "user:"
+	throttleIPPrefix   = "ip:"
+)
+
+// throttlePolicy 로그인 시도 제한 정책
+type throttlePolicy struct {
+	window          time.Duration
+	baseDelay       time.Duration
+	maxDelay        time.Duration
+	lockout         time.Duration
+	userMaxFailures int
+	ipMaxFailures   int
+	retention       time.Duration
+}
+
+// loadThrottlePolicy 설정 파일의 로그인 시도 제한 정책 로드 (미설정 항목은 기본값 사용)
+func loadThrottlePolicy() throttlePolicy {
+	conf := config.Conf.LoginThrottle
+	seconds := func(v, def int) time.Duration {
+		if v <= 0 {
+			v = def
+		}
+		return time.Duration(v) * time.Second
+	}
+	positive := func(v, def int) int {
+		if v <= 0 {
+			return def
+		}
+		return v
+	}
+
+	return throttlePolicy{
+		window:          seconds(conf.Window, 900),
+		baseDelay:       seconds(conf.BaseDelay, 1),
+		maxDelay:        seconds(conf.MaxDelay, 60),
+		lockout:         seconds(conf.LockoutDuration, 900),
+		userMaxFailures: positive(conf.UserMaxFailures, 5),
+		ipMaxFailures:   positive(conf.IPMaxFailures, 20),
+		retention:       time.Duration(positive(conf.Retention, 30)) * 24 * time.Hour,
+	}
+}
+
+// delay 연속 실패 횟수에 따른 대기 시간 (최대 실패 횟수에 도달하면 잠금)
+func (p throttlePolicy) delay(failures, maxFailures int) (time.Duration, bool) {
+	if failures >= maxFailures {
+		return p.lockout, true
+	}
+
+	d := p.baseDelay
+	for i := 1; i < failures && d < p.maxDelay; i++ {
+		d *= 2
+	}
+	return min(d, p.maxDelay), false
+}
+
+// checkLoginThrottle 아이디 또는 IP가 제한 중이면 남은 대기 시간 반환 (username이 빈 값이면 IP만 확인)
+func checkLoginThrottle(username, ip string) time.Duration {
+	subjects := []string{throttleIPPrefix + ip}
+	if username != "" {
+		subjects = append(subjects, throttleUserPrefix+username)
+	}
+
+	var throttles []db.LoginThrottle
+	if err := db.SqliteDB.Where("subject IN ? AND blocked_until > ?", subjects, time.Now()).Find(&throttles).Error; err != nil {
+		logger.Auth.Errorw("Failed to query login throttle", "ip", ip, "err", err)
+		return 0
+	}
+
+	var wait time.Duration
+	for _, t := range throttles {
+		wait = max(wait, time.Until(t.BlockedUntil))
+	}
+	return wait
+}
+
+// recordLoginFailure 실패한 로그인 시도 기록 후 IP 및 아이디(limitUser 설정 시) 제한 갱신
+func recordLoginFailure(c *gin.Context, username, reason string, limitUser bool) {
+	p := loadThrottlePolicy()
+	ip := c.ClientIP()
+	now := time.Now()
+
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&db.LoginAttempt{Username: username, ClientIP: ip, Reason: reason}).Error; err != nil {
+			return err
+		}
+		// 보관 기간이 지난 실패 기록 정리
+		if err := tx.Unscoped().Where("created_at < ?", now.Add(-p.retention)).Delete(&db.LoginAttempt{}).Error; err != nil {
+			return err
+		}
+
+		if err := updateThrottle(tx, p, throttleIPPrefix+ip, p.ipMaxFailures, now); err != nil {
+			return err
+		}
+		if limitUser && username != "" {
+			return updateThrottle(tx, p, throttleUserPrefix+username, p.userMaxFailures, now)
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Auth.Errorw("Failed to record login failure", "user", username, "ip", ip, "err", err)
+		return
+	}
+
+	logger.Auth.Warnw("Login failed", "user", username, "reason", reason, "ip", ip)
+	auditLoginFailure(c, username, reason)
+}
+
+// auditLoginFailure 로그인 실패를 감사 로그에 기록 (재인증처럼 로그인 상태에서 실패한 경우 사용자 ID 포함)
+func auditLoginFailure(c *gin.Context, username, reason string) {
+	e := audit.Event{
+		Type:     audit.TypeLoginFailure,
+		Username: username,
+		ClientIP: c.ClientIP(),
+		Details:  map[string]interface{}{"reason": reason, "path": c.Request.URL.Path},
+	}
+	if user := middleware.CurrentUser(c); user != nil {
+		e.UserID = user.ID
+	}
+	audit.Record(e)
+	metrics.LoginFailed(reason)
+}
+
+// updateThrottle 제한 대상의 연속 실패 횟수를 증가시키고 대기 시간 설정 (호출 측 트랜잭션 내에서 사용)
+func updateThrottle(tx *gorm.DB, p throttlePolicy, subject string, maxFailures int, now time.Time) error {
+	var t db.LoginThrottle
+	err := tx.Where("subject = ?", subject).First(&t).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// 집계 기간이 지났고 제한도 풀린 상태면 처음부터 다시 집계
+	if t.ID == 0 || (now.Sub(t.LastFailureAt) > p.window && now.After(t.BlockedUntil)) {
+		t.Failures = 0
+		t.Locked = false
+	}
+	t.Subject = subject
+	t.Failures++
+	t.LastFailureAt = now
+
+	d, locked := p.delay(t.Failures, maxFailures)
+	t.BlockedUntil = now.Add(d)
+	if locked && !t.Locked {
+		logger.Auth.Warnw("Login locked out",
+			"subject", subject, "failures", t.Failures, "until", t.BlockedUntil.Format(time.RFC3339))
+	}
+	t.Locked = locked
+
+	return tx.Save(&t).Error
+}
+
+// resetLoginThrottle 로그인 성공 시 아이디의 연속 실패 기록 초기화 (IP 제한은 유지)
+func resetLoginThrottle(username string) {
+	err := db.SqliteDB.Unscoped().Where("subject = ?", throttleUserPrefix+username).Delete(&db.LoginThrottle{}).Error
+	if err != nil {
+		logger.Auth.Errorw("Failed to reset login throttle", "user", username, "err", err)
+	}
+}
+
+// throttleMessage 제한 중인 사용자에게 보여줄 안내 문구
+func throttleMessage(wait time.Duration) string {
+	secs := int(wait.Round(time.Second) / time.Second)
+	return "로그인 시도가 너무 많습니다. " + strconv.Itoa(max(secs, 1)) + "초 후 다시 시도하세요."
+}
+
+// setRetryAfter Retry-After 응답 헤더 설정
+func setRetryAfter(c *gin.Context, wait time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(max(int(wait.Round(time.Second)/time.Second), 1)))
+}
+
+// throttleView 제한 상태 응답 형식
+type throttleView struct {
+	ID            uint
+	Kind          string // user 또는 ip
+	Target        string
+	Failures      int
+	Locked        bool
+	LastFailureAt time.Time
+	BlockedUntil  time.Time
+}
+
+// HtmlLoginAttempts [GET /login-attempts] 로그인 제한 상태 및 실패 기록 페이지 렌더링
+func HtmlLoginAttempts(c *gin.Context) {
+	var throttles []db.LoginThrottle
+	if err := db.SqliteDB.Where("blocked_until > ?", time.Now()).Order("blocked_until DESC").Find(&throttles).Error; err != nil {
+		c.String(http.StatusInternalServerError, "로그인 제한 상태 조회 실패")
+		logger.Auth.Errorw("Failed to query login throttles", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	var attempts []db.LoginAttempt
+	if err := db.SqliteDB.Order("id DESC").Limit(200).Find(&attempts).Error; err != nil {
+		c.String(http.StatusInternalServerError, "로그인 실패 기록 조회 실패")
+		logger.Auth.Errorw("Failed to query login attempts", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	views := make([]throttleView, 0, len(throttles))
+	for _, t := range throttles {
+		kind, target, _ := strings.Cut(t.Subject, ":")
+		views = append(views, throttleView{
+			ID:            t.ID,
+			Kind:          kind,
+			Target:        target,
+			Failures:      t.Failures,
+			Locked:        t.Locked,
+			LastFailureAt: t.LastFailureAt,
+			BlockedUntil:  t.BlockedUntil,
+		})
+	}
+
+	c.HTML(http.StatusOK, "login_attempts.html", gin.H{
+		"User":      middleware.CurrentUser(c),
+		"Throttles": views,
+		"Attempts":  attempts,
+	})
+}
+
+// UnlockLoginThrottle [DELETE /api/login-throttles/:id] 아이디 또는 IP의 로그인 제한 해제
+func UnlockLoginThrottle(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	var t db.LoginThrottle
+	if err := db.SqliteDB.First(&t, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "제한 정보를 찾을 수 없습니다."})
+		return
+	}
+
+	if err := db.SqliteDB.Unscoped().Delete(&t).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "로그인 제한 해제 실패"})
+		logger.Auth.Errorw("Failed to delete login throttle", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.Auth.Infow("Login throttle cleared",
+		"subject", t.Subject, "by", middleware.CurrentUser(c).Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeThrottleClear, t.Subject, map[string]interface{}{"failures": t.Failures, "locked": t.Locked})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,423 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/pkg/passwd"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// 최소 비밀번호 길이
+const minPasswordLen = 8
+
+// 아이디 형식이 맞지 않을 때 안내 문구
+const usernameRuleMessage = "아이디는 영문 소문자 또는 밑줄로 시작하고, 영문 소문자, 숫자, 밑줄, 점, 하이픈으로 32자 이하여야 합니다."
+
+var errLastAdmin = errors.New("last_admin")
+
+// userView 사용자 정보 응답 형식 (비밀번호, OTP Secret 제외)
+type userView struct {
+	ID          uint      `json:"id"`
+	Username    string    `json:"username"`
+	Role        string    `json:"role"`
+	Disabled    bool      `json:"disabled"`
+	OTPEnrolled bool      `json:"otpEnrolled"`
+	Passkeys    int64     `json:"passkeys"`
+	UnixUser    string    `json:"unixUser"`
+	AllowRoot   bool      `json:"allowRoot"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// userReq 사용자 생성/수정 요청 형식
+type userReq struct {
+	Username  string  `json:"username"`
+	Password  string  `json:"password"`
+	Role      string  `json:"role"`
+	Disabled  *bool   `json:"disabled"`
+	ResetOTP  bool    `json:"resetOtp"`
+	UnixUser  *string `json:"unixUser"`
+	AllowRoot *bool   `json:"allowRoot"`
+}
+
+// newUserView 사용자 정보를 응답 형식으로 변환
+func newUserView(u *db.User) userView {
+	return userView{
+		ID:          u.ID,
+		Username:    u.Username,
+		Role:        u.Role,
+		Disabled:    u.Disabled,
+		OTPEnrolled: u.OTPSecret != "",
+		UnixUser:    u.UnixUser,
+		AllowRoot:   u.AllowRoot,
+		CreatedAt:   u.CreatedAt,
+	}
+}
+
+// findUser URL 파라미터의 ID로 사용자 조회
+func findUser(c *gin.Context, tx *gorm.DB) (*db.User, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return nil, false
+	}
+
+	var user db.User
+	if err := tx.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "사용자를 찾을 수 없습니다."})
+		return nil, false
+	}
+
+	return &user, true
+}
+
+// ensureOtherAdmin 대상 외에 활성화된 관리자가 남아있는지 확인 (호출 측 트랜잭션 내에서 사용)
+func ensureOtherAdmin(tx *gorm.DB, target *db.User) error {
+	if !target.IsAdmin() || target.Disabled {
+		return nil
+	}
+
+	var count int64
+	err := tx.Model(&db.User{}).
+		Where("role = ? AND disabled = ? AND id <> ?", db.RoleAdmin, false, target.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return errLastAdmin
+	}
+	return nil
+}
+
+// validateUnixUser 매핑할 리눅스 계정이 존재하는지 확인 (빈 값은 매핑 해제)
+func validateUnixUser(c *gin.Context, name *string) bool {
+	if name == nil {
+		return true
+	}
+
+	*name = strings.TrimSpace(*name)
+	if *name == "" {
+		return true
+	}
+	if _, err := passwd.Lookup(*name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "존재하지 않는 리눅스 계정입니다. (" + *name + ")"})
+		return false
+	}
+	return true
+}
+
+// HtmlUsers [GET /users] 사용자 관리 페이지 렌더링
+func HtmlUsers(c *gin.Context) {
+	c.HTML(http.StatusOK, "users.html", gin.H{
+		"User": middleware.CurrentUser(c),
+	})
+}
+
+// ListUsers [GET /api/users] 사용자 목록 조회
+func ListUsers(c *gin.Context) {
+	var users []db.User
+	if err := db.SqliteDB.Order("id").Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 목록 조회 실패"})
+		logger.Auth.Errorw("Failed to query users", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	views := make([]userView, 0, len(users))
+	for i := range users {
+		view := newUserView(&users[i])
+		view.Passkeys = countPasskeys(users[i].ID)
+		views = append(views, view)
+	}
+	c.JSON(http.StatusOK, gin.H{"users": views})
+}
+
+// CreateUser [POST /api/users] 사용자 생성 (OTP는 최초 로그인 시 본인이 등록)
+func CreateUser(c *gin.Context) {
+	var req userReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+
+	req.Username = strings.TrimSpace(req.Username)
+	if req.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "아이디를 입력하세요."})
+		return
+	}
+	if !db.IsValidUsername(req.Username) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": usernameRuleMessage})
+		return
+	}
+	if len(req.Password) < minPasswordLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "비밀번호는 8자 이상이어야 합니다."})
+		return
+	}
+	if !db.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "알 수 없는 권한 등급입니다."})
+		return
+	}
+	if !validateUnixUser(c, req.UnixUser) {
+		return
+	}
+
+	// 비밀번호 보안 해싱
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "비밀번호 암호화 실패"})
+		logger.Auth.Errorw("Failed to hash password", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	user := db.User{
+		Username: req.Username,
+		Password: string(hashedPassword),
+		Role:     req.Role,
+	}
+	if req.UnixUser != nil {
+		user.UnixUser = *req.UnixUser
+	}
+	if req.AllowRoot != nil {
+		user.AllowRoot = *req.AllowRoot
+	}
+	if err := db.SqliteDB.Create(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "UNIQUE") {
+			c.JSON(http.StatusConflict, gin.H{"error": "이미 존재하는 아이디입니다."})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 저장 실패"})
+		logger.Auth.Errorw("Failed to create user", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	logger.Auth.Infow("User created",
+		"user", user.Username, "role", user.Role, "unixUser", user.UnixUser, "allowRoot", user.AllowRoot,
+		"by", middleware.CurrentUser(c).Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeUserCreate, user.Username, map[string]interface{}{
+		"role":      user.Role,
+		"unixUser":  user.UnixUser,
+		"allowRoot": user.AllowRoot,
+	})
+	c.JSON(http.StatusCreated, gin.H{"user": newUserView(&user)})
+}
+
+// UpdateUser [PATCH /api/users/:id] 사용자 권한 등급, 활성화 상태, 비밀번호, OTP 변경
+func UpdateUser(c *gin.Context) {
+	var req userReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "잘못된 요청입니다."})
+		return
+	}
+	if req.Role != "" && !db.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "알 수 없는 권한 등급입니다."})
+		return
+	}
+	if req.Password != "" && len(req.Password) < minPasswordLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "비밀번호는 8자 이상이어야 합니다."})
+		return
+	}
+	if !validateUnixUser(c, req.UnixUser) {
+		return
+	}
+
+	me := middleware.CurrentUser(c)
+	var updated db.User
+	var handled bool
+
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		target, ok := findUser(c, tx)
+		if !ok {
+			handled = true
+			return nil
+		}
+
+		// 본인의 권한 등급 변경 및 비활성화 금지
+		demote := req.Role != "" && req.Role != target.Role
+		disable := req.Disabled != nil && *req.Disabled && !target.Disabled
+		if target.ID == me.ID && (demote || disable) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "본인 계정의 권한 또는 상태는 변경할 수 없습니다."})
+			handled = true
+			return nil
+		}
+
+		// 마지막 관리자 보호
+		if (demote && req.Role != db.RoleAdmin) || disable {
+			if err := ensureOtherAdmin(tx, target); err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{}
+		if req.Role != "" {
+			updates["role"] = req.Role
+		}
+		if req.Disabled != nil {
+			updates["disabled"] = *req.Disabled
+		}
+		if req.Password != "" {
+			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+			if err != nil {
+				return err
+			}
+			updates["password"] = string(hashedPassword)
+		}
+		if req.UnixUser != nil {
+			if *req.UnixUser == "" {
+				updates["unix_user"] = gorm.Expr("NULL")
+			} else {
+				updates["unix_user"] = *req.UnixUser
+			}
+		}
+		if req.AllowRoot != nil {
+			updates["allow_root"] = *req.AllowRoot
+		}
+		if req.ResetOTP {
+			// 다음 로그인 시 OTP를 다시 등록하도록 함 (분실 대비를 위해 복구 코드와 패스키도 함께 삭제)
+			updates["otp_secret"] = gorm.Expr("NULL")
+			if err := tx.Unscoped().Where("user_id = ?", target.ID).Delete(&db.WebAuthnCredential{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Unscoped().Where("user_id = ?", target.ID).Delete(&db.RecoveryCode{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(updates) > 0 {
+			if err := tx.Model(target).Updates(updates).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.First(&updated, target.ID).Error
+	})
+
+	if handled {
+		return
+	}
+	if err != nil {
+		if errors.Is(err, errLastAdmin) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "활성화된 관리자가 최소 1명 있어야 합니다."})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 정보 변경 실패"})
+		logger.Auth.Errorw("Failed to update user", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	// 비활성화된 계정의 로그인 세션 및 터미널 연결 종료
+	if updated.Disabled {
+		revokeUserSessions(c, &updated)
+	}
+
+	logger.Auth.Infow("User updated",
+		"user", updated.Username, "role", updated.Role, "disabled", updated.Disabled, "unixUser", updated.UnixUser,
+		"allowRoot", updated.AllowRoot, "password", req.Password != "", "resetOtp", req.ResetOTP,
+		"by", me.Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeUserUpdate, updated.Username, userChanges(&req))
+	c.JSON(http.StatusOK, gin.H{"user": newUserView(&updated)})
+}
+
+// userChanges 감사 로그에 남길 변경 항목 (비밀번호는 변경 여부만 기록)
+func userChanges(req *userReq) map[string]interface{} {
+	changes := map[string]interface{}{}
+	if req.Role != "" {
+		changes["role"] = req.Role
+	}
+	if req.Disabled != nil {
+		changes["disabled"] = *req.Disabled
+	}
+	if req.UnixUser != nil {
+		changes["unixUser"] = *req.UnixUser
+	}
+	if req.AllowRoot != nil {
+		changes["allowRoot"] = *req.AllowRoot
+	}
+	if req.Password != "" {
+		changes["password"] = true
+	}
+	if req.ResetOTP {
+		changes["resetOtp"] = true
+	}
+	return changes
+}
+
+// DeleteUser [DELETE /api/users/:id] 사용자 삭제
+func DeleteUser(c *gin.Context) {
+	me := middleware.CurrentUser(c)
+	var deleted db.User
+	var handled bool
+
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		target, ok := findUser(c, tx)
+		if !ok {
+			handled = true
+			return nil
+		}
+
+		if target.ID == me.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "본인 계정은 삭제할 수 없습니다."})
+			handled = true
+			return nil
+		}
+
+		if err := ensureOtherAdmin(tx, target); err != nil {
+			return err
+		}
+
+		// 동일 아이디로 재생성할 수 있도록 영구 삭제 (녹화 및 명령 실행 기록에는 아이디가 남음)
+		deleted = *target
+		if err := tx.Unscoped().Where("user_id = ?", target.ID).Delete(&db.WebAuthnCredential{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", target.ID).Delete(&db.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("user_id = ?", target.ID).Delete(&db.Snippet{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(target).Error
+	})
+
+	if handled {
+		return
+	}
+	if err != nil {
+		if errors.Is(err, errLastAdmin) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "활성화된 관리자가 최소 1명 있어야 합니다."})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "사용자 삭제 실패"})
+		logger.Auth.Errorw("Failed to delete user", "ip", c.ClientIP(), "err", err)
+		return
+	}
+
+	revokeUserSessions(c, &deleted)
+
+	logger.Auth.Infow("User deleted", "user", deleted.Username, "by", me.Username, "ip", c.ClientIP())
+	recordAudit(c, audit.TypeUserDelete, deleted.Username, map[string]interface{}{"role": deleted.Role})
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
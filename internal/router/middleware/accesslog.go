@@ -0,0 +1,141 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	ctxRequestIDKey = "request_id"
+	redactedValue   = "[REDACTED]"
+)
+
+// 이름에 포함되어 있으면 값을 가리는 쿼리, 폼, 경로 파라미터 키워드
+var sensitiveKeywords = []string{"password", "passwd", "secret", "token", "otp", "csrf", "key", "credential"}
+
+// 리버스 프록시 등이 전달한 요청 ID 중 그대로 사용할 형식
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// AccessLog 요청마다 요청 ID를 부여하고 처리 결과를 접근 로그에 기록하는 미들웨어
+// 쿼리와 폼의 민감한 값은 가려서 기록하며, 폼은 핸들러가 읽은 경우에만 기록함
+func AccessLog() gin.HandlerFunc {
+	extra := make(map[string]bool)
+	for _, name := range config.Conf.Log.Access.RedactFields {
+		extra[strings.ToLower(name)] = true
+	}
+	sensitive := func(name string) bool {
+		name = strings.ToLower(name)
+		if extra[name] {
+			return true
+		}
+		for _, k := range sensitiveKeywords {
+			if strings.Contains(name, k) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := c.GetHeader(requestIDHeader)
+		if !requestIDPattern.MatchString(id) {
+			id = newRequestID()
+		}
+		c.Set(ctxRequestIDKey, id)
+		c.Header(requestIDHeader, id)
+
+		c.Next()
+
+		e := &logger.AccessEntry{
+			RequestID: id,
+			Method:    c.Request.Method,
+			Path:      redactPath(c.Request.URL.Path, c.Params, sensitive),
+			Query:     redactQuery(c.Request.URL.RawQuery, sensitive),
+			Status:    c.Writer.Status(),
+			Latency:   time.Since(start),
+			Bytes:     max(c.Writer.Size(), 0),
+			ClientIP:  c.ClientIP(),
+			Error:     strings.Join(c.Errors.Errors(), "; "),
+		}
+		if form := c.Request.PostForm; len(form) > 0 {
+			e.Form = make(map[string]string, len(form))
+			for name, values := range form {
+				if sensitive(name) {
+					e.Form[name] = redactedValue
+				} else {
+					e.Form[name] = strings.Join(values, ",")
+				}
+			}
+		}
+		if user := CurrentUser(c); user != nil {
+			e.UserID = user.ID
+		}
+		logger.LogAccess(e)
+		logger.Server.Debugw("Request handled",
+			"method", e.Method, "path", e.Path, "status", e.Status, "latency", e.Latency, "id", e.RequestID, "ip", e.ClientIP)
+	}
+}
+
+// RequestID 현재 요청의 요청 ID (접근 로그 미들웨어를 거치지 않았으면 빈 문자열)
+func RequestID(c *gin.Context) string {
+	return c.GetString(ctxRequestIDKey)
+}
+
+// newRequestID 임의의 요청 ID 생성
+func newRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// redactQuery 민감한 쿼리 파라미터 값을 가린 쿼리 문자열
+func redactQuery(raw string, sensitive func(string) bool) string {
+	if raw == "" {
+		return ""
+	}
+	// 해석할 수 없는 부분은 버리고 해석된 파라미터만 기록
+	values, _ := url.ParseQuery(raw)
+	for name := range values {
+		if sensitive(name) {
+			values[name] = []string{redactedValue}
+		}
+	}
+	// 가린 값은 읽기 쉽도록 이스케이프하지 않음
+	return strings.ReplaceAll(values.Encode(), url.QueryEscape(redactedValue), redactedValue)
+}
+
+// redactPath 경로에 포함된 민감한 경로 파라미터 값(초대 링크 토큰 등)을 가린 경로
+func redactPath(path string, params gin.Params, sensitive func(string) bool) string {
+	for _, p := range params {
+		if p.Value != "" && sensitive(p.Key) {
+			path = strings.Replace(path, "/"+p.Value, "/"+redactedValue, 1)
+		}
+	}
+	return path
+}
@@ -0,0 +1,184 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// CSRF 토큰 전달 경로
+const (
+	csrfSessionKey = "csrf_token"   // 세션에 저장하는 키
+	csrfCookieName = "rootweb_csrf" // 스크립트에서 읽을 수 있도록 발급하는 쿠키
+	csrfHeader     = "X-CSRF-Token" // fetch 요청 헤더
+	csrfFormField  = "csrf_token"   // HTML 폼 필드
+	// CSRFQueryParam 웹소켓 핸드셰이크 및 로그아웃 링크의 쿼리 파라미터 (헤더를 지정할 수 없는 요청)
+	CSRFQueryParam = "csrf"
+)
+
+// CSRFProtect 상태를 변경하는 요청과 웹소켓 핸드셰이크의 Origin 및 CSRF 토큰 검증 미들웨어
+// 세션 미들웨어 이후에 동작해야 하며, 조회 요청 시 세션에 토큰이 없으면 발급함
+func CSRFProtect() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if strings.HasPrefix(path, "/static/") || path == "/favicon.ico" {
+			c.Next()
+			return
+		}
+
+		sess := sessions.Default(c)
+		switch {
+		case websocket.IsWebSocketUpgrade(c.Request):
+			// 브라우저는 웹소켓 연결에 Same-Origin 정책을 적용하지 않으므로 Origin 필수
+			if !OriginAllowed(c.Request) {
+				logger.Auth.Warnw("Rejected cross-origin web socket handshake",
+					"ip", c.ClientIP(), "path", path, "origin", c.GetHeader("Origin"))
+				c.String(http.StatusForbidden, "허용되지 않은 출처의 요청입니다.")
+				c.Abort()
+				return
+			}
+			if !ValidCSRFToken(c, c.Query(CSRFQueryParam)) {
+				rejectCSRF(c)
+				return
+			}
+
+		case c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead || c.Request.Method == http.MethodOptions:
+			ensureCSRFToken(c, sess)
+
+		default:
+			// Origin 헤더를 보내지 않는 클라이언트는 토큰으로만 검증
+			if c.GetHeader("Origin") != "" && !OriginAllowed(c.Request) {
+				logger.Auth.Warnw("Rejected cross-origin request",
+					"ip", c.ClientIP(), "method", c.Request.Method, "path", path, "origin", c.GetHeader("Origin"))
+				c.String(http.StatusForbidden, "허용되지 않은 출처의 요청입니다.")
+				c.Abort()
+				return
+			}
+			token := c.GetHeader(csrfHeader)
+			if token == "" {
+				token = c.PostForm(csrfFormField)
+			}
+			if !ValidCSRFToken(c, token) {
+				rejectCSRF(c)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// OriginAllowed Origin 헤더가 허용된 출처인지 확인 (미설정 시 https://접속 호스트만 허용)
+func OriginAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	origin = u.Scheme + "://" + u.Host
+
+	allowed := config.Conf.Server.AllowedOrigins
+	if len(allowed) == 0 {
+		return strings.EqualFold(origin, "https://"+r.Host)
+	}
+	for _, a := range allowed {
+		if strings.EqualFold(origin, strings.TrimRight(a, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidCSRFToken 요청에 포함된 토큰이 세션의 토큰과 일치하는지 확인
+func ValidCSRFToken(c *gin.Context, token string) bool {
+	expected, _ := sessions.Default(c).Get(csrfSessionKey).(string)
+	if expected == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(token)) == 1
+}
+
+// RotateCSRFToken 새 CSRF 토큰 발급 (로그인 성공 시 호출, 세션 저장은 호출 측에서 수행)
+func RotateCSRFToken(c *gin.Context, sess sessions.Session) error {
+	token, err := newCSRFToken()
+	if err != nil {
+		return err
+	}
+	sess.Set(csrfSessionKey, token)
+	setCSRFCookie(c, token)
+	return nil
+}
+
+// ensureCSRFToken 세션에 토큰이 없으면 발급하고, 쿠키 값이 다르면 다시 설정
+func ensureCSRFToken(c *gin.Context, sess sessions.Session) {
+	token, _ := sess.Get(csrfSessionKey).(string)
+	if token == "" {
+		var err error
+		if token, err = newCSRFToken(); err != nil {
+			logger.Auth.Errorw("Failed to generate CSRF token", "ip", c.ClientIP(), "err", err)
+			return
+		}
+		sess.Set(csrfSessionKey, token)
+		if err := sess.Save(); err != nil {
+			logger.Auth.Errorw("Failed to save CSRF token", "ip", c.ClientIP(), "err", err)
+			return
+		}
+	}
+	if cookie, err := c.Cookie(csrfCookieName); err != nil || cookie != token {
+		setCSRFCookie(c, token)
+	}
+}
+
+// setCSRFCookie 스크립트가 요청 헤더에 담을 수 있도록 CSRF 토큰 쿠키 설정 (HttpOnly 미적용)
+func setCSRFCookie(c *gin.Context, token string) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     csrfCookieName,
+		Value:    token,
+		Path:     "/",
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// newCSRFToken 임의 CSRF 토큰 생성 (256비트)
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// rejectCSRF CSRF 토큰 검증 실패 응답
+func rejectCSRF(c *gin.Context) {
+	logger.Auth.Warnw("CSRF token mismatch", "ip", c.ClientIP(), "method", c.Request.Method, "path", c.Request.URL.Path)
+	c.String(http.StatusForbidden, "요청이 만료되었거나 올바르지 않습니다. 페이지를 새로고침한 뒤 다시 시도하세요.")
+	c.Abort()
+}
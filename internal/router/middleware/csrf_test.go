@@ -0,0 +1,216 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-contrib/sessions/cookie"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "rootweb-middleware")
+	if err != nil {
+		panic(err)
+	}
+	logger.InitializeLogger(filepath.Join(dir, "test.log"), 1, 1, 1, false, false, logger.FormatConsole, "")
+	gin.SetMode(gin.TestMode)
+
+	code := m.Run()
+	logger.FinalizeLogger()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// newCSRFTestServer 세션 및 CSRF 미들웨어만 등록한 테스트 서버 생성
+func newCSRFTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	upgrader := websocket.Upgrader{CheckOrigin: OriginAllowed}
+	r := gin.New()
+	r.Use(sessions.Sessions("rootweb_sess", cookie.NewStore([]byte("0123456789abcdef0123456789abcdef"))))
+	r.Use(CSRFProtect())
+	r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.POST("/api", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/ws", func(c *gin.Context) {
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return
+		}
+		conn.Close()
+	})
+
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// csrfSession 페이지를 조회해 세션 쿠키와 발급된 CSRF 토큰 반환
+func csrfSession(t *testing.T, srv *httptest.Server) (string, string) {
+	t.Helper()
+
+	resp, err := http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatalf("GET /: %v", err)
+	}
+	resp.Body.Close()
+
+	var cookies []string
+	var token string
+	for _, ck := range resp.Cookies() {
+		cookies = append(cookies, ck.Name+"="+ck.Value)
+		if ck.Name == csrfCookieName {
+			token = ck.Value
+		}
+	}
+	if token == "" {
+		t.Fatalf("GET / did not issue a %s cookie", csrfCookieName)
+	}
+	return strings.Join(cookies, "; "), token
+}
+
+// dialWS 지정한 Origin과 토큰으로 웹소켓 핸드셰이크를 시도하고 응답 상태 코드 반환
+func dialWS(t *testing.T, srv *httptest.Server, cookies, origin, token string) int {
+	t.Helper()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	if token != "" {
+		wsURL += "?" + CSRFQueryParam + "=" + url.QueryEscape(token)
+	}
+	header := http.Header{}
+	header.Set("Cookie", cookies)
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+
+	conn, resp, err := websocket.DefaultDialer.Dial(wsURL, header)
+	if err == nil {
+		conn.Close()
+		return http.StatusSwitchingProtocols
+	}
+	if resp == nil {
+		t.Fatalf("dial %s: %v", wsURL, err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestWebSocketHandshakeOrigin(t *testing.T) {
+	config.Conf.Server.AllowedOrigins = nil
+	srv := newCSRFTestServer(t)
+	cookies, token := csrfSession(t, srv)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	tests := []struct {
+		name   string
+		origin string
+		token  string
+		want   int
+	}{
+		{"same origin with token", "https://" + host, token, http.StatusSwitchingProtocols},
+		{"cross origin", "https://evil.example.com", token, http.StatusForbidden},
+		{"cross origin with same host name", "https://" + host + ".evil.example.com", token, http.StatusForbidden},
+		{"plain http scheme", "http://" + host, token, http.StatusForbidden},
+		{"null origin", "null", token, http.StatusForbidden},
+		{"missing origin", "", token, http.StatusForbidden},
+		{"missing token", "https://" + host, "", http.StatusForbidden},
+		{"wrong token", "https://" + host, "not-the-token", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dialWS(t, srv, cookies, tt.origin, tt.token); got != tt.want {
+				t.Errorf("handshake status = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketHandshakeAllowedOrigins(t *testing.T) {
+	config.Conf.Server.AllowedOrigins = []string{"https://rootweb.example.com/"}
+	t.Cleanup(func() { config.Conf.Server.AllowedOrigins = nil })
+
+	srv := newCSRFTestServer(t)
+	cookies, token := csrfSession(t, srv)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	if got := dialWS(t, srv, cookies, "https://rootweb.example.com", token); got != http.StatusSwitchingProtocols {
+		t.Errorf("configured origin: handshake status = %d, want %d", got, http.StatusSwitchingProtocols)
+	}
+	// 허용 목록을 설정하면 접속 호스트 기반의 기본 출처는 더 이상 허용하지 않음
+	if got := dialWS(t, srv, cookies, "https://"+host, token); got != http.StatusForbidden {
+		t.Errorf("request host origin: handshake status = %d, want %d", got, http.StatusForbidden)
+	}
+}
+
+func TestStateChangingRequestToken(t *testing.T) {
+	config.Conf.Server.AllowedOrigins = nil
+	srv := newCSRFTestServer(t)
+	cookies, token := csrfSession(t, srv)
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	tests := []struct {
+		name   string
+		origin string
+		header string
+		form   string
+		want   int
+	}{
+		{"header token", "", token, "", http.StatusOK},
+		{"form token", "", "", token, http.StatusOK},
+		{"same origin", "https://" + host, token, "", http.StatusOK},
+		{"missing token", "", "", "", http.StatusForbidden},
+		{"wrong token", "", "not-the-token", "", http.StatusForbidden},
+		{"cross origin with token", "https://evil.example.com", token, "", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			form := url.Values{}
+			if tt.form != "" {
+				form.Set(csrfFormField, tt.form)
+			}
+			req, _ := http.NewRequest(http.MethodPost, srv.URL+"/api", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.Header.Set("Cookie", cookies)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.header != "" {
+				req.Header.Set(csrfHeader, tt.header)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("POST /api: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
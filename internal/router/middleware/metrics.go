@@ -0,0 +1,42 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/internal/metrics"
+)
+
+// Metrics 라우트별 요청 수 및 처리 시간을 메트릭으로 기록하는 미들웨어
+// 웹소켓 연결은 연결 유지 시간이 처리 시간으로 집계되지 않도록 요청 수만 기록
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		upgrade := websocket.IsWebSocketUpgrade(c.Request)
+
+		c.Next()
+
+		if upgrade {
+			metrics.CountHTTP(c.Request.Method, c.FullPath(), c.Writer.Status())
+			return
+		}
+		metrics.ObserveHTTP(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+	}
+}
@@ -23,11 +23,17 @@ import (
 
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/audit"
 	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
 )
 
 var AdminExists bool
 
+// 요청 컨텍스트에 로그인 사용자 정보를 저장하는 키
+const ctxUserKey = "rootweb_user"
+
 // EnsureAdminExists 관리자 계정 존재 여부 체크 미들웨어
 func EnsureAdminExists() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -41,7 +47,7 @@ func EnsureAdminExists() gin.HandlerFunc {
 		if !AdminExists {
 			// DB에서 관리자 계정 존재 여부 확인
 			var exists bool
-			err := db.SqliteDB.Model(&db.User{}).Select("1").Where("is_admin = ?", true).
+			err := db.SqliteDB.Model(&db.User{}).Select("1").Where("role = ? AND disabled = ?", db.RoleAdmin, false).
 				Limit(1).Find(&exists).Error
 			if err == nil && exists {
 				// 메모리 캐시 업데이트
@@ -68,10 +74,56 @@ func EnsureAdminExists() gin.HandlerFunc {
 	}
 }
 
+// SessionPolicy 로그인 세션 유지 정책
+type SessionPolicy struct {
+	IdleTimeout     time.Duration // 마지막 요청 후 세션 유지 시간
+	RefreshInterval time.Duration // 세션 유지 기간 갱신 간격
+	MaxLifetime     time.Duration // 활동과 관계없이 로그인 후 세션을 유지하는 최대 시간
+}
+
+// LoadSessionPolicy 설정 파일의 로그인 세션 유지 정책 로드 (미설정 항목은 기본값 사용)
+func LoadSessionPolicy() SessionPolicy {
+	conf := config.Conf.Auth
+	seconds := func(v, def int) time.Duration {
+		if v <= 0 {
+			v = def
+		}
+		return time.Duration(v) * time.Second
+	}
+
+	p := SessionPolicy{
+		IdleTimeout:     seconds(conf.IdleTimeout, 1800),
+		RefreshInterval: seconds(conf.RefreshInterval, 600),
+		MaxLifetime:     seconds(conf.MaxLifetime, 43200),
+	}
+	// 갱신 간격이 유지 시간보다 길면 활동 중에도 세션이 만료되므로 보정
+	if p.RefreshInterval >= p.IdleTimeout {
+		p.RefreshInterval = p.IdleTimeout / 3
+	}
+	return p
+}
+
+// KeepAliveInterval 열려 있는 페이지가 세션 유지를 위해 요청을 보낼 간격
+// 갱신 간격이 지난 뒤 첫 요청에서 갱신되므로 두 간격의 합이 유지 시간보다 짧아야 함
+func (p SessionPolicy) KeepAliveInterval() time.Duration {
+	return (p.IdleTimeout - p.RefreshInterval) / 2
+}
+
+// CookieOptions 세션 쿠키 옵션 (maxAge: 쿠키 및 서버 측 세션 유지 시간)
+func CookieOptions(maxAge time.Duration) sessions.Options {
+	return sessions.Options{
+		Path: "/",
+		// 0이면 브라우저 종료 시까지 유지되므로 최소 1초
+		MaxAge:   max(int(maxAge/time.Second), 1),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
 // RequireAuth 로그인 되어있는지 확인하는 미들웨어
 func RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		idleTimeout := 30 * time.Minute
 		path := c.Request.URL.Path
 
 		// 정적 리소스는 예외
@@ -82,58 +134,101 @@ func RequireAuth() gin.HandlerFunc {
 
 		// 예외 경로 체크
 		switch path {
-		case "/login", "/setup", "/logout", "/favicon.ico":
+		case "/login", "/setup", "/enroll", "/logout", "/favicon.ico",
+			"/login/passkey", "/login/passkey/begin", "/login/passkey/finish":
 			c.Next()
 			return
 		}
 
 		// 세션 검사
 		sess := sessions.Default(c)
-		if sess.Get("user_id") == nil {
+		userID := sess.Get("user_id")
+		if userID == nil {
 			c.Redirect(http.StatusFound, "/login")
 			c.Abort()
 			return
 		}
 
-		// last_seen 읽기
-		var lastSeenUnix int64
-		if v := sess.Get("last_seen"); v != nil {
-			switch t := v.(type) {
-			case int64:
-				lastSeenUnix = t
-			case int:
-				lastSeenUnix = int64(t)
-			case float64:
-				lastSeenUnix = int64(t)
-			}
-		}
+		// last_seen, login_at 읽기
+		lastSeenUnix := sessionUnix(sess, "last_seen")
+		loginAtUnix := sessionUnix(sess, "login_at")
 
+		policy := LoadSessionPolicy()
 		now := time.Now()
 		lastSeen := time.Unix(lastSeenUnix, 0)
+		expiresAt := time.Unix(loginAtUnix, 0).Add(policy.MaxLifetime)
 
-		// last_seen 없으면 비정상 세션으로 보고 재로그인 유도
-		// IDLE 타임아웃 체크
-		if lastSeenUnix == 0 || now.Sub(lastSeen) > idleTimeout {
-			sess.Clear()
-			sess.Options(sessions.Options{Path: "/", MaxAge: -1})
-			_ = sess.Save()
-			c.Redirect(http.StatusFound, "/login")
-			c.Abort()
+		// last_seen 또는 login_at이 없으면 비정상 세션으로 보고 재로그인 유도
+		// IDLE 타임아웃 및 최대 유지 시간 체크
+		switch {
+		case lastSeenUnix == 0 || loginAtUnix == 0:
+			expireSession(c, sess, "invalid")
+			return
+		case now.Sub(lastSeen) > policy.IdleTimeout:
+			expireSession(c, sess, "idle")
+			return
+		case !now.Before(expiresAt):
+			expireSession(c, sess, "lifetime")
+			return
+		}
+
+		// 사용자 정보 및 권한 등급 로드 (삭제 또는 비활성화된 계정은 즉시 로그아웃)
+		var user db.User
+		if err := db.SqliteDB.First(&user, userID).Error; err != nil || user.Disabled {
+			expireSession(c, sess, "user_disabled")
 			return
 		}
+		c.Set(ctxUserKey, &user)
 
-		// 10분 마다 세션 유지 기간 갱신
-		if now.Sub(lastSeen) >= 10*time.Minute {
+		// 이 요청에서 세션을 저장하더라도 최대 유지 시간을 넘겨 연장되지 않도록 제한
+		sess.Options(CookieOptions(min(policy.IdleTimeout, expiresAt.Sub(now))))
+
+		// 갱신 간격마다 세션 유지 기간 갱신
+		if now.Sub(lastSeen) >= policy.RefreshInterval {
 			sess.Set("last_seen", now.Unix())
-			sess.Options(sessions.Options{
-				Path:     "/",
-				MaxAge:   int(idleTimeout.Seconds()),
-				HttpOnly: true,
-				Secure:   true,
-				SameSite: http.SameSiteLaxMode,
-			})
 			_ = sess.Save()
 		}
 		c.Next()
 	}
 }
+
+// RequireRole 최소 권한 등급 확인 미들웨어 (RequireAuth 이후에 동작해야 함)
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := CurrentUser(c)
+		if user == nil || !user.HasRole(role) {
+			c.String(http.StatusForbidden, "접근 권한이 없습니다.")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentUser RequireAuth에서 로드한 로그인 사용자 정보 반환
+func CurrentUser(c *gin.Context) *db.User {
+	if v, ok := c.Get(ctxUserKey); ok {
+		if user, ok := v.(*db.User); ok {
+			return user
+		}
+	}
+	return nil
+}
+
+// expireSession 세션을 제거하고 로그인 페이지로 이동 (reason: 감사 로그에 기록할 만료 사유)
+func expireSession(c *gin.Context, sess sessions.Session, reason string) {
+	userID, _ := sess.Get("user_id").(uint)
+	audit.Record(audit.Event{
+		Type:     audit.TypeSessionExpire,
+		UserID:   userID,
+		ClientIP: c.ClientIP(),
+		Target:   sessionstore.Handle(sess.ID()),
+		Details:  map[string]interface{}{"reason": reason},
+	})
+
+	sess.Clear()
+	sess.Options(sessions.Options{Path: "/", MaxAge: -1})
+	_ = sess.Save()
+	c.Redirect(http.StatusFound, "/login")
+	c.Abort()
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package middleware
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
+)
+
+// StepUpHeader 재인증이 필요해 거부된 요청임을 알리는 응답 헤더 (값: required)
+const StepUpHeader = "X-Step-Up"
+
+// StepUpPath OTP 재인증 페이지 경로
+const StepUpPath = "/account/verify"
+
+// StepUpMaxAge 재인증 없이 민감한 작업을 허용하는 OTP 인증 후 경과 시간 (미설정 시 15분)
+func StepUpMaxAge() time.Duration {
+	if sec := config.Conf.StepUp.MaxAge; sec > 0 {
+		return time.Duration(sec) * time.Second
+	}
+	return 15 * time.Minute
+}
+
+// OTPVerifiedAt 로그인 세션에서 마지막으로 OTP를 인증한 시각 (인증 기록이 없으면 zero time)
+func OTPVerifiedAt(c *gin.Context) time.Time {
+	verifiedAt := sessionUnix(sessions.Default(c), "otp_verified_at")
+	if verifiedAt == 0 {
+		return time.Time{}
+	}
+	return time.Unix(verifiedAt, 0)
+}
+
+// StepUpExpiresAt 마지막 OTP 인증의 재인증 만료 시각 (인증 기록이 없으면 zero time)
+func StepUpExpiresAt(c *gin.Context) time.Time {
+	verifiedAt := OTPVerifiedAt(c)
+	if verifiedAt.IsZero() {
+		return time.Time{}
+	}
+	return verifiedAt.Add(StepUpMaxAge())
+}
+
+// StoredStepUpExpiresAt 저장소에서 다시 읽은 로그인 세션의 재인증 만료 시각 (인증 기록이 없으면 zero time)
+// 웹소켓 연결은 연결 시점의 세션 값을 계속 사용하므로, 연결 이후 다른 창에서 마친 재인증을 반영할 때 사용
+func StoredStepUpExpiresAt(c *gin.Context) (time.Time, error) {
+	values, err := sessionstore.Values(sessions.Default(c).ID())
+	if err != nil {
+		return time.Time{}, err
+	}
+	verifiedAt := unixValue(values["otp_verified_at"])
+	if verifiedAt == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(verifiedAt, 0).Add(StepUpMaxAge()), nil
+}
+
+// StepUpRequired 재인증이 필요한 상태인지 확인 (재인증 비활성화 시 항상 false)
+func StepUpRequired(c *gin.Context) bool {
+	if !config.Conf.StepUp.Enabled {
+		return false
+	}
+	return !time.Now().Before(StepUpExpiresAt(c))
+}
+
+// RequireStepUp 최근 OTP 인증 여부 확인 미들웨어 (RequireAuth 이후에 동작해야 함)
+// 페이지 요청은 재인증 페이지로 이동하고, API 및 웹소켓 요청은 403으로 거부
+func RequireStepUp() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !StepUpRequired(c) {
+			c.Next()
+			return
+		}
+
+		if user := CurrentUser(c); user != nil {
+			logger.Auth.Infow("Step-up verification required",
+				"user", user.Username, "ip", c.ClientIP(), "path", c.Request.URL.Path)
+		}
+
+		c.Header(StepUpHeader, "required")
+		switch {
+		case websocket.IsWebSocketUpgrade(c.Request):
+			c.String(http.StatusForbidden, "OTP 재인증이 필요합니다.")
+		case c.Request.Method == http.MethodGet && !strings.HasPrefix(c.Request.URL.Path, "/api/"):
+			c.Redirect(http.StatusFound, StepUpPath+"?next="+url.QueryEscape(c.Request.URL.RequestURI()))
+		default:
+			c.JSON(http.StatusForbidden, gin.H{"error": "OTP 재인증이 필요합니다.", "stepUp": true})
+		}
+		c.Abort()
+	}
+}
+
+// sessionUnix 세션에 저장된 Unix 시각 읽기 (값이 없으면 0)
+func sessionUnix(sess sessions.Session, key string) int64 {
+	return unixValue(sess.Get(key))
+}
+
+// unixValue 세션 값으로 저장된 Unix 시각 변환 (값이 없거나 숫자가 아니면 0)
+func unixValue(v interface{}) int64 {
+	switch t := v.(type) {
+	case int64:
+		return t
+	case int:
+		return int64(t)
+	case float64:
+		return int64(t)
+	}
+	return 0
+}
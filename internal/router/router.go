@@ -17,18 +17,19 @@
 package router
 
 import (
-	"net/http"
-
 	"github.com/gin-contrib/sessions"
-	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
 	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
 	"github.com/hoon-x/rootweb/internal/router/handler"
 	"github.com/hoon-x/rootweb/internal/router/middleware"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
 )
 
 // NewGinRouterEngine gin 프레임워크 엔진 생성
-func NewGinRouterEngine() *gin.Engine {
+// sessionKeys: 세션 쿠키 서명/암호화 키 쌍 (현재 키가 먼저 위치, 이후 키는 기존 세션 검증에만 사용)
+func NewGinRouterEngine(sessionKeys [][]byte) *gin.Engine {
 	// gin 동작 모드 설정
 	gin.SetMode(func() string {
 		if config.RunConf.Debug {
@@ -37,31 +38,41 @@ func NewGinRouterEngine() *gin.Engine {
 		return gin.ReleaseMode
 	}())
 
-	// 쿠키 세션 설정
-	store := cookie.NewStore([]byte("rB9xQ7KfA2mW4ZP8EJcD6VtY5SgHnU3L"))
-	store.Options(sessions.Options{
-		Path:     "/",
-		MaxAge:   1800,
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteLaxMode,
-	})
+	// 세션 저장소 설정 (세션 값은 DB에 저장하고 쿠키에는 서명된 세션 ID만 저장)
+	store := sessionstore.NewStore(sessionKeys...)
+	store.Options(middleware.CookieOptions(middleware.LoadSessionPolicy().IdleTimeout))
 
 	// gin 라우터 생성
 	r := gin.New()
+	// 설정된 리버스 프록시가 보낸 경우에만 X-Forwarded-For 헤더로 접속 IP 판별
+	if err := r.SetTrustedProxies(config.Conf.Server.TrustedProxies); err != nil {
+		logger.Server.Errorw("Invalid trusted proxies", "proxies", config.Conf.Server.TrustedProxies, "err", err)
+		r.SetTrustedProxies(nil)
+	}
 
 	// HTML 템플릿 및 정적 리소스 설정
 	r.LoadHTMLGlob("assets/templates/*.html")
 	r.Static("/static", "./assets/static")
+	// 메트릭 조회 핸들러 (세션 및 로그인 확인 미들웨어 없이 자체 접근 제어 적용)
+	if config.Conf.Metrics.Enabled && config.Conf.Metrics.Port == 0 {
+		r.GET("/metrics", handler.Metrics)
+	}
 
 	// [미들웨어 정의]
-	// TODO: 로그가 모듈 로그로 기록되도록 인터페이스 구현 필요
-	r.Use(gin.Logger())
+	// 요청 ID 부여 및 접근 로그 기록 미들웨어 등록
+	r.Use(middleware.AccessLog())
+	// 라우트별 요청 수 및 처리 시간 측정 미들웨어 등록
+	if config.Conf.Metrics.Enabled {
+		r.Use(middleware.Metrics())
+	}
 	r.Use(gin.Recovery())
 	// 모든 접속 시 관리자 존재 여부 확인 미들웨어 등록
 	r.Use(middleware.EnsureAdminExists())
-	// 쿠키 세션 미들웨어 등록
+	// 세션 미들웨어 등록 (세션에 기록할 접속 IP를 먼저 판별)
+	r.Use(sessionstore.ClientIP())
 	r.Use(sessions.Sessions("rootweb_sess", store))
+	// Origin 및 CSRF 토큰 검증 미들웨어 등록
+	r.Use(middleware.CSRFProtect())
 	// 로그인 여부 확인 미들웨어 등록
 	r.Use(middleware.RequireAuth())
 
@@ -73,11 +84,97 @@ func NewGinRouterEngine() *gin.Engine {
 	r.GET("/login", handler.HtmlLogin)
 	r.POST("/login", handler.Login)
 	r.GET("/logout", handler.Logout)
-	// 터미널 처리 핸들러
-	r.GET("/terminal", handler.HtmlTerminal)
-	r.GET("/terminal/ws", handler.TerminalWS)
+	// 패스키 2단계 인증 핸들러 (비밀번호 인증 후 진행)
+	r.GET("/login/passkey", handler.HtmlPasskeyLogin)
+	r.POST("/login/passkey/begin", handler.BeginPasskeyLogin)
+	r.POST("/login/passkey/finish", handler.FinishPasskeyLogin)
+	// 최초 로그인 사용자 OTP 등록 핸들러
+	r.GET("/enroll", handler.HtmlEnroll)
+	r.POST("/enroll", handler.EnrollOTP)
 	r.GET("/ping", handler.Ping)
 	// 메인 페이지 핸들러
 	r.GET("/", handler.HtmlIndex)
+	// 본인 OTP 재등록 및 복구 코드 관리 핸들러
+	r.GET("/account/otp", handler.HtmlAccountOTP)
+	r.POST("/api/account/otp", handler.RotateOTP)
+	r.POST("/api/account/recovery-codes", handler.RegenerateRecoveryCodes)
+	// 민감한 작업 전 OTP 재인증 핸들러
+	r.GET("/account/verify", handler.HtmlStepUp)
+	r.POST("/account/verify", handler.VerifyStepUp)
+	r.GET("/api/account/step-up", handler.StepUpStatus)
+	// 본인 패스키 관리 핸들러 (등록 및 삭제는 OTP 재인증 필요)
+	r.GET("/account/passkeys", handler.HtmlPasskeys)
+	r.GET("/api/passkeys", handler.ListPasskeys)
+	r.POST("/api/passkeys/register/begin", middleware.RequireStepUp(), handler.BeginPasskeyRegistration)
+	r.POST("/api/passkeys/register/finish", handler.FinishPasskeyRegistration)
+	r.DELETE("/api/passkeys/:id", middleware.RequireStepUp(), handler.DeletePasskey)
+	// 세션 녹화 핸들러 (관리자 외에는 본인 녹화만 조회 가능)
+	r.GET("/recordings", handler.HtmlRecordings)
+	r.GET("/recordings/:id", handler.HtmlReplay)
+	r.GET("/recordings/:id/ws", handler.ReplayWS)
+	r.GET("/recordings/:id/download", handler.DownloadRecording)
+	// 공유 터미널 참여 핸들러 (초대 링크를 받은 모든 사용자, 입력 승인은 OTP 재인증을 마친 운영자 이상만 가능)
+	r.GET("/terminal/join/:token", handler.HtmlJoinTerminal)
+	r.GET("/terminal/join/:token/ws", handler.JoinTerminalWS)
+
+	// [운영자 이상 권한 라우트]
+	operator := r.Group("/", middleware.RequireRole(db.RoleOperator))
+	// 터미널 처리 핸들러 (터미널 열기는 OTP 재인증 필요)
+	operator.GET("/terminal", middleware.RequireStepUp(), handler.HtmlTerminal)
+	operator.GET("/terminal/ws", middleware.RequireStepUp(), handler.TerminalWS)
+	operator.GET("/terminal/sessions", handler.HtmlTerminalSessions)
+	operator.GET("/api/terminal/sessions", handler.ListTerminalSessions)
+	operator.DELETE("/api/terminal/sessions/:id", handler.TerminateTerminalSession)
+	operator.POST("/api/terminal/sessions/:id/invites", handler.CreateTerminalInvite)
+	operator.DELETE("/api/terminal/sessions/:id/invites", handler.RevokeTerminalInvites)
+	// 파일 관리자 핸들러 (매핑된 리눅스 계정 권한으로 동작, OTP 재인증 필요)
+	if config.Conf.Files.Enabled {
+		files := operator.Group("/", middleware.RequireStepUp())
+		files.GET("/files", handler.HtmlFiles)
+		files.GET("/api/files", handler.ListFiles)
+		files.DELETE("/api/files", handler.DeleteFile)
+		files.GET("/api/files/download", handler.DownloadFile)
+		files.GET("/api/files/upload", handler.UploadStatus)
+		files.PUT("/api/files/upload", handler.UploadChunk)
+		files.DELETE("/api/files/upload", handler.CancelUpload)
+		files.POST("/api/files/upload/complete", handler.CompleteUpload)
+		files.POST("/api/files/mkdir", handler.MakeDir)
+		files.POST("/api/files/rename", handler.RenameFile)
+		files.POST("/api/files/chmod", handler.ChmodFile)
+	}
+	// 저장된 명령 핸들러 (터미널 전송 및 실행은 OTP 재인증 필요)
+	operator.GET("/snippets", handler.HtmlSnippets)
+	operator.GET("/api/snippets", handler.ListSnippets)
+	operator.POST("/api/snippets", handler.CreateSnippet)
+	operator.PATCH("/api/snippets/:id", handler.UpdateSnippet)
+	operator.DELETE("/api/snippets/:id", handler.DeleteSnippet)
+	operator.POST("/api/snippets/:id/render", handler.RenderSnippet)
+	operator.POST("/api/snippets/:id/send", middleware.RequireStepUp(), handler.SendSnippet)
+	operator.POST("/api/snippets/:id/run", middleware.RequireStepUp(), handler.RunSnippet)
+	operator.GET("/api/snippet-runs", handler.ListSnippetRuns)
+	operator.GET("/api/snippet-runs/:id", handler.GetSnippetRun)
+	operator.POST("/api/snippet-runs/:id/cancel", handler.CancelSnippetRun)
+
+	// [관리자 전용 라우트]
+	admin := r.Group("/", middleware.RequireRole(db.RoleAdmin))
+	// 사용자 관리 핸들러 (계정 변경은 OTP 재인증 필요)
+	admin.GET("/users", handler.HtmlUsers)
+	admin.GET("/api/users", handler.ListUsers)
+	admin.POST("/api/users", middleware.RequireStepUp(), handler.CreateUser)
+	admin.PATCH("/api/users/:id", middleware.RequireStepUp(), handler.UpdateUser)
+	admin.DELETE("/api/users/:id", middleware.RequireStepUp(), handler.DeleteUser)
+	// 로그인 시도 제한 관리 핸들러
+	admin.GET("/login-attempts", handler.HtmlLoginAttempts)
+	admin.DELETE("/api/login-throttles/:id", handler.UnlockLoginThrottle)
+	// 로그인 세션 관리 핸들러
+	admin.GET("/login-sessions", handler.HtmlLoginSessions)
+	admin.DELETE("/api/login-sessions/:id", handler.RevokeLoginSession)
+	// 감사 로그 조회 및 해시 체인 검증 핸들러
+	admin.GET("/audit", handler.HtmlAudit)
+	admin.GET("/api/audit/events", handler.ListAuditEvents)
+	admin.GET("/api/audit/verify", handler.VerifyAuditLog)
+	// 로그 레벨 조회 및 변경 핸들러
+	admin.GET("/api/log/level", handler.GetLogLevel)
+	admin.PUT("/api/log/level", handler.SetLogLevel)
 	return r
 }
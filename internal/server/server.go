@@ -30,7 +30,12 @@ import (
 	"github.com/hoon-x/rootweb/internal/db"
 	"github.com/hoon-x/rootweb/internal/ipc"
 	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/metrics"
 	"github.com/hoon-x/rootweb/internal/router"
+	"github.com/hoon-x/rootweb/internal/router/handler"
+	"github.com/hoon-x/rootweb/internal/sessionstore"
+	"github.com/hoon-x/rootweb/internal/snippet"
+	"github.com/hoon-x/rootweb/internal/terminal"
 	"github.com/hoon-x/rootweb/pkg/cert"
 	"github.com/hoon-x/rootweb/pkg/file"
 )
@@ -51,22 +56,49 @@ func Run(ctx context.Context) {
 
 	// DB 경로 생성
 	if err := os.MkdirAll(filepath.Dir(config.Conf.DB.DBPath), 0700); err != nil {
-		logger.LogError("Failed to create directory (%s): %v", filepath.Dir(config.Conf.DB.DBPath), err)
+		logger.Server.Errorw("Failed to create directory", "path", filepath.Dir(config.Conf.DB.DBPath), "err", err)
 		return
 	}
 
 	// DB 초기화
 	if err := db.InitSqliteDB(config.Conf.DB.DBPath); err != nil {
-		logger.LogError("Failed to initialize DB: %v", err)
+		logger.Server.Errorw("Failed to initialize DB", "err", err)
 		return
 	}
 	defer db.CloseSqliteDB()
 
+	// 터미널 세션 관리 시작 (DB 종료 전에 모든 세션의 녹화 정보가 기록되도록 대기)
+	var bgWg sync.WaitGroup
+	defer bgWg.Wait()
+	bgWg.Add(4)
+	go func() {
+		defer bgWg.Done()
+		terminal.Run(ctx)
+	}()
+
+	// 저장된 명령 실행 관리 시작 (종료 시 실행 중인 명령을 종료하고 결과 기록)
+	go func() {
+		defer bgWg.Done()
+		snippet.Run(ctx)
+	}()
+
+	// 만료된 로그인 세션 정리 시작
+	go func() {
+		defer bgWg.Done()
+		sessionstore.Run(ctx)
+	}()
+
+	// 만료된 로그인 세션의 터미널 연결 해제 시작
+	go func() {
+		defer bgWg.Done()
+		handler.WatchLoginSessions(ctx)
+	}()
+
 	// TLS 인증서 파일이 없으면 새로 생성
 	if !file.IsFileExists(config.Conf.Server.TlsCertPath) || !file.IsFileExists(config.Conf.Server.TlsKeyPath) {
 		// TLS 인증서 파일 경로 생성
 		if err := os.MkdirAll(filepath.Dir(config.Conf.Server.TlsCertPath), 0700); err != nil {
-			logger.LogError("Failed to create directory (%s): %v", filepath.Dir(config.Conf.Server.TlsCertPath), err)
+			logger.Server.Errorw("Failed to create directory", "path", filepath.Dir(config.Conf.Server.TlsCertPath), "err", err)
 			return
 		}
 
@@ -74,15 +106,30 @@ func Run(ctx context.Context) {
 		err := cert.GenTLSCertificate(config.Conf.Server.TlsCertPath, config.Conf.Server.TlsKeyPath,
 			config.ModuleName, 365)
 		if err != nil {
-			logger.LogError("Failed to create TLS certificate: %v", err)
+			logger.Server.Errorw("Failed to create TLS certificate", "err", err)
 			return
 		}
 	}
 
+	// 메트릭 조회 접근 제어 설정
+	if config.Conf.Metrics.Enabled {
+		if err := metrics.Configure(config.Conf.Metrics.BearerToken, config.Conf.Metrics.AllowIPs); err != nil {
+			logger.Server.Errorw("Failed to configure metrics", "err", err)
+			return
+		}
+	}
+
+	// 세션 쿠키 키 로드 (키 파일이 없으면 새로 생성)
+	sessionKeys, err := sessionstore.LoadKeys()
+	if err != nil {
+		logger.Server.Errorw("Failed to load session keys", "err", err)
+		return
+	}
+
 	// TLS 인증서 로드
 	cert, err := tls.LoadX509KeyPair(config.Conf.Server.TlsCertPath, config.Conf.Server.TlsKeyPath)
 	if err != nil {
-		logger.LogError("Failed to load TLS certificate: %v", err)
+		logger.Server.Errorw("Failed to load TLS certificate", "err", err)
 		return
 	}
 
@@ -94,7 +141,7 @@ func Run(ctx context.Context) {
 	// 서버 설정
 	server := &http.Server{
 		Addr:           ":" + strconv.Itoa(config.Conf.Server.Port),
-		Handler:        router.NewGinRouterEngine(),
+		Handler:        router.NewGinRouterEngine(sessionKeys),
 		ReadTimeout:    10 * time.Second,
 		WriteTimeout:   10 * time.Second,
 		MaxHeaderBytes: 1 << 20,
@@ -105,11 +152,30 @@ func Run(ctx context.Context) {
 		// 서버 가동
 		err := server.ListenAndServeTLS("", "")
 		if err != nil && err != http.ErrServerClosed {
-			logger.LogError("Server error occurred: %v", err)
+			logger.Server.Errorw("Server error occurred", "err", err)
 			shutdown()
 		}
 	}()
 
+	// 별도 포트의 메트릭 서버 가동 (메트릭 서버 오류는 웹 서버를 종료하지 않음)
+	var metricsServer *http.Server
+	if config.Conf.Metrics.Enabled && config.Conf.Metrics.Port != 0 {
+		metricsServer = &http.Server{
+			Addr:           ":" + strconv.Itoa(config.Conf.Metrics.Port),
+			Handler:        metrics.StandaloneHandler(),
+			ReadTimeout:    10 * time.Second,
+			WriteTimeout:   10 * time.Second,
+			MaxHeaderBytes: 1 << 20,
+			TLSConfig:      &tlsConf,
+		}
+		go func() {
+			err := metricsServer.ListenAndServeTLS("", "")
+			if err != nil && err != http.ErrServerClosed {
+				logger.Server.Errorw("Metrics server error occurred", "err", err)
+			}
+		}()
+	}
+
 	// 종료 이벤트 감지
 	<-ctx.Done()
 
@@ -120,6 +186,11 @@ func Run(ctx context.Context) {
 	// 서버 종료
 	err = server.Shutdown(shutdownCtx)
 	if err != nil {
-		logger.LogWarn("Failed to shutdown server: %v", err)
+		logger.Server.Warnw("Failed to shutdown server", "err", err)
+	}
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			logger.Server.Warnw("Failed to shutdown metrics server", "err", err)
+		}
 	}
 }
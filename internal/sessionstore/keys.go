@@ -0,0 +1,212 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sessionstore
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// 키 파일에 보관하는 키 쌍 개수 (현재 키 + 이전 키)
+const maxKeyPairs = 2
+
+// 키 파일 경로 미설정 시 기본값
+const defaultKeyFile = "cert/session.keys"
+
+var ErrKeysInConfig = errors.New("session keys are set in the config file")
+
+// keyPair 세션 쿠키 서명/암호화 키 쌍
+type keyPair struct {
+	AuthKey       []byte    `json:"authKey"`
+	EncryptionKey []byte    `json:"encryptionKey"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// keyFile 키 파일 형식 (첫 번째 항목이 현재 키)
+type keyFile struct {
+	Keys []keyPair `json:"keys"`
+}
+
+// newKeyPair 임의 키 쌍 생성 (서명 64바이트, AES-256 암호화 32바이트)
+func newKeyPair() (keyPair, error) {
+	kp := keyPair{
+		AuthKey:       make([]byte, 64),
+		EncryptionKey: make([]byte, 32),
+		CreatedAt:     time.Now(),
+	}
+	if _, err := rand.Read(kp.AuthKey); err != nil {
+		return kp, err
+	}
+	if _, err := rand.Read(kp.EncryptionKey); err != nil {
+		return kp, err
+	}
+	return kp, nil
+}
+
+// validate 키 길이 검증
+func (kp keyPair) validate() error {
+	if len(kp.AuthKey) < 32 {
+		return errors.New("auth key must be at least 32 bytes")
+	}
+	switch len(kp.EncryptionKey) {
+	case 0, 16, 24, 32:
+		return nil
+	}
+	return errors.New("encryption key must be 16, 24 or 32 bytes")
+}
+
+// keyFilePath 설정된 키 파일 경로
+func keyFilePath() string {
+	if config.Conf.Session.KeyFile != "" {
+		return config.Conf.Session.KeyFile
+	}
+	return defaultKeyFile
+}
+
+// LoadKeys 세션 쿠키 키 로드 (NewStore 인자 형식, 현재 키가 먼저 위치)
+// 설정 파일에 키가 있으면 사용하고, 없으면 키 파일에서 읽음 (키 파일이 없으면 생성)
+func LoadKeys() ([][]byte, error) {
+	var pairs []keyPair
+	if len(config.Conf.Session.Keys) > 0 {
+		for i, k := range config.Conf.Session.Keys {
+			auth, err := base64.StdEncoding.DecodeString(k.AuthKey)
+			if err != nil {
+				return nil, fmt.Errorf("session key #%d: invalid auth key: %w", i+1, err)
+			}
+			enc, err := base64.StdEncoding.DecodeString(k.EncryptionKey)
+			if err != nil {
+				return nil, fmt.Errorf("session key #%d: invalid encryption key: %w", i+1, err)
+			}
+			pairs = append(pairs, keyPair{AuthKey: auth, EncryptionKey: enc})
+		}
+	} else {
+		kf, err := readKeyFile(keyFilePath())
+		if errors.Is(err, os.ErrNotExist) {
+			kf, err = createKeyFile(keyFilePath())
+		}
+		if err != nil {
+			return nil, err
+		}
+		pairs = kf.Keys
+	}
+
+	keys := make([][]byte, 0, len(pairs)*2)
+	for i, kp := range pairs {
+		if err := kp.validate(); err != nil {
+			return nil, fmt.Errorf("session key #%d: %w", i+1, err)
+		}
+		keys = append(keys, kp.AuthKey, kp.EncryptionKey)
+	}
+	return keys, nil
+}
+
+// RotateKeys 새 키 쌍을 생성하여 현재 키로 등록하고 기존 현재 키는 이전 키로 보관
+// 실행 중인 서버에는 재시작 후 적용됨
+func RotateKeys() error {
+	if len(config.Conf.Session.Keys) > 0 {
+		return ErrKeysInConfig
+	}
+
+	path := keyFilePath()
+	kf, err := readKeyFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	kp, err := newKeyPair()
+	if err != nil {
+		return err
+	}
+	kf.Keys = append([]keyPair{kp}, kf.Keys...)
+	if len(kf.Keys) > maxKeyPairs {
+		kf.Keys = kf.Keys[:maxKeyPairs]
+	}
+	return writeKeyFile(path, kf)
+}
+
+// readKeyFile 키 파일 읽기 (다른 사용자가 읽을 수 있는 권한이면 0600으로 변경)
+func readKeyFile(path string) (keyFile, error) {
+	var kf keyFile
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return kf, err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		logger.Auth.Warnw("Session key file is accessible by other users, restricting permissions", "path", path, "mode", info.Mode().Perm())
+		if err := os.Chmod(path, 0600); err != nil {
+			return kf, err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return kf, err
+	}
+	if err := json.Unmarshal(data, &kf); err != nil {
+		return kf, fmt.Errorf("invalid session key file (%s): %w", path, err)
+	}
+	if len(kf.Keys) == 0 {
+		return kf, fmt.Errorf("no session keys in %s", path)
+	}
+	return kf, nil
+}
+
+// createKeyFile 최초 실행 시 새 키 파일 생성
+func createKeyFile(path string) (keyFile, error) {
+	kp, err := newKeyPair()
+	if err != nil {
+		return keyFile{}, err
+	}
+	kf := keyFile{Keys: []keyPair{kp}}
+	if err := writeKeyFile(path, kf); err != nil {
+		return kf, err
+	}
+	logger.Auth.Infow("Session key file created", "path", path)
+	return kf, nil
+}
+
+// writeKeyFile 임시 파일에 쓴 뒤 교체하여 키 파일 저장 (권한 0600)
+func writeKeyFile(path string, kf keyFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(kf, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
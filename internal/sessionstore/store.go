@@ -0,0 +1,342 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package sessionstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/gob"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sessions"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/securecookie"
+	gsessions "github.com/gorilla/sessions"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"gorm.io/gorm"
+)
+
+// 쿠키 MaxAge가 0(브라우저 종료 시 삭제)인 세션의 서버 측 유지 시간
+const defaultTTL = 24 * time.Hour
+
+// 마지막 접속 시각 갱신 간격 (요청마다 DB에 쓰지 않도록 함)
+const touchInterval = time.Minute
+
+// 만료된 세션 정리 주기
+const cleanupInterval = 10 * time.Minute
+
+// 로그인 사용자 ID가 저장되는 세션 키
+const userIDKey = "user_id"
+
+// 요청 컨텍스트에 접속 IP를 저장하는 키
+type clientIPKey struct{}
+
+// Store SQLite에 세션 값을 저장하는 세션 저장소 (쿠키에는 서명된 세션 ID만 저장)
+type Store struct {
+	codecs  []securecookie.Codec
+	options *gsessions.Options
+}
+
+// NewStore 세션 저장소 생성 (쿠키 세션 저장소와 동일하게 서명/암호화 키 쌍 사용)
+func NewStore(keyPairs ...[]byte) *Store {
+	return &Store{
+		codecs:  securecookie.CodecsFromPairs(keyPairs...),
+		options: &gsessions.Options{Path: "/", MaxAge: 86400 * 30},
+	}
+}
+
+// Options 세션 쿠키 옵션 설정
+func (s *Store) Options(options sessions.Options) {
+	s.options = options.ToGorillaOptions()
+}
+
+// Get 요청 단위로 캐시된 세션 반환
+func (s *Store) Get(r *http.Request, name string) (*gsessions.Session, error) {
+	return gsessions.GetRegistry(r).Get(s, name)
+}
+
+// New 쿠키의 세션 ID로 저장된 세션 로드 (없거나 만료되었으면 빈 세션 반환)
+func (s *Store) New(r *http.Request, name string) (*gsessions.Session, error) {
+	session := gsessions.NewSession(s, name)
+	opts := *s.options
+	session.Options = &opts
+	session.IsNew = true
+
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return session, nil
+	}
+
+	// 서명이 올바르지 않은 쿠키는 무시 (새 세션으로 처리)
+	var id string
+	if err := securecookie.DecodeMulti(name, cookie.Value, &id, s.codecs...); err != nil {
+		return session, nil
+	}
+
+	var row db.WebSession
+	if err := db.SqliteDB.First(&row, "id = ?", id).Error; err != nil {
+		return session, nil
+	}
+	now := time.Now()
+	if now.After(row.ExpiresAt) {
+		deleteExpired(&row)
+		return session, nil
+	}
+	if err := gob.NewDecoder(bytes.NewReader(row.Data)).Decode(&session.Values); err != nil {
+		logger.Auth.Warnw("Failed to decode session data", "err", err)
+		return session, nil
+	}
+
+	session.ID = id
+	session.IsNew = false
+
+	// 마지막 접속 시각 및 IP 갱신
+	if now.Sub(row.LastSeenAt) >= touchInterval {
+		db.SqliteDB.Model(&row).Updates(map[string]interface{}{
+			"last_seen_at": now,
+			"client_ip":    clientIP(r),
+		})
+	}
+	return session, nil
+}
+
+// Save 세션 값을 DB에 저장하고 세션 ID 쿠키 발급 (MaxAge < 0 이면 세션 삭제)
+// 로그인 사용자가 바뀌면 세션 고정 공격을 막기 위해 새 세션 ID 발급
+func (s *Store) Save(r *http.Request, w http.ResponseWriter, session *gsessions.Session) error {
+	if session.Options.MaxAge < 0 {
+		if session.ID != "" {
+			if err := db.SqliteDB.Delete(&db.WebSession{ID: session.ID}).Error; err != nil {
+				return err
+			}
+		}
+		http.SetCookie(w, gsessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(session.Values); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	ttl := time.Duration(session.Options.MaxAge) * time.Second
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	userID, _ := session.Values[userIDKey].(uint)
+	row := db.WebSession{
+		ID:         session.ID,
+		UserID:     userID,
+		ClientIP:   clientIP(r),
+		UserAgent:  r.UserAgent(),
+		Data:       buf.Bytes(),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+
+	var revoked bool
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		if session.ID != "" {
+			var old db.WebSession
+			err := tx.Select("id", "user_id", "created_at").First(&old, "id = ?", session.ID).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				// 요청 처리 중 강제 종료된 세션은 되살리지 않음
+				revoked = true
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if old.UserID == userID {
+				row.CreatedAt = old.CreatedAt
+				return tx.Select("*").Omit("created_at").Updates(&row).Error
+			}
+			if err := tx.Delete(&old).Error; err != nil {
+				return err
+			}
+		}
+
+		id, err := newSessionID()
+		if err != nil {
+			return err
+		}
+		row.ID = id
+		return tx.Create(&row).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if revoked {
+		session.ID = ""
+		session.Options.MaxAge = -1
+		http.SetCookie(w, gsessions.NewCookie(session.Name(), "", session.Options))
+		return nil
+	}
+
+	session.ID = row.ID
+	encoded, err := securecookie.EncodeMulti(session.Name(), session.ID, s.codecs...)
+	if err != nil {
+		return err
+	}
+	http.SetCookie(w, gsessions.NewCookie(session.Name(), encoded, session.Options))
+	return nil
+}
+
+// newSessionID 임의 세션 ID 생성 (256비트)
+func newSessionID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b), nil
+}
+
+// Handle 세션 ID로 화면 및 감사 로그 노출용 식별자 생성 (세션 ID 자체는 노출하지 않음)
+func Handle(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:8])
+}
+
+// ClientIP 세션 저장 시 기록할 접속 IP를 요청 컨텍스트에 저장하는 미들웨어
+// 신뢰할 프록시 설정이 반영된 gin의 IP 판별 결과를 사용하기 위함 (세션 미들웨어보다 먼저 등록)
+func ClientIP() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), clientIPKey{}, c.ClientIP()))
+		c.Next()
+	}
+}
+
+// clientIP 요청 컨텍스트의 접속 IP 반환 (미들웨어 미등록 시 소켓 주소 사용)
+func clientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// Revoke 세션 강제 종료 (다음 요청부터 로그인 페이지로 이동)
+func Revoke(id string) error {
+	return db.SqliteDB.Delete(&db.WebSession{ID: id}).Error
+}
+
+// RevokeUser 사용자의 모든 세션 강제 종료 후 종료된 세션 ID 반환
+func RevokeUser(userID uint) ([]string, error) {
+	var ids []string
+	err := db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&db.WebSession{}).Where("user_id = ?", userID).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&db.WebSession{}).Error
+	})
+	return ids, err
+}
+
+// ExpiresAt 주어진 세션 ID 중 만료되거나 강제 종료되지 않은 세션의 만료 시각 조회
+func ExpiresAt(ids []string) (map[string]time.Time, error) {
+	var alive []db.WebSession
+	err := db.SqliteDB.Select("id", "expires_at").
+		Where("id IN ? AND expires_at > ?", ids, time.Now()).
+		Find(&alive).Error
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := make(map[string]time.Time, len(alive))
+	for _, s := range alive {
+		expiresAt[s.ID] = s.ExpiresAt
+	}
+	return expiresAt, nil
+}
+
+// Values 만료되지 않은 세션의 저장된 값 조회 (요청 없이 오래 유지되는 웹소켓 연결에서 변경된 값을 다시 읽을 때 사용)
+func Values(id string) (map[interface{}]interface{}, error) {
+	var row db.WebSession
+	if err := db.SqliteDB.First(&row, "id = ? AND expires_at > ?", id, time.Now()).Error; err != nil {
+		return nil, err
+	}
+	values := map[interface{}]interface{}{}
+	if err := gob.NewDecoder(bytes.NewReader(row.Data)).Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// deleteExpired 만료된 세션 삭제 (로그인 세션이었으면 만료 이벤트를 감사 로그에 기록)
+func deleteExpired(row *db.WebSession) {
+	res := db.SqliteDB.Delete(&db.WebSession{ID: row.ID})
+	if res.Error != nil {
+		logger.Auth.Errorw("Failed to delete expired session", "err", res.Error)
+		return
+	}
+	// 동시에 정리된 경우 한 번만 기록
+	if res.RowsAffected == 0 || row.UserID == 0 {
+		return
+	}
+	audit.Record(audit.Event{
+		Type:     audit.TypeSessionExpire,
+		UserID:   row.UserID,
+		ClientIP: row.ClientIP,
+		Target:   Handle(row.ID),
+		Details: map[string]interface{}{
+			"reason":     "timeout",
+			"loginAt":    row.CreatedAt,
+			"lastSeenAt": row.LastSeenAt,
+		},
+	})
+}
+
+// Run 만료된 세션을 주기적으로 정리
+func Run(ctx context.Context) {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		// 로그인 전 세션은 한 번에 삭제하고, 로그인 세션은 감사 로그에 남기기 위해 하나씩 삭제
+		if err := db.SqliteDB.Where("user_id = 0 AND expires_at < ?", now).Delete(&db.WebSession{}).Error; err != nil {
+			logger.Auth.Errorw("Failed to delete expired sessions", "err", err)
+		}
+		var expired []db.WebSession
+		if err := db.SqliteDB.Omit("data").Where("user_id > 0 AND expires_at < ?", now).Find(&expired).Error; err != nil {
+			logger.Auth.Errorw("Failed to query expired sessions", "err", err)
+		}
+		for i := range expired {
+			deleteExpired(&expired[i])
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,303 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package snippet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/internal/terminal"
+	"golang.org/x/sys/unix"
+	"gorm.io/gorm"
+)
+
+// 실행 상태
+const (
+	StatusRunning     = "running"
+	StatusSucceeded   = "succeeded"   // 종료 코드 0
+	StatusFailed      = "failed"      // 0이 아닌 종료 코드 또는 시그널로 종료
+	StatusTimeout     = "timeout"     // 최대 실행 시간 초과로 종료
+	StatusCanceled    = "canceled"    // 사용자가 실행 취소
+	StatusInterrupted = "interrupted" // 서버 종료로 중단
+)
+
+// 사용자당 동시에 실행할 수 있는 명령 개수
+const maxRunsPerUser = 4
+
+// 프로세스 종료 후 출력 파이프를 닫기까지 기다리는 시간 (백그라운드로 남은 자식 프로세스 대비)
+const waitDelay = 5 * time.Second
+
+var (
+	ErrTooManyRuns  = errors.New("too many snippets are running")
+	ErrShuttingDown = errors.New("server is shutting down")
+
+	errCanceled = errors.New("canceled by user")
+	errShutdown = errors.New("server shutdown")
+)
+
+// runner 실행 중인 명령 관리
+type runner struct {
+	mu      sync.Mutex
+	cancels map[uint]context.CancelCauseFunc // 실행 기록 ID -> 실행 취소 함수
+	users   map[uint]int                     // 사용자 ID -> 실행 중인 명령 개수
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+var runs = runner{
+	cancels: make(map[uint]context.CancelCauseFunc),
+	users:   make(map[uint]int),
+}
+
+// Run 이전 실행에서 완료되지 못한 기록을 중단 상태로 표시하고, 서버 종료 시 실행 중인 명령 종료
+func Run(ctx context.Context) {
+	err := db.SqliteDB.Model(&db.SnippetRun{}).
+		Where("status = ?", StatusRunning).
+		Updates(map[string]interface{}{"status": StatusInterrupted, "ended_at": time.Now()}).Error
+	if err != nil {
+		logger.PTY.Errorw("Failed to mark interrupted snippet runs", "err", err)
+	}
+
+	<-ctx.Done()
+
+	runs.mu.Lock()
+	runs.closed = true
+	for _, cancel := range runs.cancels {
+		cancel(errShutdown)
+	}
+	runs.mu.Unlock()
+	runs.wg.Wait()
+}
+
+// Start 사용자의 리눅스 계정으로 명령 실행 시작 (실행 기록을 저장하고 완료 결과는 백그라운드에서 기록)
+func Start(user *db.User, snip *db.Snippet, command, clientIP string) (*db.SnippetRun, error) {
+	acc, err := terminal.LookupUnixAccount(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := runs.acquire(user.ID); err != nil {
+		return nil, err
+	}
+
+	base, cancel := context.WithCancelCause(context.Background())
+	ctx, stop := context.WithTimeout(base, runTimeout())
+	release := func() {
+		stop()
+		cancel(nil)
+		runs.release(user.ID)
+	}
+
+	out := &outputBuffer{max: maxOutput()}
+	cmd := terminal.NewCommand(ctx, acc, command)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	// 쉘이 실행한 자식 프로세스까지 종료
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = waitDelay
+	if err := cmd.Start(); err != nil {
+		release()
+		return nil, err
+	}
+
+	run := &db.SnippetRun{
+		SnippetID:   snip.ID,
+		SnippetName: snip.Name,
+		UserID:      user.ID,
+		Username:    user.Username,
+		UnixUser:    acc.Name,
+		ClientIP:    clientIP,
+		Command:     command,
+		Status:      StatusRunning,
+		StartedAt:   time.Now(),
+	}
+	if err := createRun(run); err != nil {
+		cmd.Cancel()
+		cmd.Wait()
+		release()
+		return nil, err
+	}
+
+	runs.mu.Lock()
+	runs.cancels[run.ID] = cancel
+	if runs.closed {
+		cancel(errShutdown)
+	}
+	runs.mu.Unlock()
+
+	logger.PTY.Infow("Snippet run started",
+		"run", run.ID, "snippet", snip.Name, "snippetID", snip.ID, "user", user.Username, "unixUser", acc.Name, "pid", cmd.Process.Pid, "ip", clientIP)
+
+	go func() {
+		defer release()
+
+		err := cmd.Wait()
+		finishRun(run, cmd, out, err, context.Cause(ctx))
+
+		runs.mu.Lock()
+		delete(runs.cancels, run.ID)
+		runs.mu.Unlock()
+	}()
+	return run, nil
+}
+
+// Cancel 실행 중인 명령 취소 (실행 중이 아니면 false)
+func Cancel(runID uint) bool {
+	runs.mu.Lock()
+	defer runs.mu.Unlock()
+	cancel, ok := runs.cancels[runID]
+	if ok {
+		cancel(errCanceled)
+	}
+	return ok
+}
+
+// acquire 사용자의 실행 개수 확인 후 실행 자리 확보
+func (r *runner) acquire(userID uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return ErrShuttingDown
+	}
+	if r.users[userID] >= maxRunsPerUser {
+		return ErrTooManyRuns
+	}
+	r.users[userID]++
+	r.wg.Add(1)
+	return nil
+}
+
+// release 실행 자리 반환
+func (r *runner) release(userID uint) {
+	r.mu.Lock()
+	if r.users[userID]--; r.users[userID] <= 0 {
+		delete(r.users, userID)
+	}
+	r.mu.Unlock()
+	r.wg.Done()
+}
+
+// createRun 실행 기록 저장 후 보관 기간이 지난 기록 정리
+func createRun(run *db.SnippetRun) error {
+	retention := time.Duration(positive(config.Conf.Snippets.RunRetention, 30)) * 24 * time.Hour
+	return db.SqliteDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(run).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().
+			Where("status <> ? AND started_at < ?", StatusRunning, time.Now().Add(-retention)).
+			Delete(&db.SnippetRun{}).Error
+	})
+}
+
+// finishRun 종료 상태와 출력을 실행 기록에 저장
+func finishRun(run *db.SnippetRun, cmd *exec.Cmd, out *outputBuffer, waitErr, cause error) {
+	status := StatusSucceeded
+	var exitCode *int
+	var signal string
+	if state := cmd.ProcessState; state != nil {
+		if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			signal = unix.SignalName(ws.Signal())
+		} else {
+			code := state.ExitCode()
+			exitCode = &code
+		}
+	}
+	switch {
+	case errors.Is(cause, errCanceled):
+		status = StatusCanceled
+	case errors.Is(cause, errShutdown):
+		status = StatusInterrupted
+	case errors.Is(cause, context.DeadlineExceeded):
+		status = StatusTimeout
+	case waitErr != nil:
+		status = StatusFailed
+	}
+
+	output, truncated := out.result()
+	err := db.SqliteDB.Model(&db.SnippetRun{}).Where("id = ?", run.ID).Updates(map[string]interface{}{
+		"status":    status,
+		"exit_code": exitCode,
+		"signal":    signal,
+		"output":    output,
+		"truncated": truncated,
+		"ended_at":  time.Now(),
+	}).Error
+	if err != nil {
+		logger.PTY.Errorw("Failed to save snippet run", "run", run.ID, "err", err)
+	}
+
+	code := -1
+	if exitCode != nil {
+		code = *exitCode
+	}
+	logger.PTY.Infow("Snippet run finished",
+		"run", run.ID, "snippet", run.SnippetName, "snippetID", run.SnippetID, "user", run.Username,
+		"status", status, "exitCode", code, "signal", signal, "outputBytes", len(output))
+}
+
+// outputBuffer 최대 크기까지만 보관하는 출력 버퍼 (stdout, stderr 공용)
+type outputBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *outputBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(p)
+	if room := b.max - b.buf.Len(); room < len(p) {
+		p = p[:max(room, 0)]
+		b.truncated = true
+	}
+	b.buf.Write(p)
+	return n, nil
+}
+
+func (b *outputBuffer) result() (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String(), b.truncated
+}
+
+// runTimeout 비대화형 실행 최대 시간
+func runTimeout() time.Duration {
+	return time.Duration(positive(config.Conf.Snippets.RunTimeout, 300)) * time.Second
+}
+
+// maxOutput 실행 기록에 저장할 최대 출력 크기
+func maxOutput() int {
+	return positive(config.Conf.Snippets.MaxOutput, 256) * 1024
+}
+
+func positive(v, def int) int {
+	if v <= 0 {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package snippet
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// 명령 본문의 매개변수 표기 ({{이름}}, 이름은 영문자, 숫자, 밑줄로 구성)
+var paramPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+var (
+	ErrMissingParam = errors.New("missing parameter value")
+	ErrInvalidParam = errors.New("parameter value must not contain NUL characters")
+)
+
+// Params 명령에 사용된 매개변수 이름 (처음 나온 순서, 중복 제외)
+func Params(command string) []string {
+	params := []string{}
+	seen := map[string]bool{}
+	for _, m := range paramPattern.FindAllStringSubmatch(command, -1) {
+		if !seen[m[1]] {
+			seen[m[1]] = true
+			params = append(params, m[1])
+		}
+	}
+	return params
+}
+
+// Render 매개변수에 값을 넣은 명령 생성
+// 값은 작은따옴표로 감싸 쉘에서 하나의 인자로 취급되므로 명령 본문에서는 매개변수를 따옴표로 감싸지 않음
+func Render(command string, values map[string]string) (string, error) {
+	for _, name := range Params(command) {
+		v, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrMissingParam, name)
+		}
+		if strings.ContainsRune(v, 0) {
+			return "", fmt.Errorf("%w: %s", ErrInvalidParam, name)
+		}
+	}
+
+	return paramPattern.ReplaceAllStringFunc(command, func(m string) string {
+		return quote(values[paramPattern.FindStringSubmatch(m)[1]])
+	}), nil
+}
+
+// quote 쉘 작은따옴표 인용 (값 안의 작은따옴표는 따옴표를 닫고 이스케이프한 뒤 다시 열어 표현)
+func quote(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
@@ -0,0 +1,183 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// 입력 없음 시간 초과 시 처리 방식
+const (
+	IdleActionLock       = "lock"       // 모든 연결을 해제하고 OTP 재인증 후에만 재접속 허용 (쉘은 유지)
+	IdleActionDisconnect = "disconnect" // 세션 종료
+)
+
+// 경고 메시지 전송 시점 기본값
+const defaultWarnBefore = time.Minute
+
+var ErrSessionLocked = errors.New("session is locked due to inactivity")
+
+// limitPolicy 터미널 세션 사용 제한 정책
+type limitPolicy struct {
+	idleTimeout time.Duration // 키 입력 없이 유지하는 시간 (0이면 제한 없음)
+	idleAction  string        // 입력 없음 시간 초과 시 처리 방식
+	maxDuration time.Duration // 세션 최대 사용 시간 (0이면 제한 없음)
+	warnBefore  time.Duration // 잠금 또는 종료 전 경고 시점
+}
+
+// loadLimitPolicy 설정 파일의 터미널 세션 사용 제한 정책 로드
+func loadLimitPolicy() limitPolicy {
+	conf := config.Conf.Terminal
+	p := limitPolicy{
+		idleTimeout: time.Duration(max(conf.IdleTimeout, 0)) * time.Second,
+		idleAction:  conf.IdleAction,
+		maxDuration: time.Duration(max(conf.MaxDuration, 0)) * time.Second,
+		warnBefore:  time.Duration(conf.WarnBefore) * time.Second,
+	}
+	if p.idleAction != IdleActionDisconnect {
+		p.idleAction = IdleActionLock
+	}
+	if p.warnBefore <= 0 {
+		p.warnBefore = defaultWarnBefore
+	}
+	return p
+}
+
+// LockedAt 입력 없음으로 세션이 잠긴 시각 (잠기지 않았으면 zero)
+func (s *Session) LockedAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lockedAt
+}
+
+// Unlock 세션 잠금 해제 (OTP 재인증을 확인한 뒤 호출, clientIP: 재접속한 IP)
+func (s *Session) Unlock(clientIP string) {
+	s.mu.Lock()
+	lockedAt := s.lockedAt
+	if lockedAt.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.lockedAt = time.Time{}
+	s.touchInput()
+	s.mu.Unlock()
+
+	logger.PTY.Infow("Terminal session unlocked", "id", s.ID, "user", s.Username, "ip", clientIP)
+	audit.Record(audit.Event{
+		Type:     audit.TypeTerminalUnlock,
+		UserID:   s.UserID,
+		Username: s.Username,
+		ClientIP: clientIP,
+		Target:   s.ID,
+		Details:  map[string]interface{}{"lockedFor": int(time.Since(lockedAt) / time.Second)},
+	})
+}
+
+// touchInput 마지막 입력 시각 갱신 및 입력 없음 경고 초기화 (s.mu 잠금 상태에서 호출)
+func (s *Session) touchInput() {
+	s.lastInput = time.Now()
+	s.idleWarned = false
+}
+
+// enforceLimits 입력 없음 시간 및 최대 사용 시간 확인 후 경고, 잠금 또는 종료
+func (s *Session) enforceLimits(now time.Time, p limitPolicy) {
+	if p.maxDuration > 0 {
+		remain := s.StartedAt.Add(p.maxDuration).Sub(now)
+		if remain <= 0 {
+			s.recordLimit(audit.TypeTerminalLimit, ReasonMaxDuration, "Terminal session reached maximum duration", p.maxDuration)
+			s.Terminate(ReasonMaxDuration)
+			return
+		}
+		s.mu.Lock()
+		if remain <= p.warnBefore && !s.maxWarned {
+			s.maxWarned = true
+			s.broadcast(Event{Type: EventWarning, Reason: ReasonMaxDuration, Seconds: int(remain.Round(time.Second).Seconds())})
+		}
+		s.mu.Unlock()
+	}
+
+	if p.idleTimeout <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	if s.closed || !s.lockedAt.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	reason := ReasonIdle
+	if p.idleAction == IdleActionLock {
+		reason = ReasonLocked
+	}
+	remain := s.lastInput.Add(p.idleTimeout).Sub(now)
+	if remain > 0 {
+		if remain <= p.warnBefore && !s.idleWarned {
+			s.idleWarned = true
+			s.broadcast(Event{Type: EventWarning, Reason: reason, Seconds: int(remain.Round(time.Second).Seconds())})
+		}
+		s.mu.Unlock()
+		return
+	}
+
+	if reason == ReasonIdle {
+		s.mu.Unlock()
+		s.recordLimit(audit.TypeTerminalLimit, ReasonIdle, "Terminal session idle timeout", p.idleTimeout)
+		s.Terminate(ReasonIdle)
+		return
+	}
+
+	// 쉘은 유지한 채 모든 연결 해제 (분리 상태 유지 시간이 지나면 종료됨)
+	s.lockedAt = now
+	for cl := range s.clients {
+		cl.Close(ReasonLocked)
+	}
+	s.clients = map[Client]*participant{}
+	if s.owner != nil {
+		s.owner = nil
+		s.detachedAt = now
+		s.rejectUpload()
+	} else if s.detachedAt.IsZero() {
+		// 연결된 적 없는 세션도 잠금 후에는 유지 시간이 지나면 종료
+		s.detachedAt = now
+	}
+	s.mu.Unlock()
+	s.recordLimit(audit.TypeTerminalLock, ReasonLocked, "Terminal session locked", p.idleTimeout)
+}
+
+// recordLimit 사용 제한에 의한 조치를 로그, 녹화 파일 및 감사 로그에 기록
+func (s *Session) recordLimit(typ, reason, msg string, limit time.Duration) {
+	logger.PTY.Warnw(msg,
+		"id", s.ID, "user", s.Username, "unixUser", s.UnixUser, "ip", s.ClientIP, "reason", reason, "limit", limit)
+	s.rec.writeMarker(msg + " (" + limit.String() + ")")
+	audit.Record(audit.Event{
+		Type:     typ,
+		UserID:   s.UserID,
+		Username: s.Username,
+		ClientIP: s.ClientIP,
+		Target:   s.ID,
+		Details: map[string]interface{}{
+			"reason":   reason,
+			"unixUser": s.UnixUser,
+			"limit":    int(limit / time.Second),
+		},
+	})
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/pkg/asciicast"
+)
+
+// recorder 진행 중인 터미널 세션 녹화
+type recorder struct {
+	*asciicast.Writer
+	record db.Recording
+}
+
+// startRecording 터미널 세션 녹화 시작 (녹화 비활성화 시 nil 반환)
+// 파일 이름은 사용자 입력이 경로에 섞이지 않도록 시각과 세션 ID로만 구성
+func startRecording(sessionID string, user *db.User, clientIP, shell string, cols, rows int) (*recorder, error) {
+	if !config.Conf.Recording.Enabled {
+		return nil, nil
+	}
+
+	// 녹화 파일 경로 생성
+	dir := filepath.Join(config.Conf.Recording.Dir, time.Now().Format("2006-01-02"))
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	filePath := filepath.Join(dir, now.Format("150405")+"_"+sessionID+".cast")
+
+	// 녹화 파일 생성
+	writer, err := asciicast.NewWriter(filePath, asciicast.Header{
+		Width:     cols,
+		Height:    rows,
+		Timestamp: now.Unix(),
+		Title:     user.Username + "@" + clientIP,
+		Env:       map[string]string{"TERM": "xterm-256color", "SHELL": shell},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 녹화 정보 DB 등록
+	rec := &recorder{
+		Writer: writer,
+		record: db.Recording{
+			UserID:    user.ID,
+			Username:  user.Username,
+			ClientIP:  clientIP,
+			FilePath:  filePath,
+			StartedAt: now,
+		},
+	}
+	if err := db.SqliteDB.Create(&rec.record).Error; err != nil {
+		writer.Close()
+		os.Remove(filePath)
+		return nil, err
+	}
+
+	return rec, nil
+}
+
+// writeOutput 출력 녹화
+func (r *recorder) writeOutput(data []byte) error {
+	if r == nil {
+		return nil
+	}
+	return r.WriteOutput(data)
+}
+
+// writeInput 입력 녹화 (설정에서 허용한 경우만)
+func (r *recorder) writeInput(data []byte) {
+	if r == nil || !config.Conf.Recording.RecordInput {
+		return
+	}
+	r.WriteInput(data)
+}
+
+// writeResize 터미널 크기 변경 녹화
+func (r *recorder) writeResize(cols, rows int) {
+	if r == nil {
+		return
+	}
+	r.WriteResize(cols, rows)
+}
+
+// writeMarker 마커 녹화 (잠금, 종료 등 세션 제어 기록)
+func (r *recorder) writeMarker(label string) {
+	if r == nil {
+		return
+	}
+	r.WriteMarker(label)
+}
+
+// finish 녹화 종료 및 종료 정보 기록
+func (r *recorder) finish(state *os.ProcessState) {
+	if r == nil {
+		return
+	}
+
+	if err := r.Close(); err != nil {
+		logger.PTY.Warnw("Failed to close recording file", "path", r.record.FilePath, "err", err)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"ended_at": &now}
+	if state != nil {
+		updates["exit_status"] = exitStatus(state)
+	}
+	if err := db.SqliteDB.Model(&r.record).Updates(updates).Error; err != nil {
+		logger.PTY.Errorw("Failed to update recording", "recording", r.record.ID, "err", err)
+	}
+}
+
+// exitStatus 프로세스 종료 상태를 쉘 규칙의 종료 코드로 변환 (시그널 종료 시 128+signum)
+func exitStatus(state *os.ProcessState) int {
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return state.ExitCode()
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+// ringBuffer 최근 출력만 보관하는 고정 크기 순환 버퍼 (동기화는 호출 측에서 처리)
+type ringBuffer struct {
+	buf   []byte
+	start int
+	size  int
+}
+
+// newRingBuffer 순환 버퍼 생성
+func newRingBuffer(capacity int) *ringBuffer {
+	return &ringBuffer{buf: make([]byte, capacity)}
+}
+
+// Write 데이터 추가 (용량 초과 시 오래된 데이터부터 덮어씀)
+func (r *ringBuffer) Write(p []byte) {
+	capacity := len(r.buf)
+	if capacity == 0 {
+		return
+	}
+
+	// 용량보다 큰 데이터는 마지막 부분만 보관
+	if len(p) >= capacity {
+		copy(r.buf, p[len(p)-capacity:])
+		r.start = 0
+		r.size = capacity
+		return
+	}
+
+	end := (r.start + r.size) % capacity
+	n := copy(r.buf[end:], p)
+	copy(r.buf, p[n:])
+
+	r.size += len(p)
+	if r.size > capacity {
+		r.start = (r.start + r.size - capacity) % capacity
+		r.size = capacity
+	}
+}
+
+// Bytes 보관 중인 데이터를 순서대로 복사하여 반환
+func (r *ringBuffer) Bytes() []byte {
+	out := make([]byte, r.size)
+	n := copy(out, r.buf[r.start:min(r.start+r.size, len(r.buf))])
+	copy(out[n:], r.buf[:r.size-n])
+	return out
+}
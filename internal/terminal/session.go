@@ -0,0 +1,418 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/hoon-x/rootweb/internal/audit"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// 세션 연결 해제 사유
+const (
+	ReasonExited     = "exited"     // 쉘 프로세스 종료
+	ReasonTerminated = "terminated" // 사용자 또는 관리자에 의한 강제 종료
+	ReasonTakeover   = "takeover"   // 다른 연결이 세션을 가져감
+	ReasonSlowClient = "slow"       // 클라이언트가 출력을 따라가지 못해 연결 해제 (재접속 가능)
+	ReasonShutdown   = "shutdown"   // 서버 종료
+	ReasonKicked     = "kicked"     // 세션 소유자가 참여자를 내보냄
+	// 사용 제한 정책에 의한 연결 해제 사유
+	ReasonLocked      = "locked"       // 입력 없음 시간 초과로 잠금 (OTP 재인증 후 재접속 가능)
+	ReasonIdle        = "idle"         // 입력 없음 시간 초과로 종료
+	ReasonMaxDuration = "max_duration" // 최대 사용 시간 초과로 종료
+)
+
+// 참여자 모드
+const (
+	ModeOwner = "owner" // 세션 소유자 (입력 가능, 참여자 관리)
+	ModeWrite = "write" // 소유자가 입력을 승인한 참여자
+	ModeRead  = "read"  // 읽기 전용 참여자
+)
+
+var ErrReadOnly = errors.New("read-only participant")
+
+// Client 세션 출력을 수신하는 클라이언트 (웹소켓 연결)
+type Client interface {
+	// Send 출력 데이터 전송 (PTY 읽기가 지연되지 않도록 블로킹 금지)
+	// data는 호출 이후 재사용되므로 보관하려면 복사해야 함
+	Send(data []byte) bool
+	// Notify 제어 이벤트 전송 (블로킹 금지)
+	Notify(evt Event) bool
+	// Close 사유와 함께 연결 종료 (대기 중인 출력은 전송 후 종료)
+	Close(reason string)
+}
+
+// Session PTY, 쉘 프로세스, 스크롤백 버퍼를 보유한 터미널 세션
+// 웹소켓 연결이 끊겨도 유예 시간 동안 유지되어 재접속 가능
+type Session struct {
+	ID        string
+	UserID    uint
+	Username  string
+	UnixUser  string
+	ClientIP  string
+	StartedAt time.Time
+
+	uid  uint32 // 쉘을 실행한 리눅스 계정 UID
+	cmd  *exec.Cmd
+	ptmx *os.File
+	rec  *recorder
+
+	mu         sync.Mutex
+	scrollback *ringBuffer
+	clients    map[Client]*participant
+	owner      Client
+	invites    map[string]time.Time // 초대 토큰 -> 만료 시각
+	cols, rows int
+	detachedAt time.Time // 소유자 연결이 끊긴 시각 (한 번도 연결되지 않았으면 zero)
+	lastInput  time.Time // 마지막 키 입력 시각 (입력 없음 시간 초과 판단)
+	lockedAt   time.Time
+	idleWarned bool // 입력 없음 경고 전송 여부
+	maxWarned  bool // 최대 사용 시간 경고 전송 여부
+	// 터미널 파일 전송 (파일 전송이 비활성화되어 있으면 transfer는 nil)
+	transfer      *transferScanner
+	uploadPending bool // 쉘이 업로드를 요청하여 소유자의 파일 선택을 기다리는 중
+	uploading     bool // 업로드 데이터를 쉘 입력으로 전달하는 중 (키 입력 차단)
+	reason        string
+	closed        bool
+	done          chan struct{}
+}
+
+// Attached 소유자 연결 여부
+func (s *Session) Attached() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.owner != nil
+}
+
+// DetachedAt 소유자 연결이 끊긴 시각 (연결 중이면 zero)
+func (s *Session) DetachedAt() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.owner != nil {
+		return time.Time{}
+	}
+	return s.detachedAt
+}
+
+// Done 세션 종료 시 닫히는 채널
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+// Attach 소유자 클라이언트를 세션에 연결하고 스크롤백 버퍼를 재전송
+// 이미 연결된 소유자 클라이언트가 있으면 해당 연결은 종료됨
+func (s *Session) Attach(cl Client) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed || !s.lockedAt.IsZero() {
+		return false
+	}
+
+	if s.owner != nil {
+		s.owner.Close(ReasonTakeover)
+		delete(s.clients, s.owner)
+	}
+	s.owner = cl
+	// 재접속도 사용자 활동으로 보고 입력 없음 시간 초기화
+	s.touchInput()
+	s.addClient(cl, &participant{
+		userID:   s.UserID,
+		username: s.Username,
+		mode:     ModeOwner,
+		canWrite: true,
+	})
+	// 다른 창에서 가져온 경우 응답하지 않은 업로드 요청을 다시 전달
+	if s.uploadPending {
+		cl.Notify(Event{Type: EventUploadRequest})
+	}
+	return true
+}
+
+// Detach 클라이언트 연결 해제
+// 소유자 연결이 끊기면 세션은 유예 시간 동안 유지되고, 참여자는 목록에서 제거됨
+func (s *Session) Detach(cl Client) {
+	s.mu.Lock()
+	p, ok := s.clients[cl]
+	if !ok {
+		s.mu.Unlock()
+		return
+	}
+	s.removeClient(cl)
+	isOwner := p.mode == ModeOwner
+	s.mu.Unlock()
+
+	if !isOwner {
+		logger.PTY.Infow("Terminal session participant left", "id", s.ID, "user", p.username)
+		return
+	}
+
+	logger.PTY.Infow("Terminal session detached", "id", s.ID, "user", s.Username)
+
+	// 유예 시간이 없으면 즉시 종료
+	if DetachTimeout() <= 0 {
+		s.Terminate(ReasonTerminated)
+	}
+}
+
+// Write 키 입력을 PTY로 전달 (읽기 전용 참여자의 입력은 거부)
+func (s *Session) Write(cl Client, data []byte) error {
+	s.mu.Lock()
+	p, ok := s.clients[cl]
+	if ok && p.mode != ModeRead {
+		s.touchInput()
+	}
+	uploading := s.uploading
+	s.mu.Unlock()
+	if !ok || p.mode == ModeRead {
+		return ErrReadOnly
+	}
+	// 업로드 데이터 전달 중의 입력은 버림
+	if uploading {
+		return nil
+	}
+
+	s.rec.writeInput(data)
+	_, err := s.ptmx.Write(data)
+	return err
+}
+
+// Paste 소유자의 입력으로 데이터 전달 (저장된 명령을 터미널로 보낼 때 사용)
+// 잠긴 세션과 업로드 데이터를 전달 중인 세션은 거부, marker는 녹화 파일에 남길 설명
+func (s *Session) Paste(data []byte, marker string) error {
+	s.mu.Lock()
+	if !s.lockedAt.IsZero() {
+		s.mu.Unlock()
+		return ErrSessionLocked
+	}
+	if s.uploading {
+		s.mu.Unlock()
+		return ErrUploadInProgress
+	}
+	s.touchInput()
+	s.mu.Unlock()
+
+	s.rec.writeMarker(marker)
+	s.rec.writeInput(data)
+	_, err := s.ptmx.Write(data)
+	return err
+}
+
+// Resize 클라이언트 화면 크기 갱신 후 PTY 크기와 변경 여부 반환
+// PTY 크기는 연결된 클라이언트 중 가장 작은 크기로 설정됨 (모든 참여자 화면에 맞추기 위함)
+func (s *Session) Resize(cl Client, cols, rows int) (int, int, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cols <= 0 || rows <= 0 || cols > 0xffff || rows > 0xffff {
+		return s.cols, s.rows, false, nil
+	}
+	p, ok := s.clients[cl]
+	if !ok {
+		return s.cols, s.rows, false, nil
+	}
+	p.cols, p.rows = cols, rows
+
+	oldCols, oldRows := s.cols, s.rows
+	if err := s.applySize(); err != nil {
+		return s.cols, s.rows, false, err
+	}
+	// 요청한 클라이언트는 크기가 바뀌지 않았더라도 현재 크기에 맞춤
+	cl.Notify(Event{Type: EventSize, Cols: s.cols, Rows: s.rows})
+	return s.cols, s.rows, s.cols != oldCols || s.rows != oldRows, nil
+}
+
+// Terminate 쉘 프로세스 그룹을 종료하여 세션 종료
+func (s *Session) Terminate(reason string) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	if s.reason == "" {
+		s.reason = reason
+	}
+	// 세션 리더(쉘)의 프로세스 그룹 전체에 SIGHUP 전송
+	// (closed 설정 전에는 아직 회수되지 않았으므로 PID가 재사용되지 않음)
+	syscall.Kill(-s.cmd.Process.Pid, syscall.SIGHUP)
+	s.mu.Unlock()
+
+	s.cmd.Process.Kill()
+	s.ptmx.Close()
+}
+
+// addClient 클라이언트 등록 후 스크롤백 버퍼와 현재 크기 전송 (s.mu 잠금 상태에서 호출)
+func (s *Session) addClient(cl Client, p *participant) {
+	p.id = newParticipantID()
+	s.clients[cl] = p
+
+	// 출력 잠금 상태에서 버퍼를 보내므로 이후 실시간 출력과 순서가 보장됨
+	if data := s.scrollback.Bytes(); len(data) > 0 {
+		cl.Send(data)
+	}
+	cl.Notify(Event{Type: EventSize, Cols: s.cols, Rows: s.rows})
+	s.notifyParticipants()
+}
+
+// removeClient 클라이언트 제거 후 PTY 크기 재계산 (s.mu 잠금 상태에서 호출)
+func (s *Session) removeClient(cl Client) {
+	delete(s.clients, cl)
+	if s.owner == cl {
+		s.owner = nil
+		s.detachedAt = time.Now()
+		s.rejectUpload()
+	}
+	s.applySize()
+	s.notifyParticipants()
+}
+
+// applySize 연결된 클라이언트 중 가장 작은 크기로 PTY 크기 변경 (s.mu 잠금 상태에서 호출)
+func (s *Session) applySize() error {
+	cols, rows := 0, 0
+	for _, p := range s.clients {
+		if p.cols <= 0 || p.rows <= 0 {
+			continue
+		}
+		if cols == 0 || p.cols < cols {
+			cols = p.cols
+		}
+		if rows == 0 || p.rows < rows {
+			rows = p.rows
+		}
+	}
+	if cols == 0 || (cols == s.cols && rows == s.rows) {
+		return nil
+	}
+
+	if err := pty.Setsize(s.ptmx, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)}); err != nil {
+		return err
+	}
+	s.cols, s.rows = cols, rows
+	s.rec.writeResize(cols, rows)
+	s.broadcast(Event{Type: EventSize, Cols: cols, Rows: rows})
+	return nil
+}
+
+// broadcast 모든 클라이언트에 제어 이벤트 전송 (s.mu 잠금 상태에서 호출)
+func (s *Session) broadcast(evt Event) {
+	for cl := range s.clients {
+		cl.Notify(evt)
+	}
+}
+
+// run PTY 출력을 읽어 녹화, 스크롤백 버퍼, 연결된 클라이언트로 전달
+// 쉘이 종료되면 자원을 정리하고 세션 목록에서 제거
+func (s *Session) run() {
+	defer close(s.done)
+
+	buf := make([]byte, 8192)
+	var filtered []byte
+	var transfers []transferRequest
+	for {
+		n, err := s.ptmx.Read(buf)
+		out := buf[:n]
+		if n > 0 && s.transfer != nil {
+			// 파일 전송 시퀀스는 화면, 스크롤백, 녹화에 남기지 않고 소유자에게 전달
+			transfers = transfers[:0]
+			filtered = s.transfer.filter(out, filtered[:0], func(req transferRequest) {
+				transfers = append(transfers, req)
+			})
+			out = filtered
+			if len(transfers) > 0 {
+				s.mu.Lock()
+				for _, req := range transfers {
+					s.handleTransfer(req)
+				}
+				s.mu.Unlock()
+			}
+		}
+		if len(out) > 0 {
+			if werr := s.rec.writeOutput(out); werr != nil {
+				logger.PTY.Errorw("Failed to write recording", "id", s.ID, "err", werr)
+				s.Terminate(ReasonTerminated)
+			}
+
+			s.mu.Lock()
+			s.scrollback.Write(out)
+			for cl, p := range s.clients {
+				if !cl.Send(out) {
+					// 출력을 따라가지 못하는 클라이언트는 연결 해제 (재접속 시 버퍼로 복구)
+					logger.PTY.Warnw("Terminal client is too slow, detaching", "id", s.ID, "user", p.username)
+					cl.Close(ReasonSlowClient)
+					s.removeClient(cl)
+				}
+			}
+			s.mu.Unlock()
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	s.mu.Lock()
+	s.closed = true
+	if s.reason == "" {
+		s.reason = ReasonExited
+	}
+	s.mu.Unlock()
+
+	// 자원 정리 (PTY 닫기 및 프로세스 종료 대기)
+	s.ptmx.Close()
+	s.cmd.Process.Kill()
+	state, _ := s.cmd.Process.Wait()
+
+	s.mu.Lock()
+	evt := exitEvent(state)
+	for cl := range s.clients {
+		cl.Notify(evt)
+		cl.Close(s.reason)
+	}
+	s.clients = map[Client]*participant{}
+	s.owner = nil
+	s.mu.Unlock()
+
+	s.rec.finish(state)
+	mgr.remove(s.ID)
+
+	logger.PTY.Infow("Terminal session closed", "id", s.ID, "user", s.Username, "reason", s.reason, "status", state)
+	details := map[string]interface{}{
+		"reason":   s.reason,
+		"unixUser": s.UnixUser,
+		"duration": int(time.Since(s.StartedAt) / time.Second),
+	}
+	if evt.ExitCode != nil {
+		details["exitCode"] = *evt.ExitCode
+	}
+	if evt.Signal != "" {
+		details["signal"] = evt.Signal
+	}
+	audit.Record(audit.Event{
+		Type:     audit.TypeTerminalClose,
+		UserID:   s.UserID,
+		Username: s.Username,
+		ClientIP: s.ClientIP,
+		Target:   s.ID,
+		Details:  details,
+	})
+}
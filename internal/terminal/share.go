@@ -0,0 +1,285 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// 초대 링크 유효 시간
+const inviteTTL = time.Hour
+
+// 제어 이벤트 종류
+const (
+	EventSize         = "size"          // PTY 크기 변경 (클라이언트는 해당 크기로 화면 조정)
+	EventMode         = "mode"          // 참여자 모드 변경
+	EventParticipants = "participants"  // 참여자 목록 변경
+	EventWriteRequest = "write_request" // 참여자의 입력 권한 요청 (소유자에게 전달)
+	EventWarning      = "warning"       // 사용 제한에 의한 잠금 또는 종료 예고 (Reason: 예정된 조치, Seconds: 남은 시간)
+	EventExit         = "exit"          // 쉘 종료 (ExitCode: 종료 코드 또는 Signal: 종료 시그널, 세션 종료 직전 전송)
+	// 터미널 파일 전송 (세션 소유자에게만 전달)
+	EventDownload      = "download"       // 쉘에서 보낸 파일 (Name: 파일 이름, Data: base64 내용)
+	EventUploadRequest = "upload_request" // 쉘의 업로드 요청 (소유자가 파일을 선택하거나 거절해야 함)
+	EventTransferError = "transfer_error" // 처리할 수 없는 파일 전송 (Reason: 오류 내용)
+)
+
+var (
+	ErrInvalidInvite = errors.New("invite link is invalid or expired")
+	ErrNotOwner      = errors.New("only the session owner can do this")
+	ErrNoParticipant = errors.New("participant not found")
+	ErrCannotWrite   = errors.New("participant is not allowed to type")
+)
+
+// Event 클라이언트에 전달하는 제어 이벤트
+type Event struct {
+	Type         string        `json:"type"`
+	ID           string        `json:"id,omitempty"`
+	Username     string        `json:"username,omitempty"`
+	Mode         string        `json:"mode,omitempty"`
+	Cols         int           `json:"cols,omitempty"`
+	Rows         int           `json:"rows,omitempty"`
+	Participants []Participant `json:"participants,omitempty"`
+	Reason       string        `json:"reason,omitempty"`
+	Seconds      int           `json:"seconds,omitempty"`
+	Name         string        `json:"name,omitempty"`
+	Data         string        `json:"data,omitempty"`
+	ExitCode     *int          `json:"exitCode,omitempty"`
+	Signal       string        `json:"signal,omitempty"`
+}
+
+// Participant 참여자 정보
+type Participant struct {
+	ID        string `json:"id"`
+	Username  string `json:"username"`
+	Mode      string `json:"mode"`
+	CanWrite  bool   `json:"canWrite"`  // 계정 권한 상 입력 승인 가능 여부
+	Requested bool   `json:"requested"` // 입력 권한 요청 여부
+}
+
+// participant 세션에 연결된 클라이언트의 상태
+type participant struct {
+	id         string
+	userID     uint
+	username   string
+	mode       string
+	canWrite   bool
+	requested  bool
+	cols, rows int
+}
+
+// newParticipantID 참여자 식별용 임의 ID 생성
+func newParticipantID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// CreateInvite 세션 초대 토큰 생성
+func (s *Session) CreateInvite() (string, time.Time, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(b)
+	expiresAt := time.Now().Add(inviteTTL)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// 만료된 초대 정리
+	for t, exp := range s.invites {
+		if time.Now().After(exp) {
+			delete(s.invites, t)
+		}
+	}
+	s.invites[token] = expiresAt
+	return token, expiresAt, nil
+}
+
+// RevokeInvites 발급된 모든 초대 토큰 폐기 (이미 참여 중인 연결은 유지)
+func (s *Session) RevokeInvites() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.invites = map[string]time.Time{}
+}
+
+// Participants 소유자를 제외한 참여자 수
+func (s *Session) Participants() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := len(s.clients)
+	if s.owner != nil {
+		n--
+	}
+	return n
+}
+
+// Join 초대 토큰으로 읽기 전용 참여자 연결
+// canWrite는 계정 권한 상 입력 권한을 승인받을 수 있는지 여부 (operator 이상)
+func (s *Session) Join(cl Client, user *db.User, token string, canWrite bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	exp, ok := s.invites[token]
+	if s.closed || !ok || time.Now().After(exp) {
+		return ErrInvalidInvite
+	}
+	if !s.lockedAt.IsZero() {
+		return ErrSessionLocked
+	}
+
+	s.addClient(cl, &participant{
+		userID:   user.ID,
+		username: user.Username,
+		mode:     ModeRead,
+		canWrite: canWrite,
+	})
+	return nil
+}
+
+// RequestWrite 참여자가 소유자에게 입력 권한 요청
+func (s *Session) RequestWrite(cl Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.clients[cl]
+	if !ok || p.mode != ModeRead {
+		return ErrNoParticipant
+	}
+	if !p.canWrite {
+		return ErrCannotWrite
+	}
+
+	p.requested = true
+	if s.owner != nil {
+		s.owner.Notify(Event{Type: EventWriteRequest, ID: p.id, Username: p.username})
+	}
+	s.notifyParticipants()
+	return nil
+}
+
+// SetMode 소유자가 참여자의 입력 권한을 승인(ModeWrite) 또는 회수(ModeRead)
+func (s *Session) SetMode(owner Client, id, mode string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if owner != s.owner {
+		return ErrNotOwner
+	}
+	cl, p := s.findParticipant(id)
+	if p == nil || p.mode == ModeOwner {
+		return ErrNoParticipant
+	}
+	if mode == ModeWrite && !p.canWrite {
+		return ErrCannotWrite
+	}
+
+	p.requested = false
+	if p.mode != mode {
+		p.mode = mode
+		cl.Notify(Event{Type: EventMode, Mode: mode})
+		logger.PTY.Infow("Terminal session participant mode changed", "id", s.ID, "user", p.username, "mode", mode)
+	}
+	s.notifyParticipants()
+	return nil
+}
+
+// RevokeWrite 참여자의 입력 권한 승인 가능 여부를 회수하고, 입력 중이었으면 읽기 전용으로 전환
+// 입력 중이던 참여자를 읽기 전용으로 바꿨으면 true 반환 (소유자 및 참여하지 않은 클라이언트는 변화 없음)
+func (s *Session) RevokeWrite(cl Client) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.clients[cl]
+	if !ok || p.mode == ModeOwner || !p.canWrite {
+		return false
+	}
+
+	p.canWrite = false
+	p.requested = false
+	wasWriting := p.mode == ModeWrite
+	if wasWriting {
+		p.mode = ModeRead
+		cl.Notify(Event{Type: EventMode, Mode: ModeRead})
+		logger.PTY.Infow("Terminal session participant mode changed", "id", s.ID, "user", p.username, "mode", ModeRead)
+	}
+	s.notifyParticipants()
+	return wasWriting
+}
+
+// Kick 소유자가 참여자를 세션에서 내보냄
+func (s *Session) Kick(owner Client, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if owner != s.owner {
+		return ErrNotOwner
+	}
+	cl, p := s.findParticipant(id)
+	if p == nil || p.mode == ModeOwner {
+		return ErrNoParticipant
+	}
+
+	cl.Close(ReasonKicked)
+	s.removeClient(cl)
+	logger.PTY.Infow("Terminal session participant kicked", "id", s.ID, "user", p.username)
+	return nil
+}
+
+// findParticipant 참여자 ID로 클라이언트 조회 (s.mu 잠금 상태에서 호출)
+func (s *Session) findParticipant(id string) (Client, *participant) {
+	for cl, p := range s.clients {
+		if p.id == id {
+			return cl, p
+		}
+	}
+	return nil, nil
+}
+
+// notifyParticipants 참여자 목록을 모든 클라이언트에 전송 (s.mu 잠금 상태에서 호출)
+func (s *Session) notifyParticipants() {
+	list := make([]Participant, 0, len(s.clients))
+	for _, p := range s.clients {
+		list = append(list, Participant{
+			ID:        p.id,
+			Username:  p.username,
+			Mode:      p.mode,
+			CanWrite:  p.canWrite,
+			Requested: p.requested,
+		})
+	}
+	s.broadcast(Event{Type: EventParticipants, Participants: list})
+}
+
+// FindByInvite 초대 토큰으로 세션 조회
+func FindByInvite(token string) *Session {
+	for _, s := range List() {
+		s.mu.Lock()
+		exp, ok := s.invites[token]
+		s.mu.Unlock()
+		if ok && time.Now().Before(exp) {
+			return s
+		}
+	}
+	return nil
+}
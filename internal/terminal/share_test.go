@@ -0,0 +1,94 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// eventClient 받은 제어 이벤트를 기록하는 테스트용 클라이언트
+type eventClient struct {
+	events []Event
+}
+
+func (c *eventClient) Send(data []byte) bool { return true }
+func (c *eventClient) Notify(evt Event) bool { c.events = append(c.events, evt); return true }
+func (c *eventClient) Close(reason string)   {}
+
+// modeEvents 받은 참여자 모드 변경 이벤트의 모드 목록
+func (c *eventClient) modeEvents() []string {
+	var modes []string
+	for _, evt := range c.events {
+		if evt.Type == EventMode {
+			modes = append(modes, evt.Mode)
+		}
+	}
+	return modes
+}
+
+func TestRevokeWrite(t *testing.T) {
+	logger.InitializeLogger(filepath.Join(t.TempDir(), "test.log"), 1, 1, 1, false, false, logger.FormatConsole, "")
+	t.Cleanup(logger.FinalizeLogger)
+
+	owner, writer, viewer := &eventClient{}, &eventClient{}, &eventClient{}
+	s := &Session{
+		ID:    "test",
+		owner: owner,
+		clients: map[Client]*participant{
+			owner:  {username: "alice", mode: ModeOwner, canWrite: true},
+			writer: {id: "w", username: "bob", mode: ModeWrite, canWrite: true},
+			viewer: {id: "v", username: "carol", mode: ModeRead, canWrite: true, requested: true},
+		},
+	}
+
+	// 소유자의 입력 권한은 회수하지 않음
+	if s.RevokeWrite(owner) || s.clients[owner].mode != ModeOwner {
+		t.Errorf("RevokeWrite(owner) changed the owner")
+	}
+
+	// 입력 중이던 참여자는 읽기 전용으로 전환되고 다시 승인할 수 없음
+	if !s.RevokeWrite(writer) {
+		t.Errorf("RevokeWrite(writer) = false, want true")
+	}
+	if p := s.clients[writer]; p.mode != ModeRead || p.canWrite {
+		t.Errorf("writer mode = %s, canWrite = %t, want read-only", p.mode, p.canWrite)
+	}
+	if got := writer.modeEvents(); len(got) != 1 || got[0] != ModeRead {
+		t.Errorf("writer mode events = %v, want [%s]", got, ModeRead)
+	}
+	if err := s.Write(writer, []byte("x")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("Write after revoke = %v, want ErrReadOnly", err)
+	}
+	if err := s.SetMode(owner, "w", ModeWrite); !errors.Is(err, ErrCannotWrite) {
+		t.Errorf("SetMode after revoke = %v, want ErrCannotWrite", err)
+	}
+
+	// 읽기 전용 참여자는 승인 가능 여부와 요청만 회수
+	if s.RevokeWrite(viewer) {
+		t.Errorf("RevokeWrite(viewer) = true, want false")
+	}
+	if p := s.clients[viewer]; p.canWrite || p.requested {
+		t.Errorf("viewer canWrite = %t, requested = %t, want both false", p.canWrite, p.requested)
+	}
+	if got := viewer.modeEvents(); len(got) != 0 {
+		t.Errorf("viewer mode events = %v, want none", got)
+	}
+}
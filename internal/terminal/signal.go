@@ -0,0 +1,127 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"syscall"
+
+	"github.com/hoon-x/rootweb/internal/logger"
+	"github.com/hoon-x/rootweb/pkg/proc"
+	"golang.org/x/sys/unix"
+)
+
+// 클라이언트가 포그라운드 프로세스 그룹에 보낼 수 있는 시그널
+var allowedSignals = []syscall.Signal{
+	syscall.SIGINT,
+	syscall.SIGQUIT,
+	syscall.SIGTERM,
+	syscall.SIGHUP,
+	syscall.SIGKILL,
+}
+
+// 키 입력(Ctrl+C, Ctrl+\)으로도 보낼 수 있어 프로세스 소유자와 관계없이 허용하는 시그널
+var ttySignals = []syscall.Signal{syscall.SIGINT, syscall.SIGQUIT}
+
+var (
+	ErrInvalidSignal = errors.New("signal is not supported")
+	ErrSignalDenied  = errors.New("foreground process belongs to another user")
+	ErrNoForeground  = errors.New("terminal has no foreground process group")
+)
+
+// Signal 포그라운드 프로세스 그룹에 시그널 전송 (입력 권한이 있는 클라이언트만 가능)
+// 데몬은 루트 권한으로 동작하므로, 키 입력으로 보낼 수 없는 시그널은 계정 소유의 프로세스에만 전송
+// (sudo 등으로 다른 계정 권한을 얻은 프로세스는 사용자가 직접 kill 할 수 없는 것과 동일)
+func (s *Session) Signal(cl Client, name string) error {
+	sig := unix.SignalNum(name)
+	if !slices.Contains(allowedSignals, sig) {
+		return ErrInvalidSignal
+	}
+
+	s.mu.Lock()
+	p, ok := s.clients[cl]
+	if ok && p.mode != ModeRead {
+		s.touchInput()
+	}
+	s.mu.Unlock()
+	if !ok || p.mode == ModeRead {
+		return ErrReadOnly
+	}
+
+	pgrp, err := s.foregroundGroup()
+	if err != nil {
+		return err
+	}
+	// 쉘이 종료된 뒤 다른 프로세스가 PTY를 열고 있으면 0이 조회되며, kill(0)은 데몬 자신의 프로세스 그룹에 전송됨
+	if pgrp <= 1 || pgrp == syscall.Getpgrp() {
+		logger.PTY.Warnw("Rejected terminal signal without valid foreground group",
+			"id", s.ID, "user", p.username, "signal", name, "pgrp", pgrp)
+		return ErrNoForeground
+	}
+	if !slices.Contains(ttySignals, sig) {
+		uids, err := proc.GetProcUids(pgrp)
+		if err != nil {
+			return err
+		}
+		// kill(2)의 권한 확인과 동일하게 real 또는 saved UID 비교
+		if uids[0] != s.uid && uids[2] != s.uid {
+			return ErrSignalDenied
+		}
+	}
+	if err := syscall.Kill(-pgrp, sig); err != nil {
+		return err
+	}
+
+	logger.PTY.Infow("Terminal signal sent", "id", s.ID, "user", p.username, "unixUser", s.UnixUser, "signal", name, "pgrp", pgrp)
+	s.rec.writeMarker("Signal " + name + " sent by " + p.username)
+	return nil
+}
+
+// foregroundGroup PTY의 포그라운드 프로세스 그룹 ID 조회
+// (Fd()를 호출하면 파일이 블로킹 모드로 바뀌므로 SyscallConn 사용)
+func (s *Session) foregroundGroup() (int, error) {
+	rc, err := s.ptmx.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+
+	var pgrp int
+	var ioctlErr error
+	if err := rc.Control(func(fd uintptr) {
+		pgrp, ioctlErr = unix.IoctlGetInt(int(fd), unix.TIOCGPGRP)
+	}); err != nil {
+		return 0, err
+	}
+	return pgrp, ioctlErr
+}
+
+// exitEvent 쉘 종료 상태 이벤트 (시그널로 종료되면 시그널 이름, 아니면 종료 코드)
+func exitEvent(state *os.ProcessState) Event {
+	evt := Event{Type: EventExit}
+	if state == nil {
+		return evt
+	}
+	if ws, ok := state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		evt.Signal = unix.SignalName(ws.Signal())
+		return evt
+	}
+	code := state.ExitCode()
+	evt.ExitCode = &code
+	return evt
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// testClient 출력을 버리는 테스트용 클라이언트
+type testClient struct{}
+
+func (testClient) Send(data []byte) bool { return true }
+func (testClient) Notify(evt Event) bool { return true }
+func (testClient) Close(reason string)   {}
+
+// newSignalTestSession 입력 권한이 있는 클라이언트 하나가 연결된 PTY 세션 생성
+func newSignalTestSession(t *testing.T) (*Session, *os.File, Client) {
+	t.Helper()
+
+	logger.InitializeLogger(filepath.Join(t.TempDir(), "test.log"), 1, 1, 1, false, false, logger.FormatConsole, "")
+	t.Cleanup(logger.FinalizeLogger)
+
+	ptmx, tty, err := pty.Open()
+	if err != nil {
+		t.Fatalf("open pty: %v", err)
+	}
+	t.Cleanup(func() {
+		ptmx.Close()
+		tty.Close()
+	})
+
+	cl := testClient{}
+	s := &Session{
+		ID:      "test",
+		uid:     uint32(os.Getuid()),
+		ptmx:    ptmx,
+		clients: map[Client]*participant{cl: {username: "alice", mode: ModeWrite}},
+	}
+	return s, tty, cl
+}
+
+// 쉘이 종료되고 SIGHUP을 무시한 자식 프로세스만 PTY를 열고 있으면 포그라운드 그룹이 0으로 조회됨
+// kill(0)으로 데몬 자신의 프로세스 그룹에 시그널이 전송되지 않아야 함
+func TestSignalWithoutForegroundGroup(t *testing.T) {
+	s, _, cl := newSignalTestSession(t)
+
+	pgrp, err := s.foregroundGroup()
+	if err != nil {
+		t.Fatalf("foregroundGroup: %v", err)
+	}
+	if pgrp != 0 {
+		t.Fatalf("foregroundGroup = %d, want 0 without a session leader", pgrp)
+	}
+
+	for _, name := range []string{"SIGINT", "SIGQUIT", "SIGTERM"} {
+		if err := s.Signal(cl, name); !errors.Is(err, ErrNoForeground) {
+			t.Errorf("Signal(%s) = %v, want ErrNoForeground", name, err)
+		}
+	}
+}
+
+func TestSignalForegroundGroup(t *testing.T) {
+	s, tty, cl := newSignalTestSession(t)
+
+	// 쉘처럼 새 세션을 만들고 PTY를 제어 터미널로 사용하는 프로세스 실행
+	cmd := exec.Command("sleep", "30")
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if pgrp, _ := s.foregroundGroup(); pgrp == cmd.Process.Pid {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("foreground group was not set")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := s.Signal(cl, "SIGTERM"); err != nil {
+		t.Fatalf("Signal(SIGTERM): %v", err)
+	}
+	err := cmd.Wait()
+	if ws, ok := cmd.ProcessState.Sys().(syscall.WaitStatus); !ok || !ws.Signaled() || ws.Signal() != syscall.SIGTERM {
+		t.Errorf("process exit = %v, want terminated by SIGTERM", err)
+	}
+
+	// 세션 리더가 종료된 뒤에는 다시 거부
+	if err := s.Signal(cl, "SIGINT"); !errors.Is(err, ErrNoForeground) {
+		t.Errorf("Signal(SIGINT) after exit = %v, want ErrNoForeground", err)
+	}
+}
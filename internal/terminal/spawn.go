@@ -0,0 +1,185 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"syscall"
+
+	"github.com/creack/pty"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/pkg/passwd"
+)
+
+var (
+	errNoUnixUser      = errors.New("no linux account is mapped to this user")
+	errRootNotAllowed  = errors.New("root shell is not allowed for this user")
+	errLoginNotAllowed = errors.New("linux account does not allow login")
+)
+
+// LookupUnixAccount 사용자에 매핑된 리눅스 계정 조회 및 접근 정책 확인
+func LookupUnixAccount(user *db.User) (*passwd.Account, error) {
+	if user.UnixUser == "" {
+		return nil, errNoUnixUser
+	}
+
+	acc, err := passwd.Lookup(user.UnixUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// 루트 계정은 명시적으로 허용된 사용자만 사용 가능
+	if acc.IsRoot() && !user.AllowRoot {
+		return nil, errRootNotAllowed
+	}
+
+	// 로그인이 차단된 계정 (nologin, false)
+	switch filepath.Base(acc.Shell) {
+	case "nologin", "false":
+		return nil, errLoginNotAllowed
+	}
+
+	return acc, nil
+}
+
+// newShellCommand 리눅스 계정의 로그인 쉘 실행 명령 생성
+func newShellCommand(acc *passwd.Account) *exec.Cmd {
+	shell := accountShell(acc)
+
+	// argv[0]을 "-bash" 형태로 지정하여 로그인 쉘로 실행
+	cmd := exec.Command(shell)
+	cmd.Args = []string{"-" + filepath.Base(shell)}
+	setupAccount(cmd, acc, shell)
+	return cmd
+}
+
+// NewCommand 리눅스 계정의 쉘로 명령을 비대화형 실행하는 명령 생성 (터미널과 같은 환경 변수, 디렉터리, 권한 적용)
+// 새 세션으로 실행하므로 프로세스 그룹 ID(-PID)로 자식 프로세스까지 종료 가능
+func NewCommand(ctx context.Context, acc *passwd.Account, command string) *exec.Cmd {
+	shell := accountShell(acc)
+
+	cmd := exec.CommandContext(ctx, shell, "-c", command)
+	setupAccount(cmd, acc, shell)
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	return cmd
+}
+
+// accountShell 리눅스 계정의 로그인 쉘 (미지정 시 /bin/sh)
+func accountShell(acc *passwd.Account) string {
+	if acc.Shell == "" {
+		return "/bin/sh"
+	}
+	return acc.Shell
+}
+
+// setupAccount 실행 명령에 리눅스 계정의 환경 변수, 시작 디렉터리, uid/gid 적용
+func setupAccount(cmd *exec.Cmd, acc *passwd.Account, shell string) {
+	cmd.Env = shellEnv(acc, shell)
+
+	// 홈 디렉터리가 없으면 최상위 경로에서 시작
+	cmd.Dir = acc.Home
+	if stat, err := os.Stat(acc.Home); err != nil || !stat.IsDir() {
+		cmd.Dir = "/"
+	}
+
+	// 데몬과 다른 계정이면 해당 계정의 uid/gid/보조 그룹으로 전환
+	if cred := UnixCredential(acc); cred != nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Credential: cred}
+	}
+}
+
+// UnixCredential 리눅스 계정으로 프로세스를 실행할 때 사용할 uid/gid/보조 그룹 (데몬과 같은 계정이면 nil)
+func UnixCredential(acc *passwd.Account) *syscall.Credential {
+	if acc.Uid == uint32(os.Getuid()) && acc.Gid == uint32(os.Getgid()) && os.Getuid() != 0 {
+		return nil
+	}
+	return &syscall.Credential{
+		Uid:    acc.Uid,
+		Gid:    acc.Gid,
+		Groups: acc.Groups,
+	}
+}
+
+// shellEnv 설정 파일 기반의 깨끗한 환경 변수 목록 생성
+func shellEnv(acc *passwd.Account, shell string) []string {
+	vars := map[string]string{"TERM": "xterm-256color"}
+	for k, v := range config.Conf.Terminal.Env {
+		vars[k] = v
+	}
+	vars["HOME"] = acc.Home
+	vars["USER"] = acc.Name
+	vars["LOGNAME"] = acc.Name
+	vars["SHELL"] = shell
+
+	env := make([]string, 0, len(vars))
+	for k, v := range vars {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	return env
+}
+
+// startPTY PTY를 열어 쉘 프로세스 실행
+// 슬레이브 TTY의 소유자를 쉘 계정으로 변경하여 tty, mesg 등이 정상 동작하도록 함
+func startPTY(cmd *exec.Cmd, acc *passwd.Account, size *pty.Winsize) (*os.File, error) {
+	ptmx, tty, err := pty.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer tty.Close()
+
+	if os.Getuid() == 0 {
+		if err := tty.Chown(int(acc.Uid), int(acc.Gid)); err != nil {
+			ptmx.Close()
+			return nil, err
+		}
+		if err := tty.Chmod(0620); err != nil {
+			ptmx.Close()
+			return nil, err
+		}
+	}
+
+	if err := pty.Setsize(ptmx, size); err != nil {
+		ptmx.Close()
+		return nil, err
+	}
+
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+
+	if err := cmd.Start(); err != nil {
+		ptmx.Close()
+		return nil, err
+	}
+
+	return ptmx, nil
+}
@@ -0,0 +1,228 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/creack/pty"
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/db"
+	"github.com/hoon-x/rootweb/internal/logger"
+)
+
+// 기본 스크롤백 버퍼 크기 (설정 값이 없을 때)
+const defaultScrollbackKB = 256
+
+var ErrTooManySessions = errors.New("too many terminal sessions")
+
+// manager 터미널 세션 관리자
+type manager struct {
+	mu             sync.Mutex
+	sessions       map[string]*Session
+	detachTimeout  time.Duration
+	scrollbackSize int
+	maxPerUser     int
+	limits         limitPolicy
+	transferMax    int // 터미널 파일 전송 최대 크기 (0이면 비활성화)
+}
+
+var mgr = manager{
+	sessions: make(map[string]*Session),
+}
+
+// Run 연결이 끊긴 세션을 유예 시간 경과 후 정리하고, 종료 시 모든 세션 종료
+// 입력 없음 시간 및 최대 사용 시간 제한도 같은 주기로 확인
+func Run(ctx context.Context) {
+	mgr.mu.Lock()
+	mgr.detachTimeout = time.Duration(config.Conf.Terminal.DetachTimeout) * time.Second
+	mgr.scrollbackSize = config.Conf.Terminal.ScrollbackSize * 1024
+	if mgr.scrollbackSize <= 0 {
+		mgr.scrollbackSize = defaultScrollbackKB * 1024
+	}
+	mgr.maxPerUser = config.Conf.Terminal.MaxSessionsPerUser
+	mgr.limits = loadLimitPolicy()
+	mgr.transferMax = loadTransferMaxSize()
+	limits := mgr.limits
+	mgr.mu.Unlock()
+
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			closeAll()
+			return
+		case now := <-ticker.C:
+			detachTimeout := DetachTimeout()
+			for _, s := range List() {
+				// 한 번도 연결되지 않은 세션은 분리 시각이 zero이므로 정리 대상에서 제외
+				detachedAt := s.DetachedAt()
+				if !detachedAt.IsZero() && now.Sub(detachedAt) > detachTimeout {
+					logger.PTY.Infow("Terminal session detach timeout", "id", s.ID, "user", s.Username)
+					s.Terminate(ReasonTerminated)
+					continue
+				}
+				s.enforceLimits(now, limits)
+			}
+		}
+	}
+}
+
+// NewSession 사용자의 리눅스 계정으로 쉘을 실행하고 세션 등록
+func NewSession(user *db.User, clientIP string, cols, rows int) (*Session, error) {
+	// 사용자당 최대 세션 개수 확인
+	if mgr.maxPerUser > 0 && len(ListByUser(user.ID)) >= mgr.maxPerUser {
+		return nil, ErrTooManySessions
+	}
+
+	// 사용자에 매핑된 리눅스 계정 조회
+	acc, err := LookupUnixAccount(user)
+	if err != nil {
+		return nil, err
+	}
+
+	// 실행할 쉘 설정 (계정의 로그인 쉘, 홈 디렉터리, uid/gid 및 환경 변수 지정)
+	cmd := newShellCommand(acc)
+
+	// PTY(가상 터미널) 시작 및 쉘 실행
+	ptmx, err := startPTY(cmd, acc, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)})
+	if err != nil {
+		return nil, err
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		ptmx.Close()
+		cmd.Process.Kill()
+		cmd.Process.Wait()
+		return nil, err
+	}
+	sessionID := hex.EncodeToString(id)
+
+	// 세션 녹화 시작 (녹화 실패 시 감사 추적이 불가능하므로 세션을 열지 않음)
+	rec, err := startRecording(sessionID, user, clientIP, cmd.Path, cols, rows)
+	if err != nil {
+		ptmx.Close()
+		cmd.Process.Kill()
+		cmd.Process.Wait()
+		return nil, err
+	}
+
+	s := &Session{
+		ID:         sessionID,
+		UserID:     user.ID,
+		Username:   user.Username,
+		UnixUser:   acc.Name,
+		ClientIP:   clientIP,
+		StartedAt:  time.Now(),
+		uid:        acc.Uid,
+		cmd:        cmd,
+		ptmx:       ptmx,
+		rec:        rec,
+		scrollback: newRingBuffer(mgr.scrollbackSize),
+		clients:    map[Client]*participant{},
+		invites:    map[string]time.Time{},
+		cols:       cols,
+		rows:       rows,
+		lastInput:  time.Now(),
+		done:       make(chan struct{}),
+	}
+	if maxSize := TransferMaxSize(); maxSize > 0 {
+		s.transfer = newTransferScanner(maxSize)
+	}
+
+	mgr.mu.Lock()
+	mgr.sessions[s.ID] = s
+	mgr.mu.Unlock()
+
+	go s.run()
+
+	logger.PTY.Infow("Terminal session started",
+		"id", s.ID, "user", user.Username, "unixUser", acc.Name, "uid", acc.Uid, "shell", cmd.Path, "pid", cmd.Process.Pid, "ip", clientIP)
+	return s, nil
+}
+
+// Find ID로 세션 조회
+func Find(id string) *Session {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	return mgr.sessions[id]
+}
+
+// List 전체 세션 목록 (시작 시각 순)
+func List() []*Session {
+	mgr.mu.Lock()
+	list := make([]*Session, 0, len(mgr.sessions))
+	for _, s := range mgr.sessions {
+		list = append(list, s)
+	}
+	mgr.mu.Unlock()
+
+	sort.Slice(list, func(i, j int) bool { return list[i].StartedAt.Before(list[j].StartedAt) })
+	return list
+}
+
+// ListByUser 사용자의 세션 목록
+func ListByUser(userID uint) []*Session {
+	var list []*Session
+	for _, s := range List() {
+		if s.UserID == userID {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// DetachTimeout 연결이 끊긴 세션의 유지 시간
+func DetachTimeout() time.Duration {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	return mgr.detachTimeout
+}
+
+// remove 세션 목록에서 제거
+func (m *manager) remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+}
+
+// closeAll 모든 세션 종료 후 정리 완료 대기
+func closeAll() {
+	sessions := List()
+	for _, s := range sessions {
+		s.Terminate(ReasonShutdown)
+	}
+
+	timeout := time.After(3 * time.Second)
+	for _, s := range sessions {
+		select {
+		case <-s.Done():
+		case <-timeout:
+			logger.PTY.Warnw("Terminal sessions have not been closed")
+			return
+		}
+	}
+}
@@ -0,0 +1,443 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hoon-x/rootweb/config"
+	"github.com/hoon-x/rootweb/internal/logger"
+	"golang.org/x/sys/unix"
+)
+
+// 터미널 파일 전송 최대 크기 기본값 (설정 값이 없을 때)
+const defaultTransferMaxMB = 16
+
+// 파일 전송 이스케이프 시퀀스 (iTerm2 호환)
+// 다운로드: ESC ] 1337 ; File=name=<base64 이름>;size=<크기>:<base64 내용> BEL (inline=1이면 인라인 이미지로 보고 그대로 출력)
+// 업로드: ESC ] 1337 ; RequestUpload=format=tgz BEL
+// 업로드 요청에는 "ok\n" 다음에 base64로 인코딩한 tar.gz 데이터와 빈 줄, 또는 "abort\n"을 입력으로 응답
+// 응답은 요청한 도우미 프로그램(it2ul)이 입력을 기다리는 것이 확인된 경우에만 전달
+const (
+	oscFilePrefix   = "\x1b]1337;File="
+	oscUploadPrefix = "\x1b]1337;RequestUpload="
+)
+
+// 파일 전송 인자 부분 최대 길이
+const maxTransferArgs = 1024
+
+// 업로드 데이터를 입력으로 전달할 때 base64 한 줄 길이 (정규 모드 입력 줄 길이 제한 고려)
+const uploadLineLen = 76
+
+// 업로드 응답 전 도우미 프로그램의 입력 대기 상태를 확인하는 최대 시간 및 간격
+const (
+	uploadHandshakeTimeout  = 2 * time.Second
+	uploadHandshakeInterval = 50 * time.Millisecond
+)
+
+var (
+	ErrNoUpload         = errors.New("no upload has been requested")
+	ErrTransferTooLarge = errors.New("file transfer exceeds the size limit")
+	ErrTransferInvalid  = errors.New("file transfer data is not valid base64")
+	ErrUploadInProgress = errors.New("file upload is in progress")
+	ErrNoUploadHelper   = errors.New("no upload helper is waiting for input")
+)
+
+// 파일 전송 스캐너 상태
+const (
+	scanNormal       = iota
+	scanPrefix       // OSC 시작 부분이 전송 시퀀스인지 판별 중
+	scanArgs         // 인자 수집 중
+	scanData         // base64 내용 수집 중
+	scanEscape       // 인자 또는 내용 수집 중 ESC 수신 (ST 종료 확인)
+	scanInline       // 인라인 이미지 시퀀스를 그대로 출력 중
+	scanInlineEscape // 인라인 이미지 시퀀스 출력 중 ESC 수신 (ST 종료 확인)
+)
+
+// transferRequest PTY 출력에서 찾은 파일 전송 요청
+type transferRequest struct {
+	upload bool              // 업로드 요청 여부 (false면 다운로드)
+	args   map[string]string // 시퀀스 인자 (name, size, format 등)
+	data   []byte            // 다운로드 내용 (base64, 공백 제거됨)
+	err    error             // 크기 초과 등 처리할 수 없는 요청
+}
+
+// name 다운로드 파일 이름 (name 인자는 base64로 인코딩되어 있음)
+func (t *transferRequest) name() string {
+	if b, err := base64.StdEncoding.DecodeString(t.args["name"]); err == nil && len(b) > 0 {
+		return string(b)
+	}
+	return "download"
+}
+
+// transferScanner PTY 출력에서 파일 전송 시퀀스를 찾아 제거
+// 시퀀스가 여러 번의 읽기에 나뉘어 도착해도 처리하며, 그 외 출력은 그대로 통과시킴
+type transferScanner struct {
+	state    int
+	held     []byte // 판별 중인 OSC 시작 부분
+	upload   bool
+	args     []byte
+	data     []byte
+	tooLarge bool
+	maxSize  int // 최대 크기 (base64 인코딩 기준)
+	inData   bool
+}
+
+// newTransferScanner 최대 전송 크기(바이트)를 적용한 스캐너 생성
+func newTransferScanner(maxSize int) *transferScanner {
+	return &transferScanner{maxSize: base64.StdEncoding.EncodedLen(maxSize)}
+}
+
+// filter 출력에서 파일 전송 시퀀스를 제거한 결과를 out에 추가하여 반환
+// 완성된 시퀀스마다 found 호출
+func (t *transferScanner) filter(p, out []byte, found func(transferRequest)) []byte {
+	for i := 0; i < len(p); i++ {
+		b := p[i]
+		switch t.state {
+		case scanNormal:
+			// 대부분의 출력은 ESC가 없으므로 한 번에 복사
+			idx := bytes.IndexByte(p[i:], 0x1b)
+			if idx < 0 {
+				return append(out, p[i:]...)
+			}
+			out = append(out, p[i:i+idx]...)
+			i += idx
+			t.held = append(t.held[:0], 0x1b)
+			t.state = scanPrefix
+
+		case scanPrefix:
+			t.held = append(t.held, b)
+			held := string(t.held)
+			switch {
+			case held == oscFilePrefix || held == oscUploadPrefix:
+				t.upload = held == oscUploadPrefix
+				t.args, t.data, t.tooLarge, t.inData = t.args[:0], t.data[:0], false, false
+				t.state = scanArgs
+			case strings.HasPrefix(oscFilePrefix, held) || strings.HasPrefix(oscUploadPrefix, held):
+				// 판별에 필요한 바이트가 더 필요함
+			default:
+				// 전송 시퀀스가 아니면 보류한 바이트를 그대로 출력 (새 ESC는 다시 판별)
+				out = append(out, t.held[:len(t.held)-1]...)
+				t.state = scanNormal
+				i--
+			}
+
+		case scanArgs:
+			switch {
+			case b == 0x07:
+				out = t.finish(out, found)
+			case b == 0x1b:
+				t.state = scanEscape
+			case b == ':' && !t.upload && isInlineFile(t.args):
+				// 인라인 이미지 (imgcat 등)는 전송 요청이 아니므로 시퀀스 전체를 그대로 출력
+				out = append(out, oscFilePrefix...)
+				out = append(out, t.args...)
+				out = append(out, b)
+				t.state = scanInline
+			case b == ':' && !t.upload:
+				t.inData = true
+				t.state = scanData
+			case b < 0x20 || len(t.args) >= maxTransferArgs:
+				t.abort()
+				i--
+			default:
+				t.args = append(t.args, b)
+			}
+
+		case scanData:
+			switch {
+			case b == 0x07:
+				out = t.finish(out, found)
+			case b == 0x1b:
+				t.state = scanEscape
+			case b == '\r' || b == '\n':
+				// base64 명령의 줄바꿈 (PTY에서 \r\n으로 변환됨)
+			case isBase64(b):
+				if len(t.data) < t.maxSize {
+					t.data = append(t.data, b)
+				} else {
+					t.tooLarge = true
+				}
+			default:
+				// base64가 아닌 출력이면 전송이 중단된 것으로 보고 일반 출력으로 처리
+				t.abort()
+				i--
+			}
+
+		case scanInline:
+			out = append(out, b)
+			switch b {
+			case 0x07:
+				t.state = scanNormal
+			case 0x1b:
+				t.state = scanInlineEscape
+			}
+
+		case scanInlineEscape:
+			if b == '\\' {
+				out = append(out, b)
+				t.state = scanNormal
+				continue
+			}
+			// ST가 아니면 시퀀스가 끝난 것으로 보고 다음 바이트부터 일반 출력으로 처리
+			t.state = scanNormal
+			i--
+
+		case scanEscape:
+			if b == '\\' {
+				out = t.finish(out, found)
+				continue
+			}
+			// ST가 아니면 전송이 중단된 것으로 보고 ESC부터 다시 판별
+			t.abort()
+			t.held = append(t.held[:0], 0x1b)
+			t.state = scanPrefix
+			i--
+		}
+	}
+	return out
+}
+
+// finish 완성된 시퀀스를 요청으로 변환하여 전달
+func (t *transferScanner) finish(out []byte, found func(transferRequest)) []byte {
+	req := transferRequest{upload: t.upload, args: parseTransferArgs(string(t.args))}
+	switch {
+	case t.tooLarge:
+		req.err = ErrTransferTooLarge
+	case !t.upload && !t.inData:
+		// 내용이 없는 File 시퀀스 (인라인 이미지 정보 조회 등)는 무시
+		t.state = scanNormal
+		return out
+	case !t.upload:
+		req.data = append([]byte(nil), t.data...)
+	}
+	t.state = scanNormal
+	found(req)
+	return out
+}
+
+// abort 수집 중인 시퀀스 폐기
+func (t *transferScanner) abort() {
+	t.state = scanNormal
+	t.args, t.data = t.args[:0], t.data[:0]
+}
+
+// isInlineFile File 시퀀스 인자에 inline=1이 지정되었는지 확인
+func isInlineFile(args []byte) bool {
+	return parseTransferArgs(string(args))["inline"] == "1"
+}
+
+// parseTransferArgs key=value;key=value 형식의 인자 해석
+func parseTransferArgs(s string) map[string]string {
+	args := map[string]string{}
+	for _, kv := range strings.Split(s, ";") {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			args[k] = v
+		}
+	}
+	return args
+}
+
+// isBase64 base64 문자 여부
+func isBase64(b byte) bool {
+	return b >= 'A' && b <= 'Z' || b >= 'a' && b <= 'z' || b >= '0' && b <= '9' || b == '+' || b == '/' || b == '='
+}
+
+// TransferMaxSize 터미널 파일 전송 최대 크기 (바이트, 0이면 파일 전송 비활성화)
+func TransferMaxSize() int {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	return mgr.transferMax
+}
+
+// loadTransferMaxSize 설정 파일의 터미널 파일 전송 최대 크기 로드
+func loadTransferMaxSize() int {
+	conf := config.Conf.Terminal
+	if !conf.FileTransfer {
+		return 0
+	}
+	if conf.TransferMaxSize <= 0 {
+		return defaultTransferMaxMB << 20
+	}
+	return conf.TransferMaxSize << 20
+}
+
+// handleTransfer PTY 출력에서 찾은 파일 전송 요청을 세션 소유자에게 전달 (s.mu 잠금 상태에서 호출)
+// 소유자만 파일을 주고받을 수 있으며, 소유자가 연결되어 있지 않으면 거부
+func (s *Session) handleTransfer(req transferRequest) {
+	if req.upload {
+		if s.owner == nil || s.uploading || req.err != nil {
+			go s.answerUpload([]byte("abort\n"))
+			return
+		}
+		s.uploadPending = true
+		s.owner.Notify(Event{Type: EventUploadRequest})
+		return
+	}
+
+	name := req.name()
+	switch {
+	case req.err != nil:
+		if s.owner != nil {
+			s.owner.Notify(Event{Type: EventTransferError, Name: name, Reason: req.err.Error()})
+		}
+		s.logTransfer("Terminal file download refused", name, 0, req.err)
+	case s.owner == nil:
+		s.logTransfer("Terminal file download refused", name, 0, errors.New("owner is not connected"))
+	default:
+		size := base64.StdEncoding.DecodedLen(len(req.data)) - bytes.Count(req.data, []byte("="))
+		s.owner.Notify(Event{Type: EventDownload, Name: name, Data: string(req.data)})
+		s.logTransfer("Terminal file download", name, size, nil)
+	}
+}
+
+// Upload 업로드 요청에 대한 응답으로 소유자가 선택한 파일(base64 인코딩된 tar.gz)을 쉘 입력으로 전달
+func (s *Session) Upload(cl Client, name, data string) error {
+	payload := []byte(data)
+	s.mu.Lock()
+	if s.owner != cl {
+		s.mu.Unlock()
+		return ErrNotOwner
+	}
+	if !s.uploadPending {
+		s.mu.Unlock()
+		return ErrNoUpload
+	}
+	s.uploadPending = false
+
+	size, err := base64.StdEncoding.Decode(make([]byte, base64.StdEncoding.DecodedLen(len(payload))), payload)
+	switch {
+	case err != nil:
+		err = ErrTransferInvalid
+	case size > TransferMaxSize():
+		err = ErrTransferTooLarge
+	}
+	if err != nil {
+		s.mu.Unlock()
+		go s.answerUpload([]byte("abort\n"))
+		s.logTransfer("Terminal file upload refused", name, size, err)
+		return err
+	}
+
+	// 전달하는 동안 다른 입력이 섞이지 않도록 키 입력 차단
+	s.uploading = true
+	s.mu.Unlock()
+	s.logTransfer("Terminal file upload", name, size, nil)
+
+	go func() {
+		var buf bytes.Buffer
+		buf.WriteString("ok\n")
+		for len(payload) > 0 {
+			n := min(len(payload), uploadLineLen)
+			buf.Write(payload[:n])
+			buf.WriteByte('\n')
+			payload = payload[n:]
+		}
+		buf.WriteByte('\n')
+		err := s.answerUpload(buf.Bytes())
+
+		s.mu.Lock()
+		s.uploading = false
+		if err != nil && s.owner != nil {
+			s.owner.Notify(Event{Type: EventTransferError, Name: name, Reason: err.Error()})
+		}
+		s.mu.Unlock()
+	}()
+	return nil
+}
+
+// CancelUpload 소유자가 업로드 요청을 거절
+func (s *Session) CancelUpload(cl Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.owner != cl {
+		return ErrNotOwner
+	}
+	if !s.uploadPending {
+		return ErrNoUpload
+	}
+	s.uploadPending = false
+	go s.answerUpload([]byte("abort\n"))
+	return nil
+}
+
+// rejectUpload 소유자 연결이 끊기면 응답하지 않은 업로드 요청 거절 (s.mu 잠금 상태에서 호출)
+func (s *Session) rejectUpload() {
+	if s.uploadPending {
+		s.uploadPending = false
+		go s.answerUpload([]byte("abort\n"))
+	}
+}
+
+// answerUpload 업로드 요청에 대한 응답을 쉘 입력으로 전달
+// 출력에 요청 시퀀스가 포함된 것만으로는 응답하지 않고, 쉘이 아닌 포그라운드 프로그램이
+// 에코를 끈 정규 모드로 입력을 기다리는 경우(it2ul의 stty -echo 후 read)에만 전달
+func (s *Session) answerUpload(data []byte) error {
+	deadline := time.Now().Add(uploadHandshakeTimeout)
+	for !s.uploadHelperWaiting() {
+		if time.Now().After(deadline) {
+			logger.PTY.Warnw("Dropped terminal upload answer without a waiting helper",
+				"id", s.ID, "user", s.Username, "size", len(data))
+			return ErrNoUploadHelper
+		}
+		time.Sleep(uploadHandshakeInterval)
+	}
+
+	if _, err := s.ptmx.Write(data); err != nil {
+		logger.PTY.Warnw("Failed to write terminal upload answer", "id", s.ID, "err", err)
+		return err
+	}
+	return nil
+}
+
+// uploadHelperWaiting 업로드 도우미 프로그램이 응답 입력을 기다리는 상태인지 확인
+// (포그라운드 프로세스 그룹이 쉘이 아니고, 터미널이 에코 없는 정규 모드)
+func (s *Session) uploadHelperWaiting() bool {
+	pgrp, err := s.foregroundGroup()
+	if err != nil || pgrp <= 1 || s.cmd == nil || pgrp == s.cmd.Process.Pid {
+		return false
+	}
+
+	rc, err := s.ptmx.SyscallConn()
+	if err != nil {
+		return false
+	}
+	var termios *unix.Termios
+	var ioctlErr error
+	if err := rc.Control(func(fd uintptr) {
+		termios, ioctlErr = unix.IoctlGetTermios(int(fd), unix.TCGETS)
+	}); err != nil || ioctlErr != nil {
+		return false
+	}
+	return termios.Lflag&unix.ICANON != 0 && termios.Lflag&unix.ECHO == 0
+}
+
+// logTransfer 파일 전송을 로그와 녹화 파일에 기록
+func (s *Session) logTransfer(msg, name string, size int, err error) {
+	if err != nil {
+		logger.PTY.Warnw(msg,
+			"id", s.ID, "user", s.Username, "unixUser", s.UnixUser, "ip", s.ClientIP, "name", name, "size", size, "err", err)
+		return
+	}
+	logger.PTY.Infow(msg, "id", s.ID, "user", s.Username, "unixUser", s.UnixUser, "ip", s.ClientIP, "name", name, "size", size)
+	s.rec.writeMarker(msg + ": " + name + " (" + strconv.Itoa(size) + " bytes)")
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// scanChunks 출력을 작은 조각으로 나누어 스캐너에 전달하고 통과한 출력과 찾은 요청 반환
+func scanChunks(data string, chunk int) (string, []transferRequest) {
+	t := newTransferScanner(1 << 20)
+	var out []byte
+	var found []transferRequest
+	for p := []byte(data); len(p) > 0; {
+		n := min(len(p), chunk)
+		out = t.filter(p[:n], out, func(req transferRequest) { found = append(found, req) })
+		p = p[n:]
+	}
+	return string(out), found
+}
+
+func TestTransferScannerInlineImage(t *testing.T) {
+	inlineBEL := "\x1b]1337;File=name=YS5wbmc=;inline=1:aGVsbG8=\x07"
+	inlineST := "\x1b]1337;File=inline=1;size=5:aGVsbG8=\x1b\\"
+	download := "\x1b]1337;File=name=YS50eHQ=;size=5:aGVsbG8=\x07"
+
+	for _, chunk := range []int{1, 3, 1024} {
+		out, found := scanChunks("a"+inlineBEL+"b"+download+"c"+inlineST+"d", chunk)
+		if want := "a" + inlineBEL + "b" + "c" + inlineST + "d"; out != want {
+			t.Errorf("chunk %d: output = %q, want %q", chunk, out, want)
+		}
+		if len(found) != 1 || found[0].upload || found[0].name() != "a.txt" {
+			t.Errorf("chunk %d: found %+v, want one download of a.txt", chunk, found)
+		}
+	}
+}
+
+func TestUploadAnswerRequiresHelper(t *testing.T) {
+	s, tty, _ := newSignalTestSession(t)
+
+	// 쉘 자신이 에코를 끄고 입력을 읽는 동안은 도우미로 보지 않음
+	// 줄을 입력하면 별도 작업(프로세스 그룹)으로 실행한 도우미가 stty -echo 후 입력을 기다림
+	// (마지막 명령은 bash가 exec로 대체하지 않도록 true 추가)
+	script := `stty -echo; read y; sh -c 'stty -echo; read x; echo "got:$x"; sleep 30'; true`
+	s.cmd = exec.Command("bash", "-m", "-c", script)
+	s.cmd.Stdin, s.cmd.Stdout, s.cmd.Stderr = tty, tty, tty
+	s.cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	if err := s.cmd.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() {
+		if pgrp, _ := s.foregroundGroup(); pgrp > 1 {
+			syscall.Kill(-pgrp, syscall.SIGKILL)
+		}
+		syscall.Kill(-s.cmd.Process.Pid, syscall.SIGKILL)
+		s.cmd.Process.Kill()
+		s.cmd.Wait()
+	})
+
+	time.Sleep(300 * time.Millisecond)
+	if s.uploadHelperWaiting() {
+		t.Fatal("shell itself was treated as an upload helper")
+	}
+	s.ptmx.Write([]byte("\n"))
+
+	if err := s.answerUpload([]byte("ok\n")); err != nil {
+		t.Fatalf("answerUpload with waiting helper: %v", err)
+	}
+
+	var out bytes.Buffer
+	buf := make([]byte, 256)
+	deadline := time.Now().Add(3 * time.Second)
+	for !bytes.Contains(out.Bytes(), []byte("got:ok")) {
+		if time.Now().After(deadline) {
+			t.Fatalf("helper did not receive the answer, output %q", out.String())
+		}
+		s.ptmx.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+		n, _ := s.ptmx.Read(buf)
+		out.Write(buf[:n])
+	}
+}
+
+func TestUploadAnswerWithoutHelper(t *testing.T) {
+	s, _, _ := newSignalTestSession(t)
+
+	if err := s.answerUpload([]byte("abort\n")); !errors.Is(err, ErrNoUploadHelper) {
+		t.Errorf("answerUpload without a helper = %v, want ErrNoUploadHelper", err)
+	}
+}
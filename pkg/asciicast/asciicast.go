@@ -0,0 +1,271 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// 이벤트 종류 (asciicast v2)
+const (
+	EventOutput = "o"
+	EventInput  = "i"
+	EventResize = "r"
+	EventMarker = "m"
+)
+
+// Header asciicast v2 헤더 (파일 첫 줄)
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event asciicast v2 이벤트 ([time, type, data])
+type Event struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// MarshalJSON 이벤트를 배열 형식으로 인코딩
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.Time, e.Type, e.Data})
+}
+
+// UnmarshalJSON 배열 형식의 이벤트 디코딩
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw) != 3 {
+		return fmt.Errorf("invalid event length (%d)", len(raw))
+	}
+	if err := json.Unmarshal(raw[0], &e.Time); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw[1], &e.Type); err != nil {
+		return err
+	}
+	return json.Unmarshal(raw[2], &e.Data)
+}
+
+// Writer asciicast v2 파일 기록기
+type Writer struct {
+	mu      sync.Mutex
+	file    *os.File
+	start   time.Time
+	pending map[string][]byte // 스트림별로 잘린 UTF-8 바이트 보관
+	closed  bool
+}
+
+// NewWriter 녹화 파일을 생성하고 헤더 기록
+func NewWriter(path string, header Header) (*Writer, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	header.Version = 2
+	if header.Timestamp == 0 {
+		header.Timestamp = now.Unix()
+	}
+
+	data, err := json.Marshal(header)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &Writer{
+		file:    file,
+		start:   now,
+		pending: make(map[string][]byte),
+	}, nil
+}
+
+// WriteOutput 출력 이벤트 기록
+func (w *Writer) WriteOutput(data []byte) error {
+	return w.writeStream(EventOutput, data)
+}
+
+// WriteInput 입력 이벤트 기록
+func (w *Writer) WriteInput(data []byte) error {
+	return w.writeStream(EventInput, data)
+}
+
+// WriteResize 터미널 크기 변경 이벤트 기록
+func (w *Writer) WriteResize(cols, rows int) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.writeEvent(EventResize, strconv.Itoa(cols)+"x"+strconv.Itoa(rows))
+}
+
+// WriteMarker 마커 이벤트 기록
+func (w *Writer) WriteMarker(label string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+	return w.writeEvent(EventMarker, label)
+}
+
+// Close 남은 데이터를 기록하고 파일 닫기
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+
+	// 잘린 채로 남아있는 바이트는 그대로 기록 (JSON 인코딩 시 대체 문자로 변환됨)
+	for typ, rest := range w.pending {
+		if len(rest) > 0 {
+			w.writeEvent(typ, string(rest))
+		}
+	}
+	w.closed = true
+
+	return w.file.Close()
+}
+
+// writeStream 멀티바이트 문자가 잘리지 않도록 완성된 UTF-8 시퀀스까지만 기록
+func (w *Writer) writeStream(typ string, data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return os.ErrClosed
+	}
+
+	buf := append(w.pending[typ], data...)
+	cut := completeUTF8Len(buf)
+	w.pending[typ] = append([]byte(nil), buf[cut:]...)
+	if cut == 0 {
+		return nil
+	}
+
+	return w.writeEvent(typ, string(buf[:cut]))
+}
+
+// writeEvent 이벤트 한 줄 기록 (호출 전 잠금 필요)
+func (w *Writer) writeEvent(typ, data string) error {
+	line, err := json.Marshal(Event{
+		Time: time.Since(w.start).Seconds(),
+		Type: typ,
+		Data: data,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.file.Write(append(line, '\n'))
+	return err
+}
+
+// completeUTF8Len 끝부분의 미완성 UTF-8 시퀀스를 제외한 길이 반환
+func completeUTF8Len(b []byte) int {
+	// UTF-8 문자는 최대 4바이트이므로 마지막 3바이트까지만 검사
+	for i := 1; i <= 3 && i <= len(b); i++ {
+		c := b[len(b)-i]
+		if c < 0x80 {
+			return len(b)
+		}
+		if utf8.RuneStart(c) {
+			if utf8.FullRune(b[len(b)-i:]) {
+				return len(b)
+			}
+			return len(b) - i
+		}
+	}
+	return len(b)
+}
+
+// Reader asciicast v2 파일 판독기
+type Reader struct {
+	Header  Header
+	scanner *bufio.Scanner
+}
+
+// NewReader 헤더를 읽고 판독기 생성
+func NewReader(r io.Reader) (*Reader, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	var header Header
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return nil, err
+	}
+	if header.Version != 2 {
+		return nil, fmt.Errorf("unsupported asciicast version (%d)", header.Version)
+	}
+
+	return &Reader{Header: header, scanner: scanner}, nil
+}
+
+// Next 다음 이벤트 읽기 (더 이상 없으면 io.EOF 반환)
+func (r *Reader) Next() (Event, error) {
+	var evt Event
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(line, &evt); err != nil {
+			return evt, err
+		}
+		return evt, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return evt, err
+	}
+	return evt, io.EOF
+}
+
+// ParseResize 크기 변경 이벤트 데이터("COLSxROWS") 파싱
+func ParseResize(data string) (cols, rows int, err error) {
+	_, err = fmt.Sscanf(data, "%dx%d", &cols, &rows)
+	return cols, rows, err
+}
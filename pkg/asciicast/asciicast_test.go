@@ -0,0 +1,276 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package asciicast
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompleteUTF8Len(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"가", 3},
+		{"a가"[:2], 1},           // 3바이트 문자의 첫 바이트만
+		{"a가"[:3], 1},           // 3바이트 문자의 앞 2바이트
+		{"a가", 4},               // 완성된 문자
+		{"😀"[:3], 0},            // 4바이트 문자의 앞 3바이트
+		{"x😀", 5},               // 완성된 4바이트 문자
+		{"é"[:1], 0},            // 2바이트 문자의 첫 바이트만
+		{"ab\x80", 3},           // 시작 바이트 없는 연속 바이트는 그대로 기록
+		{"\x80\x80\x80\x80", 4}, // 잘못된 시퀀스도 무한히 보관하지 않음
+	}
+	for _, tt := range tests {
+		if got := completeUTF8Len([]byte(tt.in)); got != tt.want {
+			t.Errorf("completeUTF8Len(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEventJSON(t *testing.T) {
+	in := Event{Time: 1.5, Type: EventOutput, Data: "héllo\r\n\x1b[0m"}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if !strings.HasPrefix(string(data), `[1.5,"o","h`) {
+		t.Errorf("Marshal = %s, want array form", data)
+	}
+
+	var out Event
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if out != in {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+
+	for _, bad := range []string{`{"time":1}`, `[1,"o"]`, `[1,"o","x","y"]`, `["1","o","x"]`, `[1,2,"x"]`, `[1,"o",3]`} {
+		var e Event
+		if err := json.Unmarshal([]byte(bad), &e); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded, want error", bad)
+		}
+	}
+}
+
+// recordTest 기록기로 녹화 파일을 만들고 경로 반환
+func recordTest(t *testing.T, header Header, write func(w *Writer)) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "test.cast")
+	w, err := NewWriter(path, header)
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	write(w)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return path
+}
+
+// readAll 녹화 파일의 헤더와 모든 이벤트 읽기
+func readAll(t *testing.T, path string) (Header, []Event) {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+
+	r, err := NewReader(f)
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	var events []Event
+	for {
+		evt, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		events = append(events, evt)
+	}
+	return r.Header, events
+}
+
+func TestWriterReaderRoundTrip(t *testing.T) {
+	header := Header{Width: 80, Height: 24, Title: "test", Env: map[string]string{"TERM": "xterm-256color"}}
+	path := recordTest(t, header, func(w *Writer) {
+		w.WriteOutput([]byte("$ "))
+		w.WriteInput([]byte("ls\r"))
+		w.WriteResize(132, 43)
+		w.WriteMarker("upload")
+	})
+
+	got, events := readAll(t, path)
+	if got.Version != 2 || got.Width != 80 || got.Height != 24 || got.Title != "test" || got.Env["TERM"] != "xterm-256color" {
+		t.Errorf("header = %+v", got)
+	}
+	if got.Timestamp == 0 {
+		t.Error("header timestamp not set")
+	}
+
+	want := []Event{
+		{Type: EventOutput, Data: "$ "},
+		{Type: EventInput, Data: "ls\r"},
+		{Type: EventResize, Data: "132x43"},
+		{Type: EventMarker, Data: "upload"},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("events = %+v, want %d events", events, len(want))
+	}
+	prev := 0.0
+	for i, evt := range events {
+		if evt.Type != want[i].Type || evt.Data != want[i].Data {
+			t.Errorf("event %d = %+v, want type=%q data=%q", i, evt, want[i].Type, want[i].Data)
+		}
+		if evt.Time < prev {
+			t.Errorf("event %d time %v before previous %v", i, evt.Time, prev)
+		}
+		prev = evt.Time
+	}
+
+	cols, rows, err := ParseResize(events[2].Data)
+	if err != nil || cols != 132 || rows != 43 {
+		t.Errorf("ParseResize(%q) = %d, %d, %v", events[2].Data, cols, rows, err)
+	}
+}
+
+func TestWriterSplitMultibyte(t *testing.T) {
+	out := []byte("한글 😀 출력")
+	in := []byte("입력")
+	path := recordTest(t, Header{Width: 80, Height: 24}, func(w *Writer) {
+		// 한 바이트씩 나눠 쓰고 출력과 입력을 교차해도 스트림별로 문자가 보존되어야 함
+		for i := range out {
+			w.WriteOutput(out[i : i+1])
+			if i < len(in) {
+				w.WriteInput(in[i : i+1])
+			}
+		}
+	})
+
+	_, events := readAll(t, path)
+	var gotOut, gotIn strings.Builder
+	for _, evt := range events {
+		if strings.ContainsRune(evt.Data, '\uFFFD') {
+			t.Errorf("event %+v contains a replacement character", evt)
+		}
+		switch evt.Type {
+		case EventOutput:
+			gotOut.WriteString(evt.Data)
+		case EventInput:
+			gotIn.WriteString(evt.Data)
+		}
+	}
+	if gotOut.String() != string(out) {
+		t.Errorf("output = %q, want %q", gotOut.String(), out)
+	}
+	if gotIn.String() != string(in) {
+		t.Errorf("input = %q, want %q", gotIn.String(), in)
+	}
+}
+
+func TestWriterFlushesPendingOnClose(t *testing.T) {
+	path := recordTest(t, Header{Width: 80, Height: 24}, func(w *Writer) {
+		w.WriteOutput([]byte("ok" + "가"[:2]))
+	})
+
+	_, events := readAll(t, path)
+	// 잘린 바이트는 JSON 인코딩 시 바이트마다 대체 문자로 변환됨
+	if len(events) != 2 || events[0].Data != "ok" || events[1].Data != "\uFFFD\uFFFD" {
+		t.Errorf("events = %+v, want \"ok\" and the truncated rest as replacement characters", events)
+	}
+}
+
+func TestWriterClosed(t *testing.T) {
+	w, err := NewWriter(filepath.Join(t.TempDir(), "test.cast"), Header{Width: 80, Height: 24})
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	w.Close()
+
+	if err := w.WriteOutput([]byte("x")); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("WriteOutput after Close = %v, want %v", err, os.ErrClosed)
+	}
+	if err := w.WriteResize(1, 1); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("WriteResize after Close = %v, want %v", err, os.ErrClosed)
+	}
+	if err := w.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+}
+
+func TestReaderMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"bad header", "not json\n"},
+		{"unsupported version", `{"version":1,"width":80,"height":24}` + "\n"},
+	}
+	for _, tt := range tests {
+		if _, err := NewReader(strings.NewReader(tt.data)); err == nil {
+			t.Errorf("%s: NewReader succeeded, want error", tt.name)
+		}
+	}
+
+	// 빈 줄은 건너뛰고, 잘못된 이벤트 줄에서 오류 반환
+	data := `{"version":2,"width":80,"height":24}` + "\n\n" +
+		`[0.1,"o","a"]` + "\n" +
+		`[0.2,"o"]` + "\n" +
+		`{"t":1}` + "\n"
+	r, err := NewReader(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewReader: %v", err)
+	}
+	if evt, err := r.Next(); err != nil || evt.Data != "a" {
+		t.Fatalf("first Next = %+v, %v", evt, err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := r.Next(); err == nil || errors.Is(err, io.EOF) {
+			t.Errorf("Next on malformed line %d = %v, want decode error", i, err)
+		}
+	}
+	if _, err := r.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Next at end = %v, want io.EOF", err)
+	}
+}
+
+func TestParseResize(t *testing.T) {
+	if cols, rows, err := ParseResize("120x40"); err != nil || cols != 120 || rows != 40 {
+		t.Errorf("ParseResize(120x40) = %d, %d, %v", cols, rows, err)
+	}
+	for _, bad := range []string{"", "120", "x40", "abcxdef"} {
+		if _, _, err := ParseResize(bad); err == nil {
+			t.Errorf("ParseResize(%q) succeeded, want error", bad)
+		}
+	}
+}
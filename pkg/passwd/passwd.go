@@ -0,0 +1,120 @@
+// Copyright 2025 JongHoon Shim
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package passwd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+)
+
+// PasswdFilePath 계정 정보 파일 경로
+var PasswdFilePath = "/etc/passwd"
+
+// Account 리눅스 계정 정보
+type Account struct {
+	Name   string
+	Uid    uint32
+	Gid    uint32
+	Groups []uint32 // 보조 그룹 (기본 그룹 포함)
+	Home   string
+	Shell  string
+}
+
+// IsRoot 루트(uid 0) 계정 여부
+func (a *Account) IsRoot() bool {
+	return a.Uid == 0
+}
+
+// Lookup 계정명으로 /etc/passwd 항목과 보조 그룹 조회
+func Lookup(name string) (*Account, error) {
+	if name == "" || strings.ContainsAny(name, ":\n") {
+		return nil, fmt.Errorf("invalid account name (%q)", name)
+	}
+
+	acc, err := lookupPasswd(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// 보조 그룹 조회
+	u, err := user.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	gids, err := u.GroupIds()
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range gids {
+		gid, err := strconv.ParseUint(g, 10, 32)
+		if err != nil {
+			continue
+		}
+		acc.Groups = append(acc.Groups, uint32(gid))
+	}
+
+	return acc, nil
+}
+
+// lookupPasswd /etc/passwd에서 계정 항목 파싱
+// 형식: name:password:uid:gid:gecos:home:shell
+func lookupPasswd(name string) (*Account, error) {
+	file, err := os.Open(PasswdFilePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, ":")
+		if len(fields) != 7 || fields[0] != name {
+			continue
+		}
+
+		uid, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid uid for %s: %v", name, err)
+		}
+		gid, err := strconv.ParseUint(fields[3], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gid for %s: %v", name, err)
+		}
+
+		return &Account{
+			Name:  name,
+			Uid:   uint32(uid),
+			Gid:   uint32(gid),
+			Home:  fields[5],
+			Shell: fields[6],
+		}, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("account does not exist (%s)", name)
+}
@@ -19,6 +19,7 @@ package proc
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"syscall"
 )
@@ -60,3 +61,32 @@ func SendSignal(pid int, sig syscall.Signal) error {
 
 	return nil
 }
+
+// GetProcUids PID로부터 프로세스의 real, effective, saved, filesystem UID 추출
+func GetProcUids(pid int) ([4]uint32, error) {
+	var uids [4]uint32
+
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return uids, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "Uid:") {
+			continue
+		}
+		fields := strings.Fields(strings.TrimPrefix(line, "Uid:"))
+		if len(fields) != len(uids) {
+			break
+		}
+		for i, f := range fields {
+			uid, err := strconv.ParseUint(f, 10, 32)
+			if err != nil {
+				return uids, err
+			}
+			uids[i] = uint32(uid)
+		}
+		return uids, nil
+	}
+	return uids, fmt.Errorf("uid not found in /proc/%d/status", pid)
+}
@@ -20,7 +20,9 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -39,6 +41,15 @@ type taskUnit struct {
 	childCancel context.CancelFunc
 	task        func(ctx context.Context)
 	isRun       bool
+	running     atomic.Bool  // 작업 함수가 실제로 실행 중인지 여부 (작업이 스스로 반환하면 false)
+	panics      atomic.Int64 // 패닉으로 종료된 횟수
+}
+
+// TaskState 작업 상태
+type TaskState struct {
+	Name    string
+	Running bool  // 작업 함수가 실행 중인지 여부
+	Panics  int64 // 패닉으로 종료된 횟수
 }
 
 // NewTaskManager 작업 관리자 생성
@@ -104,18 +115,21 @@ func (tm *TaskManager) RunAll() {
 		t.childWG.Add(1)
 
 		t.isRun = true
+		t.running.Store(true)
 		tmpTask := t
 
 		go func(tu *taskUnit) {
 			defer func() {
 				// 패닉 발생 시 핸들러 설정
 				if err := recover(); err != nil {
+					tu.panics.Add(1)
 					if tm.panicHandler != nil {
 						tm.panicHandler(err)
 					} else {
 						tm.defPanicHandler(err)
 					}
 				}
+				tu.running.Store(false)
 				tu.childWG.Done()
 				tm.parentWG.Done()
 			}()
@@ -150,16 +164,19 @@ func (tm *TaskManager) Run(name string) error {
 	t.childWG.Add(1)
 
 	t.isRun = true
+	t.running.Store(true)
 
 	go func(tu *taskUnit) {
 		defer func() {
 			if err := recover(); err != nil {
+				tu.panics.Add(1)
 				if tm.panicHandler != nil {
 					tm.panicHandler(err)
 				} else {
 					tm.defPanicHandler(err)
 				}
 			}
+			tu.running.Store(false)
 			tu.childWG.Done()
 			tm.parentWG.Done()
 		}()
@@ -213,6 +230,19 @@ func (tm *TaskManager) Shutdown(name string, timeout time.Duration) error {
 	return nil
 }
 
+// States 등록된 작업들의 현재 상태 (이름순)
+func (tm *TaskManager) States() []TaskState {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	states := make([]TaskState, 0, len(tm.tasks))
+	for name, t := range tm.tasks {
+		states = append(states, TaskState{Name: name, Running: t.running.Load(), Panics: t.panics.Load()})
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
 // defPanicHandler 기본 패닉 에러 핸들러
 func (tm *TaskManager) defPanicHandler(err interface{}) {
 	fmt.Fprintf(os.Stderr, "panic occurred: %v\n", err)